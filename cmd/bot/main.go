@@ -3,16 +3,22 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/sirupsen/logrus"
 
+	"xui-tg-admin/internal/commands"
 	"xui-tg-admin/internal/config"
 	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/health"
+	"xui-tg-admin/internal/httpserver"
+	"xui-tg-admin/internal/metrics"
 	"xui-tg-admin/internal/permissions"
 	"xui-tg-admin/internal/services"
+	"xui-tg-admin/internal/version"
 	"xui-tg-admin/pkg/telegrambot"
 )
 
@@ -21,22 +27,32 @@ func main() {
 	logger := setupLogger()
 
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(logger)
 	if err != nil {
 		logger.Fatal("Failed to load configuration:", err)
 	}
 
+	// Apply custom button labels before any keyboard is built
+	if err := commands.SetLabels(cfg.CommandLabels); err != nil {
+		logger.Fatal("Invalid command labels:", err)
+	}
+
 	// Initialize services
 	stateService := services.NewUserStateService(logger)
 	xrayService := services.NewXrayService(cfg, logger)
-	qrService := services.NewQRService(logger)
-	storageService := services.NewStorageService("data.json", logger)
+	qrService := services.NewQRService(cfg, logger)
+	chartService := services.NewChartService(logger)
+	storageService, err := services.NewStorageService(cfg.StoragePath, cfg.StorageKey, cfg.StoragePreviousKeys, logger)
+	if err != nil {
+		logger.Fatal("Failed to open storage:", err)
+	}
+	defer storageService.Close()
 
 	// Setup permission controller
 	permController := permissions.NewController(cfg.Telegram.AdminIDs, storageService, logger)
 
 	// Initialize bot
-	bot, err := telegrambot.NewBot(cfg, stateService, xrayService, qrService, storageService, permController, logger)
+	bot, err := telegrambot.NewBot(cfg, stateService, xrayService, qrService, storageService, chartService, permController, logger)
 	if err != nil {
 		logger.Fatal("Failed to create bot:", err)
 	}
@@ -54,13 +70,52 @@ func main() {
 		cancel()
 	}()
 
+	// Serve metrics and health probes alongside the bot when enabled
+	startHTTPServers(ctx, cfg, xrayService, logger)
+
 	// Start bot
-	logger.Info("Starting X-UI Telegram bot")
+	logger.WithFields(logrus.Fields{
+		"version":    version.Version,
+		"commit":     version.Commit,
+		"build_date": version.BuildDate,
+	}).Info("Starting X-UI Telegram bot")
 	if err := bot.Start(ctx); err != nil {
 		logger.Fatal("Bot failed:", err)
 	}
 }
 
+// startHTTPServers starts the enabled HTTP endpoints, sharing one server between
+// endpoints configured with the same address
+func startHTTPServers(ctx context.Context, cfg *config.Config, xrayService *services.XrayService, logger *logrus.Logger) {
+	muxes := make(map[string]*http.ServeMux)
+	muxFor := func(addr string) *http.ServeMux {
+		if muxes[addr] == nil {
+			muxes[addr] = http.NewServeMux()
+		}
+		return muxes[addr]
+	}
+
+	if cfg.MetricsAddr != "" {
+		muxFor(cfg.MetricsAddr).Handle("/metrics", metrics.Handler())
+	}
+
+	if cfg.HealthAddr != "" {
+		checker := health.NewChecker(func(ctx context.Context) error {
+			_, err := xrayService.Ping(ctx)
+			return err
+		})
+		checker.Register(muxFor(cfg.HealthAddr))
+	}
+
+	for addr, mux := range muxes {
+		go func(addr string, mux *http.ServeMux) {
+			if err := httpserver.Serve(ctx, addr, mux, logger); err != nil {
+				logger.Errorf("HTTP server on %s failed: %v", addr, err)
+			}
+		}(addr, mux)
+	}
+}
+
 // setupLogger sets up the logger
 func setupLogger() *logrus.Logger {
 	logger := logrus.New()
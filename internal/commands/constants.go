@@ -3,8 +3,21 @@ package commands
 // TelegramCommands contains all commands for the Telegram bot
 const (
 	// Main commands
-	Start  = "/start"
-	Cancel = "Cancel"
+	Start     = "/start"
+	Menu      = "/menu"
+	Ping      = "/ping"
+	WhoAmI    = "/whoami"
+	Support   = "/support"
+	Top       = "/top"
+	Inbounds  = "/inbounds"
+	Transfer  = "/transfer"
+	Reminders = "/reminders"
+	Version   = "/version"
+	As        = "/as"
+	Cancel    = "Cancel"
+
+	// CancelCommand aborts the current flow from any state
+	CancelCommand = "/cancel"
 
 	// Navigation commands
 	ReturnToMainMenu = "Return to Main Menu"
@@ -17,25 +30,51 @@ const (
 	NetworkUsage      = "Network Usage"
 	DetailedUsage     = "Detailed Usage"
 	ResetNetworkUsage = "Reset Network Usage"
+	DeleteExpired     = "Delete Expired"
+	BulkToggle        = "Bulk Enable/Disable"
+	Reconcile         = "Reconcile Accounts"
+	ExportUsageCSV    = "Export CSV"
+	TrafficChart      = "Traffic Chart"
+	TopUsers          = "Top Users"
+	Backup            = "Backup"
+	Restore           = "Restore"
+	HealthCheck       = "Health Check"
+	ToggleInbound     = "Toggle Inbound"
+	Broadcast         = "Broadcast"
 	AddTrusted        = "Add Trusted"
 	RevokeTrusted     = "Revoke Trusted"
+	AddAdmin          = "Add Admin"
+	RevokeAdmin       = "Revoke Admin"
 
 	// Member commands
 	CreateNewConfig = "Create New Config"
 	ViewConfigsInfo = "View Configs Info"
+	MyConfigs       = "My Configs"
+	MyUsage         = "My Usage"
 
 	// Demo user commands
 	About = "About"
 	Help  = "Help"
 
 	// Member action commands
-	ViewConfig   = "View Config"
-	ResetTraffic = "Reset Traffic"
-	Delete       = "Delete"
+	ViewConfig      = "View Config"
+	ResetTraffic    = "Reset Traffic"
+	Delete          = "Delete"
+	Rename          = "Rename"
+	ConnectionLinks = "Connection Links"
+	SetNote         = "Set Note"
+	ExportConfig    = "Export Config"
+	Tags            = "Tags"
+	TrafficLimit    = "Traffic Limit"
+	AllTags         = "All"
 
 	// Confirmation commands
 	Confirm = "Confirm"
 
 	// Duration options
 	Infinite = "Infinite"
+	Days     = "days"
 )
+
+// DurationPresets are the quick-pick durations in days offered when adding a member
+var DurationPresets = []int{7, 30, 90, 365}
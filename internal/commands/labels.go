@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// labelKeys maps the config keys for button labels to the commands they rename
+var labelKeys = map[string]string{
+	"return_to_main_menu": ReturnToMainMenu,
+	"cancel":              Cancel,
+	"confirm":             Confirm,
+	"infinite":            Infinite,
+	"days":                Days,
+	"add_member":          AddMember,
+	"edit_member":         EditMember,
+	"delete_member":       DeleteMember,
+	"my_configs":          MyConfigs,
+	"my_usage":            MyUsage,
+	"online_members":      OnlineMembers,
+	"detailed_usage":      DetailedUsage,
+	"reset_network_usage": ResetNetworkUsage,
+	"delete_expired":      DeleteExpired,
+	"bulk_toggle":         BulkToggle,
+	"reconcile":           Reconcile,
+	"export_csv":          ExportUsageCSV,
+	"traffic_chart":       TrafficChart,
+	"top_users":           TopUsers,
+	"backup":              Backup,
+	"restore":             Restore,
+	"health_check":        HealthCheck,
+	"toggle_inbound":      ToggleInbound,
+	"broadcast":           Broadcast,
+	"add_trusted":         AddTrusted,
+	"revoke_trusted":      RevokeTrusted,
+	"add_admin":           AddAdmin,
+	"revoke_admin":        RevokeAdmin,
+	"view_config":         ViewConfig,
+	"connection_links":    ConnectionLinks,
+	"rename":              Rename,
+	"reset_traffic":       ResetTraffic,
+	"set_note":            SetNote,
+	"tags":                Tags,
+	"all_tags":            AllTags,
+	"traffic_limit":       TrafficLimit,
+	"export_config":       ExportConfig,
+	"delete":              Delete,
+}
+
+// labels and commandsByLabel hold the configured overrides. They are set once at
+// startup by SetLabels and only read afterwards.
+var (
+	labels          = map[string]string{}
+	commandsByLabel = map[string]string{}
+)
+
+// SetLabels replaces the button labels of commands, keyed by config key (e.g. "add_member").
+// Unknown keys and labels used by two commands are rejected.
+func SetLabels(overrides map[string]string) error {
+	newLabels := make(map[string]string, len(overrides))
+	newCommands := make(map[string]string, len(overrides))
+
+	for key, label := range overrides {
+		command, ok := labelKeys[strings.ToLower(key)]
+		if !ok {
+			return fmt.Errorf("unknown command label key %q (valid keys: %s)", key, strings.Join(LabelKeys(), ", "))
+		}
+		label = strings.TrimSpace(label)
+		if label == "" || label == command {
+			continue
+		}
+		if other, taken := newCommands[label]; taken {
+			return fmt.Errorf("label %q is used for both %q and %q", label, other, command)
+		}
+		newLabels[command] = label
+		newCommands[label] = command
+	}
+
+	// A new label must not shadow another command that keeps its default label
+	for label, command := range newCommands {
+		for _, other := range labelKeys {
+			if other == label && other != command {
+				if _, renamed := newLabels[other]; !renamed {
+					return fmt.Errorf("label %q is already the label of %q", label, other)
+				}
+			}
+		}
+	}
+
+	labels = newLabels
+	commandsByLabel = newCommands
+	return nil
+}
+
+// LabelKeys returns the config keys of the commands whose labels can be changed
+func LabelKeys() []string {
+	keys := make([]string, 0, len(labelKeys))
+	for key := range labelKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Label returns the button label shown for a command
+func Label(command string) string {
+	if label, ok := labels[command]; ok {
+		return label
+	}
+	return command
+}
+
+// Resolve returns the command a button label stands for, or the text unchanged
+func Resolve(text string) string {
+	if command, ok := commandsByLabel[text]; ok {
+		return command
+	}
+	return text
+}
+
+// CommandForKey returns the command with the given config key, ignoring case, underscores
+// and dashes so that "add_member", "addmember" and "add-member" all match
+func CommandForKey(key string) (string, bool) {
+	normalize := strings.NewReplacer("_", "", "-", "")
+	key = normalize.Replace(strings.ToLower(key))
+	if key == "" {
+		return "", false
+	}
+
+	for labelKey, command := range labelKeys {
+		if normalize.Replace(labelKey) == key {
+			return command, true
+		}
+	}
+	return "", false
+}
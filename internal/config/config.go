@@ -1,22 +1,106 @@
 package config
 
+import (
+	"strconv"
+	"strings"
+
+	"xui-tg-admin/internal/i18n"
+)
+
 // Config represents the application configuration
 type Config struct {
-	Telegram TelegramConfig `mapstructure:"telegram"`
-	Server   ServerConfig   `mapstructure:"server"`
-	LogLevel string         `mapstructure:"log_level"`
+	Telegram    TelegramConfig `mapstructure:"telegram"`
+	Server      ServerConfig   `mapstructure:"server"`
+	QR          QRConfig       `mapstructure:"qr"`
+	Welcome     WelcomeConfig  `mapstructure:"welcome"`
+	LogLevel    string         `mapstructure:"log_level"`
+	Language    i18n.Language  `mapstructure:"lang"`
+	TrafficUnit string         `mapstructure:"traffic_unit"` // auto, MB, GB or TB
+	TopUsers    int            `mapstructure:"top_users"`    // users shown by the top users report
+	StoragePath string         `mapstructure:"storage_path"` // JSON file for trusted users, admins and accounts
+
+	StorageKey          string   `mapstructure:"storage_key"`           // passphrase encrypting the storage file, empty keeps it plain JSON
+	StoragePreviousKeys []string `mapstructure:"storage_previous_keys"` // old passphrases still accepted when loading, for key rotation
+
+	CommandLabels map[string]string `mapstructure:"command_labels"` // button label overrides keyed by command, e.g. add_member
+
+	MaxDurationDays int `mapstructure:"max_duration_days"` // longest subscription an admin can grant
+
+	TrustedDefaultDurationDays int `mapstructure:"trusted_default_duration_days"` // days accounts created by trusted users last, 0 never expires
+	ExpiryReminderDays         int `mapstructure:"expiry_reminder_days"`          // days before expiry account owners are reminded, 0 disables
+
+	PanelAlertThreshold int `mapstructure:"panel_alert_threshold"` // failures per operation before alerting admins, 0 disables
+	PanelAlertWindow    int `mapstructure:"panel_alert_window"`    // minutes over which failures are counted
+
+	MetricsAddr string `mapstructure:"metrics_addr"` // listen address of the Prometheus /metrics endpoint, empty disables it
+	HealthAddr  string `mapstructure:"health_addr"`  // listen address of the /healthz and /readyz probes, empty disables them
 }
 
 // TelegramConfig holds the Telegram bot configuration
 type TelegramConfig struct {
-	Token    string  `mapstructure:"token"`
-	AdminIDs []int64 `mapstructure:"admin_ids"`
+	Token            string  `mapstructure:"token"`
+	AdminIDs         []int64 `mapstructure:"admin_ids"`
+	ShutdownTimeout  int     `mapstructure:"shutdown_timeout"`  // seconds to wait for in-flight handlers
+	RateLimit        int     `mapstructure:"rate_limit"`        // updates per user per minute, 0 disables
+	RateLimitAdmins  bool    `mapstructure:"rate_limit_admins"` // apply the rate limit to admins too
+	ConfirmTimeout   int     `mapstructure:"confirm_timeout"`   // seconds a destructive confirmation stays valid
+	InlineMenu       bool    `mapstructure:"inline_menu"`       // show the admin main menu as inline buttons
+	LoadingAnimation bool    `mapstructure:"loading_animation"` // animate loading messages of long operations
+	SupportContact   string  `mapstructure:"support_contact"`   // @handle or text shown to users asking for help or access
+
+	WebhookURL    string `mapstructure:"webhook_url"`    // public HTTPS URL Telegram posts updates to, empty uses long polling
+	WebhookListen string `mapstructure:"webhook_listen"` // local address the webhook server listens on
+	WebhookCert   string `mapstructure:"webhook_cert"`   // TLS certificate path, uploaded to Telegram so self-signed ones work
+	WebhookKey    string `mapstructure:"webhook_key"`    // TLS private key path
 }
 
 // ServerConfig holds the configuration for an X-ray server
 type ServerConfig struct {
+	Name         string `mapstructure:"name"`
 	User         string `mapstructure:"user"`
 	Password     string `mapstructure:"password"`
 	APIURL       string `mapstructure:"api_url"`
 	SubURLPrefix string `mapstructure:"sub_url_prefix"`
+
+	VerifyClients bool `mapstructure:"verify_clients"` // re-read inbounds after adding clients to confirm they exist
+
+	InboundsInclude []string `mapstructure:"inbounds_include"` // IDs or remarks of the only inbounds new clients go to, empty allows all
+	InboundsExclude []string `mapstructure:"inbounds_exclude"` // IDs or remarks of inbounds new clients never go to
+}
+
+// TargetsInbound reports whether new clients are created on the inbound with the given ID
+// and remark: InboundsInclude must name it when set, and InboundsExclude must not. Entries
+// match the ID or, ignoring case, the remark.
+func (c ServerConfig) TargetsInbound(id int, remark string) bool {
+	if len(c.InboundsInclude) > 0 && !matchesInbound(c.InboundsInclude, id, remark) {
+		return false
+	}
+	return !matchesInbound(c.InboundsExclude, id, remark)
+}
+
+// matchesInbound reports whether any entry names the inbound by ID or remark
+func matchesInbound(entries []string, id int, remark string) bool {
+	idStr := strconv.Itoa(id)
+	for _, entry := range entries {
+		if entry == idStr || strings.EqualFold(entry, remark) {
+			return true
+		}
+	}
+	return false
+}
+
+// QRConfig holds the QR code generation settings
+type QRConfig struct {
+	Size          int    `mapstructure:"size"`           // image size in pixels
+	RecoveryLevel string `mapstructure:"recovery_level"` // low, medium, high or highest
+	Caption       string `mapstructure:"caption"`        // instructions added to QR captions, {username} and {server} are replaced
+	Label         bool   `mapstructure:"label"`          // print the server name under the QR image
+}
+
+// WelcomeConfig holds custom /start messages per access type, in Telegram HTML.
+// Empty messages fall back to the built-in localized text.
+type WelcomeConfig struct {
+	Admin   string `mapstructure:"admin"`
+	Trusted string `mapstructure:"trusted"`
+	None    string `mapstructure:"none"` // shown to users without access
 }
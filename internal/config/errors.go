@@ -0,0 +1,14 @@
+package config
+
+import "fmt"
+
+// ConfigError represents an invalid or missing configuration value
+type ConfigError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
@@ -3,19 +3,87 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/validation"
 )
 
-// Load loads the configuration from environment variables
-func Load() (*Config, error) {
+// fileKeys maps environment variables to their nested keys in the config file.
+// Top-level keys such as log_level and lang already match their variables.
+var fileKeys = map[string]string{
+	"TG_TOKEN":            "telegram.token",
+	"TG_ADMIN_IDS":        "telegram.admin_ids",
+	"SHUTDOWN_TIMEOUT":    "telegram.shutdown_timeout",
+	"RATE_LIMIT":          "telegram.rate_limit",
+	"RATE_LIMIT_ADMINS":   "telegram.rate_limit_admins",
+	"CONFIRM_TIMEOUT":     "telegram.confirm_timeout",
+	"INLINE_MENU":         "telegram.inline_menu",
+	"LOADING_ANIMATION":   "telegram.loading_animation",
+	"SUPPORT_CONTACT":     "telegram.support_contact",
+	"WEBHOOK_URL":         "telegram.webhook_url",
+	"WEBHOOK_LISTEN":      "telegram.webhook_listen",
+	"WEBHOOK_CERT":        "telegram.webhook_cert",
+	"WEBHOOK_KEY":         "telegram.webhook_key",
+	"XRAY_SERVER_NAME":    "server.name",
+	"XRAY_USER":           "server.user",
+	"XRAY_PASSWORD":       "server.password",
+	"XRAY_API_URL":        "server.api_url",
+	"XRAY_SUB_URL_PREFIX": "server.sub_url_prefix",
+	"VERIFY_CLIENTS":      "server.verify_clients",
+	"INBOUNDS_INCLUDE":    "server.inbounds_include",
+	"INBOUNDS_EXCLUDE":    "server.inbounds_exclude",
+	"QR_SIZE":             "qr.size",
+	"QR_RECOVERY_LEVEL":   "qr.recovery_level",
+	"QR_CAPTION":          "qr.caption",
+	"QR_LABEL":            "qr.label",
+	"WELCOME_ADMIN":       "welcome.admin",
+	"WELCOME_TRUSTED":     "welcome.trusted",
+	"WELCOME_NONE":        "welcome.none",
+}
+
+// Load loads the configuration from an optional config file and environment variables,
+// with environment variables taking precedence
+func Load(logger *logrus.Logger) (*Config, error) {
 	v := viper.New()
 	v.SetEnvPrefix("")
 	v.AutomaticEnv()
 
 	// Set default values
 	v.SetDefault("log_level", "info")
+	v.SetDefault("LANG", string(i18n.English))
+	v.SetDefault("QR_SIZE", constants.DefaultQRSize)
+	v.SetDefault("QR_RECOVERY_LEVEL", constants.DefaultQRRecoveryLevel)
+	v.SetDefault("QR_LABEL", false)
+	v.SetDefault("SHUTDOWN_TIMEOUT", constants.DefaultShutdownTimeout)
+	v.SetDefault("RATE_LIMIT", constants.DefaultRateLimit)
+	v.SetDefault("RATE_LIMIT_ADMINS", false)
+	v.SetDefault("CONFIRM_TIMEOUT", constants.DefaultConfirmTimeout)
+	v.SetDefault("INLINE_MENU", false)
+	v.SetDefault("LOADING_ANIMATION", true)
+	v.SetDefault("WEBHOOK_LISTEN", constants.DefaultWebhookListen)
+	v.SetDefault("VERIFY_CLIENTS", false)
+	v.SetDefault("TRAFFIC_UNIT", constants.DefaultTrafficUnit)
+	v.SetDefault("TOP_USERS", constants.DefaultTopUsers)
+	v.SetDefault("MAX_DURATION_DAYS", constants.DefaultMaxDurationDays)
+	v.SetDefault("EXPIRY_REMINDER_DAYS", constants.DefaultExpiryReminderDays)
+	v.SetDefault("PANEL_ALERT_THRESHOLD", constants.DefaultPanelAlertThreshold)
+	v.SetDefault("PANEL_ALERT_WINDOW", constants.DefaultPanelAlertWindow)
+	v.SetDefault("STORAGE_PATH", constants.DefaultStoragePath)
+
+	// Values from the config file replace the defaults but not the environment
+	if err := readConfigFile(v); err != nil {
+		return nil, err
+	}
 
 	// Define environment variables
 	v.BindEnv("TG_TOKEN")
@@ -24,45 +92,140 @@ func Load() (*Config, error) {
 	v.BindEnv("XRAY_PASSWORD")
 	v.BindEnv("XRAY_API_URL")
 	v.BindEnv("XRAY_SUB_URL_PREFIX")
+	v.BindEnv("XRAY_SERVER_NAME")
+	v.BindEnv("VERIFY_CLIENTS")
+	v.BindEnv("INBOUNDS_INCLUDE")
+	v.BindEnv("INBOUNDS_EXCLUDE")
+	v.BindEnv("SHUTDOWN_TIMEOUT")
+	v.BindEnv("RATE_LIMIT")
+	v.BindEnv("RATE_LIMIT_ADMINS")
+	v.BindEnv("CONFIRM_TIMEOUT")
+	v.BindEnv("INLINE_MENU")
+	v.BindEnv("LOADING_ANIMATION")
+	v.BindEnv("SUPPORT_CONTACT")
+	v.BindEnv("WEBHOOK_URL")
+	v.BindEnv("WEBHOOK_LISTEN")
+	v.BindEnv("WEBHOOK_CERT")
+	v.BindEnv("WEBHOOK_KEY")
+	v.BindEnv("LANG")
+	v.BindEnv("QR_SIZE")
+	v.BindEnv("QR_RECOVERY_LEVEL")
+	v.BindEnv("QR_CAPTION")
+	v.BindEnv("QR_LABEL")
+	v.BindEnv("TRAFFIC_UNIT")
+	v.BindEnv("TOP_USERS")
+	v.BindEnv("MAX_DURATION_DAYS")
+	v.BindEnv("TRUSTED_DEFAULT_DURATION_DAYS")
+	v.BindEnv("EXPIRY_REMINDER_DAYS")
+	v.BindEnv("PANEL_ALERT_THRESHOLD")
+	v.BindEnv("PANEL_ALERT_WINDOW")
+	v.BindEnv("STORAGE_PATH")
+	v.BindEnv("STORAGE_KEY")
+	v.BindEnv("STORAGE_PREVIOUS_KEYS")
+	v.BindEnv("WELCOME_ADMIN")
+	v.BindEnv("WELCOME_TRUSTED")
+	v.BindEnv("WELCOME_NONE")
+	v.BindEnv("COMMAND_LABELS")
+	v.BindEnv("METRICS_ADDR")
+	v.BindEnv("HEALTH_ADDR")
+
+	// Unsupported languages (e.g. a system LANG of "C.UTF-8") fall back to English
+	language, _ := i18n.ParseLanguage(v.GetString("LANG"))
 
 	// Create config instance
 	cfg := &Config{
-		LogLevel: v.GetString("log_level"),
+		LogLevel:    v.GetString("log_level"),
+		Language:    language,
+		TrafficUnit: strings.ToLower(strings.TrimSpace(v.GetString("TRAFFIC_UNIT"))),
+		TopUsers:    v.GetInt("TOP_USERS"),
+		StoragePath: strings.TrimSpace(v.GetString("STORAGE_PATH")),
+		StorageKey:  v.GetString("STORAGE_KEY"),
+
+		MaxDurationDays: v.GetInt("MAX_DURATION_DAYS"),
+
+		TrustedDefaultDurationDays: v.GetInt("TRUSTED_DEFAULT_DURATION_DAYS"),
+		ExpiryReminderDays:         v.GetInt("EXPIRY_REMINDER_DAYS"),
+
+		PanelAlertThreshold: v.GetInt("PANEL_ALERT_THRESHOLD"),
+		PanelAlertWindow:    v.GetInt("PANEL_ALERT_WINDOW"),
+
+		MetricsAddr: strings.TrimSpace(v.GetString("METRICS_ADDR")),
+		HealthAddr:  strings.TrimSpace(v.GetString("HEALTH_ADDR")),
 		Telegram: TelegramConfig{
-			Token: v.GetString("TG_TOKEN"),
+			Token:            v.GetString("TG_TOKEN"),
+			ShutdownTimeout:  v.GetInt("SHUTDOWN_TIMEOUT"),
+			RateLimit:        v.GetInt("RATE_LIMIT"),
+			RateLimitAdmins:  v.GetBool("RATE_LIMIT_ADMINS"),
+			ConfirmTimeout:   v.GetInt("CONFIRM_TIMEOUT"),
+			InlineMenu:       v.GetBool("INLINE_MENU"),
+			LoadingAnimation: v.GetBool("LOADING_ANIMATION"),
+			SupportContact:   strings.TrimSpace(v.GetString("SUPPORT_CONTACT")),
+			WebhookURL:       strings.TrimSpace(v.GetString("WEBHOOK_URL")),
+			WebhookListen:    strings.TrimSpace(v.GetString("WEBHOOK_LISTEN")),
+			WebhookCert:      strings.TrimSpace(v.GetString("WEBHOOK_CERT")),
+			WebhookKey:       strings.TrimSpace(v.GetString("WEBHOOK_KEY")),
+		},
+		Welcome: WelcomeConfig{
+			Admin:   strings.TrimSpace(v.GetString("WELCOME_ADMIN")),
+			Trusted: strings.TrimSpace(v.GetString("WELCOME_TRUSTED")),
+			None:    strings.TrimSpace(v.GetString("WELCOME_NONE")),
 		},
 	}
 
+	cfg.QR = QRConfig{
+		Size:          v.GetInt("QR_SIZE"),
+		RecoveryLevel: strings.ToLower(strings.TrimSpace(v.GetString("QR_RECOVERY_LEVEL"))),
+		Caption:       strings.TrimSpace(v.GetString("QR_CAPTION")),
+		Label:         v.GetBool("QR_LABEL"),
+	}
+
 	// Parse admin IDs
 	adminIDsStr := v.GetString("TG_ADMIN_IDS")
 	if adminIDsStr != "" {
-		adminIDsSlice := strings.Split(adminIDsStr, ",")
-		adminIDs := make([]int64, 0, len(adminIDsSlice))
-		for _, idStr := range adminIDsSlice {
-			var id int64
-			if _, err := fmt.Sscanf(strings.TrimSpace(idStr), "%d", &id); err == nil {
-				adminIDs = append(adminIDs, id)
-			}
+		adminIDs, invalid := parseAdminIDs(adminIDsStr)
+		for _, entry := range invalid {
+			logger.Warnf("Ignoring invalid TG_ADMIN_IDS entry %q", entry)
+		}
+		if len(adminIDs) == 0 {
+			return nil, &ConfigError{Field: "TG_ADMIN_IDS", Message: fmt.Sprintf("no valid Telegram IDs in %q", adminIDsStr)}
 		}
 		cfg.Telegram.AdminIDs = adminIDs
 	}
 
+	cfg.StoragePreviousKeys = parseList(v.Get("STORAGE_PREVIOUS_KEYS"))
+
 	// Parse server configuration
 	user := v.GetString("XRAY_USER")
 	password := v.GetString("XRAY_PASSWORD")
 	apiURL := v.GetString("XRAY_API_URL")
 	subURLPrefix := v.GetString("XRAY_SUB_URL_PREFIX")
+	serverName := strings.TrimSpace(v.GetString("XRAY_SERVER_NAME"))
 
 	if user == "" || password == "" || apiURL == "" {
 		return nil, errors.New("missing required server configuration")
 	}
 
+	// Labels from the environment override the same keys from the config file
+	cfg.CommandLabels = v.GetStringMapString("command_labels")
+	if cfg.CommandLabels == nil {
+		cfg.CommandLabels = make(map[string]string)
+	}
+	for key, label := range parseCommandLabels(v.GetString("COMMAND_LABELS")) {
+		cfg.CommandLabels[key] = label
+	}
+
 	// Create server configuration
 	cfg.Server = ServerConfig{
+		Name:         serverName,
 		User:         strings.TrimSpace(user),
 		Password:     strings.TrimSpace(password),
-		APIURL:       strings.TrimSpace(apiURL),
+		APIURL:       strings.TrimRight(strings.TrimSpace(apiURL), "/"),
 		SubURLPrefix: strings.TrimSpace(subURLPrefix),
+
+		VerifyClients: v.GetBool("VERIFY_CLIENTS"),
+
+		InboundsInclude: parseList(v.Get("INBOUNDS_INCLUDE")),
+		InboundsExclude: parseList(v.Get("INBOUNDS_EXCLUDE")),
 	}
 
 	// Validate configuration
@@ -70,9 +233,122 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	// Fall back to the panel host so QR captions always name the server
+	if cfg.Server.Name == "" {
+		if parsed, err := url.Parse(cfg.Server.APIURL); err == nil {
+			cfg.Server.Name = parsed.Hostname()
+		}
+	}
+
 	return cfg, nil
 }
 
+// parseAdminIDs parses a comma-separated list of Telegram IDs, returning the valid IDs
+// and the entries that are not positive integers. Empty entries and repeated IDs are skipped.
+func parseAdminIDs(raw string) ([]int64, []string) {
+	var ids []int64
+	var invalid []string
+	seen := make(map[int64]bool)
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(entry, 10, 64)
+		if err != nil || id <= 0 {
+			invalid = append(invalid, entry)
+			continue
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids, invalid
+}
+
+// parseCommandLabels parses "key=Label;key=Label" pairs. Semicolons separate the pairs
+// so labels may contain commas; entries without "=" are skipped.
+func parseCommandLabels(raw string) map[string]string {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(raw, ";") {
+		key, label, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		labels[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(label)
+	}
+	return labels
+}
+
+// parseList reads a list such as old storage keys from a config file list or a
+// comma-separated environment variable. Empty entries are skipped.
+func parseList(value interface{}) []string {
+	var entries []string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for _, item := range v {
+			entries = append(entries, fmt.Sprint(item))
+		}
+	default:
+		entries = strings.Split(fmt.Sprint(v), ",")
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			keys = append(keys, entry)
+		}
+	}
+	return keys
+}
+
+// readConfigFile reads CONFIG_FILE, or config.yaml/config.json from the working directory
+// or /etc/xui-tg-admin, and applies it below the environment. A missing file is not an error
+// unless CONFIG_FILE names it explicitly.
+func readConfigFile(v *viper.Viper) error {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		v.SetConfigFile(path)
+	} else {
+		v.SetConfigName("config")
+		v.AddConfigPath(".")
+		v.AddConfigPath("/etc/xui-tg-admin")
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	for envKey, fileKey := range fileKeys {
+		if !v.IsSet(fileKey) {
+			continue
+		}
+
+		value := v.Get(fileKey)
+		// Lists such as admin_ids are read back as comma-separated strings
+		if list, ok := value.([]interface{}); ok {
+			items := make([]string, 0, len(list))
+			for _, item := range list {
+				items = append(items, fmt.Sprint(item))
+			}
+			value = strings.Join(items, ",")
+		}
+		v.SetDefault(envKey, value)
+	}
+
+	return nil
+}
+
 // validateConfig validates the configuration
 func validateConfig(cfg *Config) error {
 	if cfg.Telegram.Token == "" {
@@ -83,6 +359,63 @@ func validateConfig(cfg *Config) error {
 		return errors.New("TG_ADMIN_IDS is required")
 	}
 
+	if cfg.Telegram.ShutdownTimeout < 0 {
+		return errors.New("SHUTDOWN_TIMEOUT must not be negative")
+	}
+
+	if cfg.Telegram.RateLimit < 0 {
+		return errors.New("RATE_LIMIT must not be negative")
+	}
+
+	if cfg.Telegram.ConfirmTimeout <= 0 {
+		return errors.New("CONFIRM_TIMEOUT must be positive")
+	}
+
+	if err := validateWebhook(cfg.Telegram); err != nil {
+		return err
+	}
+
+	if cfg.QR.Size < constants.MinQRSize || cfg.QR.Size > constants.MaxQRSize {
+		return &ConfigError{Field: "QR_SIZE", Message: fmt.Sprintf("must be between %d and %d", constants.MinQRSize, constants.MaxQRSize)}
+	}
+	switch cfg.QR.RecoveryLevel {
+	case "low", "medium", "high", "highest":
+	default:
+		return &ConfigError{Field: "QR_RECOVERY_LEVEL", Message: "must be one of low, medium, high, highest"}
+	}
+	if len([]rune(cfg.QR.Caption)) > constants.MaxQRCaptionLength {
+		return &ConfigError{Field: "QR_CAPTION", Message: fmt.Sprintf("must be at most %d characters", constants.MaxQRCaptionLength)}
+	}
+
+	switch cfg.TrafficUnit {
+	case "auto", "mb", "gb", "tb":
+	default:
+		return &ConfigError{Field: "TRAFFIC_UNIT", Message: "must be one of auto, MB, GB, TB"}
+	}
+
+	if cfg.TopUsers < 1 {
+		return errors.New("TOP_USERS must be positive")
+	}
+
+	if cfg.MaxDurationDays < 1 {
+		return errors.New("MAX_DURATION_DAYS must be positive")
+	}
+	if cfg.TrustedDefaultDurationDays != 0 {
+		if _, err := validation.ValidateDuration(strconv.Itoa(cfg.TrustedDefaultDurationDays), cfg.MaxDurationDays); err != nil {
+			return &ConfigError{Field: "TRUSTED_DEFAULT_DURATION_DAYS", Message: err.Error()}
+		}
+	}
+	if cfg.ExpiryReminderDays < 0 || cfg.ExpiryReminderDays > cfg.MaxDurationDays {
+		return &ConfigError{Field: "EXPIRY_REMINDER_DAYS", Message: fmt.Sprintf("must be between 0 and %d", cfg.MaxDurationDays)}
+	}
+
+	if cfg.PanelAlertThreshold < 0 {
+		return errors.New("PANEL_ALERT_THRESHOLD must not be negative")
+	}
+	if cfg.PanelAlertWindow <= 0 {
+		return errors.New("PANEL_ALERT_WINDOW must be positive")
+	}
+
 	// Validate server configuration
 	if cfg.Server.User == "" {
 		return errors.New("server user is required")
@@ -93,6 +426,83 @@ func validateConfig(cfg *Config) error {
 	if cfg.Server.APIURL == "" {
 		return errors.New("server API URL is required")
 	}
+	if err := validation.ValidateURL(cfg.Server.APIURL); err != nil {
+		return &ConfigError{Field: "XRAY_API_URL", Message: err.Error()}
+	}
+
+	if cfg.StoragePath == "" {
+		return errors.New("STORAGE_PATH must not be empty")
+	}
+	if err := ensureWritableDir(filepath.Dir(cfg.StoragePath)); err != nil {
+		return &ConfigError{Field: "STORAGE_PATH", Message: err.Error()}
+	}
+	if err := validateListenAddr("METRICS_ADDR", cfg.MetricsAddr); err != nil {
+		return err
+	}
+	if err := validateListenAddr("HEALTH_ADDR", cfg.HealthAddr); err != nil {
+		return err
+	}
+
+	if cfg.StorageKey != "" && len(cfg.StorageKey) < constants.MinStorageKeyLength {
+		return &ConfigError{Field: "STORAGE_KEY", Message: fmt.Sprintf("must be at least %d characters", constants.MinStorageKeyLength)}
+	}
+
+	return nil
+}
+
+// validateWebhook checks the webhook settings when webhook mode is enabled
+func validateWebhook(tg TelegramConfig) error {
+	if tg.WebhookURL == "" {
+		return nil
+	}
+	if err := validation.ValidateURL(tg.WebhookURL); err != nil {
+		return &ConfigError{Field: "WEBHOOK_URL", Message: err.Error()}
+	}
+	if !strings.HasPrefix(strings.ToLower(tg.WebhookURL), "https://") {
+		return &ConfigError{Field: "WEBHOOK_URL", Message: "Telegram only delivers webhooks over https://"}
+	}
+	if tg.WebhookListen == "" {
+		return errors.New("WEBHOOK_LISTEN must not be empty")
+	}
+	if err := validateListenAddr("WEBHOOK_LISTEN", tg.WebhookListen); err != nil {
+		return err
+	}
+	if (tg.WebhookCert == "") != (tg.WebhookKey == "") {
+		return errors.New("WEBHOOK_CERT and WEBHOOK_KEY must be set together")
+	}
+	for field, path := range map[string]string{"WEBHOOK_CERT": tg.WebhookCert, "WEBHOOK_KEY": tg.WebhookKey} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return &ConfigError{Field: field, Message: err.Error()}
+		}
+	}
+	return nil
+}
 
+// validateListenAddr checks that an optional HTTP listen address is host:port
+func validateListenAddr(field, addr string) error {
+	if addr == "" {
+		return nil
+	}
+	if _, port, err := net.SplitHostPort(addr); err != nil || port == "" {
+		return &ConfigError{Field: field, Message: fmt.Sprintf("%q is not a host:port address, e.g. :9090", addr)}
+	}
 	return nil
 }
+
+// ensureWritableDir creates the directory if it's missing and checks that files can be
+// created in it, so a bad storage path fails at startup instead of on the first save
+func ensureWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create directory %s: %w", dir, err)
+	}
+
+	probe, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %w", dir, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseAdminIDs(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		wantIDs     []int64
+		wantInvalid []string
+	}{
+		{name: "single", raw: "123", wantIDs: []int64{123}},
+		{name: "list with spaces", raw: " 123 , 456,789 ", wantIDs: []int64{123, 456, 789}},
+		{name: "empty", raw: ""},
+		{name: "only separators", raw: " , ,, "},
+		{name: "empty entries", raw: "123,,456,", wantIDs: []int64{123, 456}},
+		{name: "duplicates", raw: "123,456,123, 456", wantIDs: []int64{123, 456}},
+		{name: "not a number", raw: "123,abc", wantIDs: []int64{123}, wantInvalid: []string{"abc"}},
+		{name: "username", raw: "@admin,456", wantIDs: []int64{456}, wantInvalid: []string{"@admin"}},
+		{name: "zero and negative", raw: "0,-5,7", wantIDs: []int64{7}, wantInvalid: []string{"0", "-5"}},
+		{name: "fraction", raw: "1.5", wantInvalid: []string{"1.5"}},
+		{name: "overflow", raw: "99999999999999999999", wantInvalid: []string{"99999999999999999999"}},
+		{name: "semicolon separated", raw: "123;456", wantInvalid: []string{"123;456"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, invalid := parseAdminIDs(tt.raw)
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("parseAdminIDs(%q) IDs = %v, want %v", tt.raw, ids, tt.wantIDs)
+			}
+			if !reflect.DeepEqual(invalid, tt.wantInvalid) {
+				t.Errorf("parseAdminIDs(%q) invalid = %q, want %q", tt.raw, invalid, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsAdminIDsWithoutValidIDs(t *testing.T) {
+	// An empty config file keeps a config.yaml on the machine out of the test
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, nil, 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Setenv("CONFIG_FILE", configFile)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	for _, raw := range []string{"abc", "@admin, 0", " , ,"} {
+		t.Run(raw, func(t *testing.T) {
+			t.Setenv("TG_ADMIN_IDS", raw)
+
+			_, err := Load(logger)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) || configErr.Field != "TG_ADMIN_IDS" {
+				t.Errorf("Load() error = %v, want a ConfigError for TG_ADMIN_IDS", err)
+			}
+		})
+	}
+}
@@ -5,25 +5,125 @@ const (
 	MinUsernameLength = 3
 	MaxUsernameLength = 32
 
+	// Telegram's own limits on @username length
+	MinTelegramUsernameLength = 5
+	MaxTelegramUsernameLength = 32
+
+	// MaxInputLength is the longest text message, in characters, the bot will process
+	MaxInputLength = 256
+
+	// MaxTrustedAccounts is how many VPN accounts a trusted user can own
+	MaxTrustedAccounts = 3
+
+	// MaxTagLength is the longest tag an admin can put on a member
+	MaxTagLength = 20
+
+	// MaxMessageLength is Telegram's limit on the length of a text message
+	MaxMessageLength = 4096
+
 	// User naming constants
 	UsernameSeparator = "-"
 
 	// Traffic constants
 	BytesInGB = 1024 * 1024 * 1024
 
+	// MaxTrafficLimitGB is the largest traffic limit that can be set on a member
+	MaxTrafficLimitGB = 100000
+
 	// Duration constants
-	MillisecondsInDay = 24 * 60 * 60 * 1000
+	MillisecondsInDay      = 24 * 60 * 60 * 1000
+	DefaultMaxDurationDays = 3650 // 10 years
 
 	// Network constants
 	DefaultTimeout          = 30
 	DefaultRetryCount       = 3
 	DefaultRetryWaitTime    = 5
 	DefaultRetryMaxWaitTime = 20
+	DefaultShutdownTimeout  = 30  // seconds
+	DefaultRateLimit        = 30  // updates per user per minute
+	DefaultConfirmTimeout   = 120 // seconds
+	CreationRetryTimeout    = 300 // seconds a failed member creation can be retried
+
+	// Webhook constants
+	DefaultWebhookListen   = ":8443"
+	WebhookShutdownTimeout = 10      // seconds to finish in-flight webhook requests
+	MaxWebhookBodySize     = 1 << 20 // bytes accepted per webhook update
+
+	// Telegram flood control: retries after a 429 and the longest retry_after worth waiting for
+	MaxFloodRetries = 3
+	MaxFloodWait    = 30 // seconds
+
+	// BroadcastInterval is the pause, in milliseconds, between broadcast messages, keeping
+	// well under Telegram's limit of about 30 messages per second
+	BroadcastInterval = 50
+
+	// ProgressUpdateInterval is the minimum number of seconds between progress message edits
+	ProgressUpdateInterval = 3
+
+	// Long operations: after OperationNoticeDelay seconds the loading message says the bot is
+	// still working, and after OperationTimeout seconds the operation stops with what it got done
+	OperationNoticeDelay = 10
+	OperationTimeout     = 300
+
+	// LoadingFrameInterval is the number of seconds between loading animation frames, slow
+	// enough to stay within Telegram's edit limits
+	LoadingFrameInterval = 3
+
+	// BulkPageSize is how many members one page of the bulk selection keyboard shows
+	BulkPageSize = 20
+
+	// MaxConcurrentInboundRequests bounds parallel per-inbound panel calls
+	MaxConcurrentInboundRequests = 4
+
+	// Panel health alert constants
+	DefaultPanelAlertThreshold = 5 // failures per operation
+	DefaultPanelAlertWindow    = 5 // minutes
+
+	// DefaultStoragePath is the JSON file holding trusted users, admins and VPN accounts
+	DefaultStoragePath = "data.json"
+
+	// MinStorageKeyLength is the shortest passphrase accepted for encrypting the storage file
+	MinStorageKeyLength = 16
+
+	// MetricsActiveWindow is how recently, in minutes, a user must have used the bot to count as active
+	MetricsActiveWindow = 15
+
+	// Readiness probe constants
+	ReadinessCacheTTL = 30 // seconds a panel check result is reused
+	ReadinessTimeout  = 5  // seconds a panel check may take
 
 	// Cache constants
 	CacheExpiration      = 30 // minutes
 	CacheCleanupInterval = 10 // minutes
 
+	// QR code constants
+	DefaultQRSize          = 256
+	MinQRSize              = 128
+	MaxQRSize              = 2048
+	DefaultQRRecoveryLevel = "medium"
+	MaxQRCaptionLength     = 800 // leaves room for the user line within Telegram's 1024-character caption limit
+	QRLabelScaleStep       = 256 // the QR_LABEL text grows by one font size per this many pixels of QR size
+	QRLabelPadding         = 4   // pixels above and below the QR_LABEL text, before scaling
+
+	// Chart constants
+	DefaultChartTopUsers = 10
+
+	// Top users report constants
+	DefaultTopUsers = 10
+
+	// Expiry reminder constants
+	DefaultExpiryReminderDays = 3
+	ExpiryReminderInterval    = 60 // minutes between checks for expiring accounts
+
+	// Traffic unit constants
+	DefaultTrafficUnit = "auto" // pick MB, GB or TB per report
+
+	// Traffic limit constants
+	TrafficWarnPercent = 80 // share of the limit at which usage is flagged
+
+	// Online list constants
+	RecentlySeenHours = 24 // how far back offline members are listed as recently seen
+
 	// Formatting constants
 	MaxEmailDisplayLength = 17
 	MaxEmailSuffixLength  = 14
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,19 +14,26 @@ import (
 
 	"xui-tg-admin/internal/commands"
 	"xui-tg-admin/internal/config"
+	"xui-tg-admin/internal/constants"
 	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
 	"xui-tg-admin/internal/models"
 	"xui-tg-admin/internal/permissions"
 	"xui-tg-admin/internal/services"
 	"xui-tg-admin/internal/validation"
+	"xui-tg-admin/pkg/xrayclient"
 )
 
+// resetInboundPrefix is the callback data prefix for resetting a member's traffic in one inbound
+const resetInboundPrefix = "reset_inbound_"
+
 // AdminHandler handles admin commands
 type AdminHandler struct {
 	BaseHandler
 	commandHandlers map[string]func(telebot.Context) error
 	trustedHandler  *AdminTrustedHandler
 	storageService  *services.StorageService
+	chartService    *services.ChartService
 }
 
 // NewAdminHandler creates a new admin handler
@@ -33,6 +42,7 @@ func NewAdminHandler(
 	stateService *services.UserStateService,
 	qrService *services.QRService,
 	storageService *services.StorageService,
+	chartService *services.ChartService,
 	config *config.Config,
 	logger *logrus.Logger,
 ) *AdminHandler {
@@ -41,6 +51,7 @@ func NewAdminHandler(
 	handler := &AdminHandler{
 		BaseHandler:    baseHandler,
 		storageService: storageService,
+		chartService:   chartService,
 	}
 
 	// Initialize trusted handler
@@ -72,6 +83,20 @@ func (h *AdminHandler) Handle(ctx context.Context, c telebot.Context) error {
 		return err
 	}
 
+	// Cancel aborts whatever flow is in progress and returns to the main menu
+	if isCancelRequest(c.Text()) {
+		return h.handleStart(c)
+	}
+
+	// A /start deep link abandons the current flow and jumps into the linked one
+	if handler, ok := h.deepLinkHandler(c, h.commandHandlers); ok {
+		if err := h.stateService.ClearState(userID); err != nil {
+			h.logger.Errorf("Failed to clear user state: %v", err)
+			return err
+		}
+		return handler(c)
+	}
+
 	// Handle based on state
 	switch userState.State {
 	case models.Default:
@@ -90,6 +115,32 @@ func (h *AdminHandler) Handle(ctx context.Context, c telebot.Context) error {
 		return h.processConfirmResetUsersNetworkUsage(c)
 	case models.StateAwaitingTrustedUsername:
 		return h.processTrustedUsernameInput(c)
+	case models.AwaitingRenameUserName:
+		return h.processRenameUser(c)
+	case models.AwaitingRestoreDocument:
+		return h.processRestoreDocument(c)
+	case models.AwaitConfirmRestore:
+		return h.processConfirmRestore(c)
+	case models.AwaitingAdminIdentifier:
+		return h.processAdminIdentifier(c)
+	case models.AwaitConfirmDeleteExpired:
+		return h.processConfirmDeleteExpired(c)
+	case models.AwaitConfirmPruneAccounts:
+		return h.processConfirmPruneAccounts(c)
+	case models.AwaitBulkSelection:
+		return h.processBulkSelection(c)
+	case models.AwaitingUserNote:
+		return h.processUserNote(c)
+	case models.AwaitingUserTags:
+		return h.processUserTags(c)
+	case models.AwaitingTrafficLimit:
+		return h.processTrafficLimit(c)
+	case models.AwaitConfirmInboundToggle:
+		return h.processConfirmInboundToggle(c)
+	case models.AwaitingBroadcastMessage:
+		return h.processBroadcastMessage(c)
+	case models.AwaitConfirmBroadcast:
+		return h.processConfirmBroadcast(c)
 	default:
 		h.logger.Warnf("Unknown state: %d", userState.State)
 		return h.handleDefaultState(c)
@@ -106,9 +157,26 @@ func (h *AdminHandler) initializeCommands() {
 		commands.OnlineMembers:     h.handleGetOnlineMembers,
 		commands.NetworkUsage:      h.handleGetUsersNetworkUsage,
 		commands.DetailedUsage:     h.handleGetDetailedUsersInfo,
+		commands.ExportUsageCSV:    h.handleExportUsageCSV,
+		commands.TrafficChart:      h.handleTrafficChart,
+		commands.TopUsers:          h.handleTopUsers,
+		commands.Top:               h.handleTopUsers,
+		commands.Inbounds:          h.handleFindInbounds,
+		commands.Transfer:          h.handleTransferAccount,
+		commands.Backup:            h.handleBackup,
+		commands.Restore:           h.handleRestore,
+		commands.HealthCheck:       h.handleHealthCheck,
+		commands.ToggleInbound:     h.handleToggleInbound,
+		commands.Broadcast:         h.handleBroadcast,
+		commands.Ping:              h.handleHealthCheck,
 		commands.ResetNetworkUsage: h.handleResetUsersNetworkUsage,
+		commands.DeleteExpired:     h.handleDeleteExpired,
+		commands.Reconcile:         h.handleReconcile,
+		commands.BulkToggle:        h.handleBulkToggle,
 		commands.AddTrusted:        h.handleAddTrusted,
 		commands.RevokeTrusted:     h.handleRevokeTrusted,
+		commands.AddAdmin:          h.handleAddAdmin,
+		commands.RevokeAdmin:       h.handleRevokeAdmin,
 		commands.ReturnToMainMenu:  h.handleStart,
 		commands.Cancel:            h.handleStart,
 	}
@@ -118,26 +186,33 @@ func (h *AdminHandler) initializeCommands() {
 func (h *AdminHandler) getButtonCommand(text string) string {
 	// Check for specific button patterns
 	switch text {
-	case "↩️ " + commands.ReturnToMainMenu:
+	case "↩️ " + commands.Label(commands.ReturnToMainMenu):
 		return commands.ReturnToMainMenu
-	case "∞ " + commands.Infinite:
+	case "∞ " + commands.Label(commands.Infinite):
 		return commands.Infinite
-	case "✅ " + commands.Confirm:
+	case "✅ " + commands.Label(commands.Confirm):
 		return commands.Confirm
-	case "❌ " + commands.Cancel:
+	case "❌ " + commands.Label(commands.Cancel):
 		return commands.Cancel
-	case "🔗 " + commands.ViewConfig:
+	case "🔗 " + commands.Label(commands.ViewConfig):
 		return commands.ViewConfig
-	case "🔄 " + commands.ResetTraffic:
+	case "🔄 " + commands.Label(commands.ResetTraffic):
 		return commands.ResetTraffic
-	case "🗑️ " + commands.Delete:
+	case "🗑️ " + commands.Label(commands.Delete):
 		return commands.Delete
 	}
 
+	// Map duration presets to the number of days they stand for
+	for _, days := range commands.DurationPresets {
+		if text == durationPresetLabel(days) {
+			return strconv.Itoa(days)
+		}
+	}
+
 	// For other buttons, try to extract command after emoji
 	if len(text) > 2 && text[0] != '/' {
 		if spaceIndex := strings.Index(text, " "); spaceIndex > 0 {
-			return text[spaceIndex+1:]
+			return commands.Resolve(text[spaceIndex+1:])
 		}
 	}
 
@@ -149,6 +224,11 @@ func (h *AdminHandler) handleDefaultState(c telebot.Context) error {
 	text := c.Text()
 	command := h.getButtonCommand(text)
 
+	// Slash commands may carry arguments, e.g. "/top 20"
+	if strings.HasPrefix(command, "/") {
+		command, _, _ = strings.Cut(command, " ")
+	}
+
 	// Check if we have a command handler for this command
 	if handler, ok := h.commandHandlers[command]; ok {
 		return handler(c)
@@ -158,6 +238,33 @@ func (h *AdminHandler) handleDefaultState(c telebot.Context) error {
 	return h.handleStart(c)
 }
 
+// handleMenuCallback runs a command picked from the inline main menu
+func (h *AdminHandler) handleMenuCallback(c telebot.Context, data string) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	// Drop the menu so a stale one can't be tapped out of context
+	if c.Message() != nil {
+		if _, err := c.Bot().EditReplyMarkup(c.Message(), nil); err != nil {
+			h.logger.Errorf("Failed to remove menu keyboard: %v", err)
+		}
+	}
+
+	handler, ok := h.commandHandlers[strings.TrimPrefix(data, menuCallbackPrefix)]
+	if !ok {
+		return c.Send(h.t(i18n.UnknownAction))
+	}
+
+	// A menu action abandons whatever flow was in progress
+	if err := h.stateService.ClearState(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to clear user state: %v", err)
+		return err
+	}
+
+	return handler(c)
+}
+
 // handleStart handles the /start command
 func (h *AdminHandler) handleStart(c telebot.Context) error {
 	// Clear user state
@@ -177,11 +284,22 @@ func (h *AdminHandler) handleStart(c telebot.Context) error {
 	// Show main menu with welcome message only for /start command
 	markup := h.createMainKeyboard(permissions.Admin)
 	if c.Text() == commands.Start {
-		return h.sendTextMessage(c, "🚀 <b>Welcome to X-UI Admin Panel!</b>\n\nYou have administrator privileges. Use the menu below to manage your VPN users, monitor connections, and configure settings.", markup)
+		return h.sendTextMessage(c, h.welcomeMessage(permissions.Admin), markup)
 	}
 
 	// For return to main menu, show only the keyboard without any message
-	return h.sendTextMessage(c, "🏠 <b>Main Menu</b>\n\nSelect an action:", markup)
+	return h.sendTextMessage(c, h.t(i18n.MainMenu), markup)
+}
+
+// ShowMenu re-sends the main menu without the welcome text. The flow step is reset so
+// menu buttons work, but the selected member and other payload are kept.
+func (h *AdminHandler) ShowMenu(c telebot.Context) error {
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.Default); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.MainMenu), h.createMainKeyboard(permissions.Admin))
 }
 
 // handleAddMember handles the Add Member command
@@ -196,7 +314,7 @@ func (h *AdminHandler) handleAddMember(c telebot.Context) error {
 
 	// Show return keyboard
 	markup := h.createReturnKeyboard()
-	return h.sendTextMessage(c, "👤 <b>Add New User</b>\n\n📝 Please enter a username for the new user:\n\n<i>• Use only letters, numbers, and underscores\n• 3-20 characters long\n• Example: john_doe, user123</i>", markup)
+	return h.sendTextMessage(c, h.t(i18n.AddMemberPrompt), markup)
 }
 
 // handleEditMember handles the Edit Member command
@@ -209,7 +327,7 @@ func (h *AdminHandler) handleEditMember(c telebot.Context) error {
 	}
 
 	// Показываем список пользователей с сортировкой по дате добавления
-	return h.showMembersWithSort(c, models.SortByCreationOrder, "edit")
+	return h.showMembersWithSort(c, models.SortByCreationOrder, "edit", "")
 }
 
 // handleDeleteMember handles the Delete Member command
@@ -222,31 +340,7 @@ func (h *AdminHandler) handleDeleteMember(c telebot.Context) error {
 	}
 
 	// Показываем список пользователей с сортировкой по дате добавления
-	return h.showMembersWithSort(c, models.SortByCreationOrder, "delete")
-}
-
-// handleGetOnlineMembers handles the Online Members command
-func (h *AdminHandler) handleGetOnlineMembers(c telebot.Context) error {
-
-	// Get online users
-	onlineUsers, err := h.xrayService.GetOnlineUsers(context.Background())
-	if err != nil {
-		h.logger.Errorf("Failed to get online users: %v", err)
-		return h.sendTextMessage(c, "❌ <b>Connection Error</b>\n\nCouldn't retrieve online users. Please check your server connection and try again.", h.createMainKeyboard(permissions.Admin))
-	}
-
-	// Format message
-	var message string
-	if len(onlineUsers) == 0 {
-		message = "💤 <b>No Active Connections</b>\n\nNo users are currently connected to the VPN server."
-	} else {
-		message = fmt.Sprintf("🟢 <b>Active Connections (%d)</b>\n\n", len(onlineUsers))
-		for _, user := range onlineUsers {
-			message += fmt.Sprintf("👤 %s\n", user)
-		}
-	}
-
-	return h.sendTextMessage(c, message, h.createMainKeyboard(permissions.Admin))
+	return h.showMembersWithSort(c, models.SortByCreationOrder, "delete", "")
 }
 
 // handleGetUsersNetworkUsage handles the Network Usage command
@@ -256,27 +350,82 @@ func (h *AdminHandler) handleGetUsersNetworkUsage(c telebot.Context) error {
 	inbounds, err := h.xrayService.GetInbounds(context.Background())
 	if err != nil {
 		h.logger.Errorf("Failed to get inbounds: %v", err)
-		return h.sendTextMessage(c, "❌ <b>Connection Error</b>\n\nCouldn't retrieve network usage data. Please check your server connection and try again.", h.createReturnKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.UsageConnectionError), h.createReturnKeyboard())
 	}
 
 	// Format beautiful network usage report
-	message := helpers.FormatNetworkUsageReport(inbounds)
+	message := helpers.FormatNetworkUsageReport(inbounds, helpers.ParseTrafficUnit(h.config.TrafficUnit))
 
-	return h.sendTextMessage(c, message, h.createReturnKeyboard())
+	return h.sendLongMessage(c, message, h.createReturnKeyboard())
 }
 
 // handleResetUsersNetworkUsage handles the Reset Network Usage command
 func (h *AdminHandler) handleResetUsersNetworkUsage(c telebot.Context) error {
+	// Preview the scale of the reset before asking for confirmation
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.ResetAllConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	preview := summarizeResetAll(inbounds)
+	if preview.Clients == 0 {
+		return h.sendTextMessage(c, h.t(i18n.ResetAllNoUsers), h.createMainKeyboard(permissions.Admin))
+	}
+
+	// The confirmation is bound to this client count, so a changed server asks again
+	clientCount := strconv.Itoa(preview.Clients)
+	if err := h.stateService.WithPayload(c.Sender().ID, clientCount); err != nil {
+		h.logger.Errorf("Failed to set payload: %v", err)
+		return err
+	}
+
 	// Set state to awaiting confirmation for reset
-	err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitConfirmResetUsersNetworkUsage)
+	err = h.stateService.WithConversationState(c.Sender().ID, models.AwaitConfirmResetUsersNetworkUsage)
 	if err != nil {
 		h.logger.Errorf("Failed to set state: %v", err)
 		return err
 	}
+	if err := h.stateService.WithConfirmationRequested(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to set confirmation time: %v", err)
+		return err
+	}
 
 	// Show confirm keyboard
-	markup := h.createConfirmKeyboard()
-	return h.sendTextMessage(c, "⚠️ <b>Reset All Network Usage</b>\n\nThis will reset traffic statistics for <b>ALL users</b> in the system.\n\n<b>⚠️ This action cannot be undone!</b>\n\nAre you sure you want to proceed?", markup)
+	unit := helpers.ResolveTrafficUnit(helpers.ParseTrafficUnit(h.config.TrafficUnit), preview.Traffic)
+	message := h.t(i18n.ResetAllConfirm, preview.Users, preview.Clients, preview.Inbounds, unit.Value(preview.Traffic), unit.Name)
+	markup := h.createInlineConfirmKeyboard(confirmResetAllPrefix + clientCount)
+	return h.sendTextMessage(c, message, markup)
+}
+
+// resetAllPreview summarizes what a mass traffic reset would affect
+type resetAllPreview struct {
+	Users    int
+	Clients  int
+	Inbounds int
+	Traffic  int64 // bytes
+}
+
+// summarizeResetAll counts the users, clients and traffic a mass traffic reset would wipe
+func summarizeResetAll(inbounds []models.Inbound) resetAllPreview {
+	var preview resetAllPreview
+	users := make(map[string]bool)
+
+	for _, inbound := range inbounds {
+		if len(inbound.ClientStats) == 0 {
+			continue
+		}
+		preview.Inbounds++
+
+		for _, clientStat := range inbound.ClientStats {
+			preview.Clients++
+			preview.Traffic += clientStat.Up + clientStat.Down
+			users[helpers.ExtractBaseUsername(clientStat.Email)] = true
+		}
+	}
+
+	preview.Users = len(users)
+	return preview
 }
 
 // processUserName processes the username input
@@ -289,9 +438,12 @@ func (h *AdminHandler) processUserName(c telebot.Context) error {
 		return h.handleStart(c)
 	}
 
+	// Mobile keyboards add stray spaces and capitals; store names in one canonical form
+	username = validation.NormalizeUsername(username)
+
 	// Validate username format
 	if err := validation.ValidateUsername(username); err != nil {
-		return h.sendTextMessage(c, fmt.Sprintf("❌ <b>Invalid Username</b>\n\n%s\n\n💡 <b>Requirements:</b>\n• 3-20 characters\n• Letters, numbers, underscores only\n• Example: john_doe, user123\n\nPlease try again:", err.Error()), h.createReturnKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.AddMemberInvalidUsername, err.Error()), h.createReturnKeyboard())
 	}
 
 	// Store username in state
@@ -308,20 +460,48 @@ func (h *AdminHandler) processUserName(c telebot.Context) error {
 		return err
 	}
 
-	// Create keyboard with Infinite option
+	return h.sendTextMessage(c, h.t(i18n.AddMemberDurationPrompt, username, h.config.MaxDurationDays), h.createDurationKeyboard())
+}
+
+// createDurationKeyboard creates the duration keyboard with presets, Infinite and Return options
+func (h *AdminHandler) createDurationKeyboard() *telebot.ReplyMarkup {
 	markup := &telebot.ReplyMarkup{
 		ResizeKeyboard: true,
 	}
-	markup.Reply(
+
+	var rows []telebot.Row
+	var row telebot.Row
+	for _, days := range commands.DurationPresets {
+		// Don't offer presets the configured maximum would reject
+		if days > h.config.MaxDurationDays {
+			continue
+		}
+		row = append(row, telebot.Btn{Text: durationPresetLabel(days)})
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	rows = append(rows,
 		telebot.Row{
-			telebot.Btn{Text: "∞ " + commands.Infinite},
+			telebot.Btn{Text: "∞ " + commands.Label(commands.Infinite)},
 		},
 		telebot.Row{
-			telebot.Btn{Text: "↩️ " + commands.ReturnToMainMenu},
+			telebot.Btn{Text: "↩️ " + commands.Label(commands.ReturnToMainMenu)},
 		},
 	)
 
-	return h.sendTextMessage(c, fmt.Sprintf("⏰ <b>Set Duration for %s</b>\n\n📅 Enter subscription duration in days:\n\n<i>• Example: 30 (for 30 days)\n• Maximum: 3650 days\n• Or choose Infinite for unlimited time</i>", username), markup)
+	markup.Reply(rows...)
+	return markup
+}
+
+// durationPresetLabel returns the button text for a preset duration
+func durationPresetLabel(days int) string {
+	return fmt.Sprintf("📅 %d %s", days, commands.Label(commands.Days))
 }
 
 // processDuration processes the duration input
@@ -346,22 +526,38 @@ func (h *AdminHandler) processDuration(c telebot.Context) error {
 
 	// Get username from state
 	if userState.Payload == nil {
-		return h.sendTextMessage(c, "❌ <b>Session Error</b>\n\nUsername data was lost. Please start over.", h.createReturnKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.SessionUsernameLost), h.createReturnKeyboard())
 	}
 
 	baseUsername := *userState.Payload
 
+	// Refuse to create a second member with the same name, offering the existing one instead
+	existing, err := h.findExistingMember(context.Background(), baseUsername)
+	if err != nil {
+		h.logger.Errorf("Failed to check existing members: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createReturnKeyboard())
+	}
+	if existing != "" {
+		return h.offerExistingMember(c, existing)
+	}
+
 	// Get enabled inbounds
 	enabledInbounds, err := h.getEnabledInbounds(context.Background())
 	if err != nil {
 		h.logger.Errorf("Failed to get enabled inbounds: %v", err)
-		return h.sendTextMessage(c, "❌ <b>Server Configuration Error</b>\n\nNo enabled inbound connections found. Please check your server configuration or contact the administrator.", h.createReturnKeyboard())
+		return h.sendTextMessage(c, h.enabledInboundsErrorMessage(err, permissions.Admin), h.createReturnKeyboard())
 	}
 
 	// Calculate expiry time
-	expiryTime, err := calculateExpiryTime(durationStr)
+	expiryTime, err := calculateExpiryTime(durationStr, h.config.MaxDurationDays)
 	if err != nil {
-		return h.sendTextMessage(c, fmt.Sprintf("❌ <b>Invalid Duration</b>\n\n%s\n\n💡 <b>Valid formats:</b>\n• Number: 30 (for 30 days)\n• Range: 1-3650 days\n• Or use the Infinite button\n\nPlease try again:", err.Error()), h.createReturnKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.AddMemberInvalidDuration, err.Error(), h.config.MaxDurationDays), h.createReturnKeyboard())
+	}
+
+	password, err := models.NewMemberPassword(enabledInbounds)
+	if err != nil {
+		h.logger.Errorf("Failed to generate password: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.AddMemberFailed, baseUsername, html.EscapeString(err.Error())), h.createReturnKeyboard())
 	}
 
 	// Create client creation parameters
@@ -372,13 +568,25 @@ func (h *AdminHandler) processDuration(c telebot.Context) error {
 		CommonSubId:     models.GenerateSubID(),
 		BaseFingerprint: fmt.Sprintf("%x", time.Now().UnixNano()),
 		SenderID:        c.Sender().ID,
+		Password:        password,
 	}
 
+	return h.createMember(c, params, enabledInbounds)
+}
+
+// createMember creates the member's clients on all enabled inbounds and reports the result.
+// If no client could be created the params are kept so the admin can retry.
+func (h *AdminHandler) createMember(c telebot.Context, params ClientCreationParams, enabledInbounds []models.Inbound) error {
 	// Send loading message
-	loadingMsg, _ := h.sendTextMessageWithReturn(c, "⏳ <b>Creating User...</b>\n\nPlease wait while we set up the new user configuration across all servers.", nil)
+	loadingMsg, _ := h.sendTextMessageWithReturn(c, h.t(i18n.AddMemberCreating), nil)
 
 	// Create clients for all enabled inbounds
-	createdEmails, addErrors, addedToAny := h.createClientsForAllInbounds(context.Background(), params, enabledInbounds)
+	createdEmails, addErrors, _ := h.createClientsForAllInbounds(context.Background(), params, enabledInbounds)
+
+	// Only count clients the panel really has when verification is on
+	createdEmails, verifyErrors := h.verifyCreatedClients(context.Background(), createdEmails)
+	addErrors = append(addErrors, verifyErrors...)
+	addedToAny := len(createdEmails) > 0
 
 	// Delete loading message
 	if loadingMsg != nil {
@@ -386,7 +594,14 @@ func (h *AdminHandler) processDuration(c telebot.Context) error {
 	}
 
 	if !addedToAny {
-		return h.sendTextMessage(c, fmt.Sprintf("❌ <b>User Creation Failed</b>\n\nCouldn't create user '%s' in any server configuration.\n\n<b>Errors:</b>\n%s\n\nPlease check server configuration or try again later.", baseUsername, strings.Join(addErrors, "\n")), h.createReturnKeyboard())
+		return h.offerCreationRetry(c, params, addErrors)
+	}
+
+	// Keep the password like trusted users' accounts do, so it can be looked up later
+	if params.Password != "" {
+		if err := h.storageService.SetPassword(params.BaseUsername, params.Password); err != nil {
+			h.logger.Errorf("Failed to store member password: %v", err)
+		}
 	}
 
 	// Send subscription information and QR code
@@ -403,6 +618,11 @@ func (h *AdminHandler) processSelectUser(c telebot.Context) error {
 		return h.handleStart(c)
 	}
 
+	// Tag filter buttons re-list the members
+	if tag, ok := parseTagFilterButton(username); ok {
+		return h.showMembersWithSort(c, models.SortByCreationOrder, "edit", tag)
+	}
+
 	// Store username in state
 	err := h.stateService.WithPayload(c.Sender().ID, username)
 	if err != nil {
@@ -420,7 +640,38 @@ func (h *AdminHandler) processSelectUser(c telebot.Context) error {
 	// Create action keyboard
 	markup := h.createUserActionKeyboard()
 
-	return h.sendTextMessage(c, fmt.Sprintf("👤 <b>Managing User: %s</b>\n\n🎛️ Choose an action:", username), markup)
+	return h.sendTextMessage(c, h.manageMemberMessage(username), markup)
+}
+
+// manageMemberMessage builds the member management header, including the admin tags, note and
+// stored password if set
+func (h *AdminHandler) manageMemberMessage(username string) string {
+	message := h.t(i18n.ManageMember, username)
+	if tags := h.storageService.GetTags(username); len(tags) > 0 {
+		message += h.t(i18n.MemberTags, formatTags(tags))
+	}
+	if note := h.storageService.GetNote(username); note != "" {
+		message += h.t(i18n.MemberNote, html.EscapeString(note))
+	}
+	if password := h.storageService.GetPassword(username); password != "" {
+		message += h.t(i18n.MemberPassword, html.EscapeString(password))
+	}
+	return message
+}
+
+// offerExistingMember switches to managing an existing member after a duplicate name was entered
+func (h *AdminHandler) offerExistingMember(c telebot.Context, username string) error {
+	if err := h.stateService.WithPayload(c.Sender().ID, username); err != nil {
+		h.logger.Errorf("Failed to set payload: %v", err)
+		return err
+	}
+
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitMemberAction); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.MemberAlreadyExists, username), h.createUserActionKeyboard())
 }
 
 // processMemberAction processes the member action selection
@@ -442,7 +693,7 @@ func (h *AdminHandler) processMemberAction(c telebot.Context) error {
 
 	// Get username from state
 	if userState.Payload == nil {
-		return h.sendTextMessage(c, "❌ <b>Session Error</b>\n\nUser data was lost. Please start over.", h.createReturnKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.SessionUserLost), h.createReturnKeyboard())
 	}
 
 	username := *userState.Payload
@@ -454,12 +705,24 @@ func (h *AdminHandler) processMemberAction(c telebot.Context) error {
 	switch command {
 	case commands.ViewConfig:
 		return h.handleViewConfig(c, username)
+	case commands.ConnectionLinks:
+		return h.handleConnectionLinks(c, username)
 	case commands.ResetTraffic:
 		return h.handleResetTraffic(c, username)
 	case commands.Delete:
 		return h.handleConfirmDelete(c, username)
+	case commands.Rename:
+		return h.handleRenameRequest(c, username)
+	case commands.SetNote:
+		return h.handleSetNoteRequest(c, username)
+	case commands.Tags:
+		return h.handleTagsRequest(c, username)
+	case commands.TrafficLimit:
+		return h.handleTrafficLimitRequest(c, username)
+	case commands.ExportConfig:
+		return h.handleExportConfig(c, username)
 	default:
-		return h.sendTextMessage(c, "❌ <b>Invalid Action</b>\n\nPlease select one of the available options from the menu.", h.createUserActionKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.InvalidAction), h.createUserActionKeyboard())
 	}
 }
 
@@ -471,14 +734,26 @@ func (h *AdminHandler) createUserActionKeyboard() *telebot.ReplyMarkup {
 
 	markup.Reply(
 		telebot.Row{
-			telebot.Btn{Text: "🔗 " + commands.ViewConfig},
+			telebot.Btn{Text: "🔗 " + commands.Label(commands.ViewConfig)},
+			telebot.Btn{Text: "🔌 " + commands.Label(commands.ConnectionLinks)},
 		},
 		telebot.Row{
-			telebot.Btn{Text: "🔄 " + commands.ResetTraffic},
-			telebot.Btn{Text: "🗑️ " + commands.Delete},
+			telebot.Btn{Text: "✏️ " + commands.Label(commands.Rename)},
+			telebot.Btn{Text: "🔄 " + commands.Label(commands.ResetTraffic)},
 		},
 		telebot.Row{
-			telebot.Btn{Text: "↩️ " + commands.ReturnToMainMenu},
+			telebot.Btn{Text: "📝 " + commands.Label(commands.SetNote)},
+			telebot.Btn{Text: "🏷 " + commands.Label(commands.Tags)},
+		},
+		telebot.Row{
+			telebot.Btn{Text: "📶 " + commands.Label(commands.TrafficLimit)},
+			telebot.Btn{Text: "📦 " + commands.Label(commands.ExportConfig)},
+		},
+		telebot.Row{
+			telebot.Btn{Text: "🗑️ " + commands.Label(commands.Delete)},
+		},
+		telebot.Row{
+			telebot.Btn{Text: "↩️ " + commands.Label(commands.ReturnToMainMenu)},
 		},
 	)
 
@@ -493,63 +768,137 @@ func (h *AdminHandler) handleViewConfig(c telebot.Context, username string) erro
 	inbounds, err := h.xrayService.GetInbounds(context.Background())
 	if err != nil {
 		h.logger.Errorf("Failed to get inbounds: %v", err)
-		return h.sendTextMessage(c, fmt.Sprintf("Failed to get inbounds: %v", err), h.createUserActionKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.ViewConfigInboundsFailed, h.panelErrorText(err)), h.createUserActionKeyboard())
 	}
 
-	// Find first client with the base username to get SubID
-	var foundClientSubID string
+	// Collect the subscription IDs used by the member's clients, most common first
+	subIDs := h.collectSubIDs(inbounds, username)
+	foundClientSubID := primarySubID(subIDs)
 
-	for _, inbound := range inbounds {
-		// Parse inbound settings to get client details
-		var settings models.InboundSettings
-		if err := json.Unmarshal([]byte(inbound.Settings), &settings); err != nil {
-			h.logger.Errorf("Failed to parse settings for inbound %d: %v", inbound.ID, err)
-			continue
+	if foundClientSubID == "" {
+		// The member's clients may sit only on inbounds whose settings can't be read
+		if unreadable := unreadableInboundsOf(inbounds, username); len(unreadable) > 0 {
+			return h.sendTextMessage(c, h.t(i18n.MemberSettingsUnreadable, username, strings.Join(unreadable, ", ")), h.createUserActionKeyboard())
 		}
+		return h.sendTextMessage(c, h.t(i18n.MemberNotFound, username), h.createUserActionKeyboard())
+	}
 
-		// Find client in settings
-		for _, client := range settings.Clients {
-			// Check if client email matches the base username using helper function
-			if helpers.IsEmailMatchingBaseUsername(client.Email, username) {
-				h.logger.Infof("Found matching client: %s in inbound %d", client.Email, inbound.ID)
-				foundClientSubID = client.SubID
+	// Get subscription URL using SubID (same format as when adding user)
+	subURL := h.subscriptionURL(foundClientSubID)
+
+	// Make sure the panel actually serves the link before handing out a QR code for it
+	message := h.t(i18n.MemberConfig, username, subURL)
+	info, err := h.xrayService.FetchSubscription(context.Background(), subURL)
+	switch {
+	case errors.Is(err, xrayclient.ErrSubscriptionNotFound) || (err == nil && info.ConfigCount == 0):
+		return h.sendTextMessage(c, h.t(i18n.SubscriptionDead, username, subURL), h.createUserActionKeyboard())
+	case err != nil:
+		// The panel may not expose subscriptions to the bot, so don't block on it
+		h.logger.Warnf("Failed to check subscription for %s: %v", username, err)
+	default:
+		message += h.t(i18n.SubscriptionConfigCount, info.ConfigCount)
+	}
+
+	// Send subscription URL with user action keyboard (stays in same state)
+	err = h.sendTextMessage(c, message, h.createUserActionKeyboard())
+	if err != nil {
+		return err
+	}
+
+	// Send QR code
+	if err := h.sendSubscriptionQR(c, foundClientSubID, h.qrCaption(username)); err != nil {
+		return err
+	}
+
+	// Older users may have clients with different SubIDs, so one link doesn't cover every inbound
+	if len(subIDs) > 1 {
+		return h.sendSubIDMismatch(c, username, subIDs, foundClientSubID)
+	}
+
+	return nil
+}
+
+// handleResetTraffic handles the Reset Traffic action
+func (h *AdminHandler) handleResetTraffic(c telebot.Context, username string) error {
+	// Get all inbounds
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.panelErrorMessage(err), h.createUserActionKeyboard())
+	}
+
+	// Collect the inbounds the user has clients in
+	var memberInbounds []models.Inbound
+	for _, inbound := range inbounds {
+		for _, clientStat := range inbound.ClientStats {
+			if helpers.IsEmailMatchingBaseUsername(clientStat.Email, username) {
+				memberInbounds = append(memberInbounds, inbound)
 				break
 			}
 		}
-		if foundClientSubID != "" {
-			break
-		}
 	}
 
-	if foundClientSubID == "" {
-		return h.sendTextMessage(c, fmt.Sprintf("❌ <b>User Not Found</b>\n\nNo configuration found for user '%s'. The user may have been deleted or never existed.", username), h.createUserActionKeyboard())
+	// Nothing to choose between with a single inbound
+	if len(memberInbounds) <= 1 {
+		return h.executeResetTraffic(c, username, 0)
 	}
 
-	// Get subscription URL using SubID (same format as when adding user)
-	subURL := fmt.Sprintf("%s%s?name=%s", h.config.Server.SubURLPrefix, foundClientSubID, foundClientSubID)
+	keyboard := [][]telebot.InlineButton{
+		{{Text: h.t(i18n.ResetTrafficAllButton), Data: resetInboundPrefix + "0"}},
+	}
+	for _, inbound := range memberInbounds {
+		keyboard = append(keyboard, []telebot.InlineButton{{
+			Text: "📡 " + inbound.Remark,
+			Data: fmt.Sprintf("%s%d", resetInboundPrefix, inbound.ID),
+		}})
+	}
 
-	// Send subscription URL with user action keyboard (stays in same state)
-	err = h.sendTextMessage(c, fmt.Sprintf("🔗 <b>Configuration for %s</b>\n\n📋 <b>Subscription URL:</b>\n<code>%s</code>\n\n<i>Copy this link to your VPN client or scan the QR code below</i>", username, subURL), h.createUserActionKeyboard())
+	return h.sendTextMessage(c, h.t(i18n.ResetTrafficChooseInbound, username), &telebot.ReplyMarkup{InlineKeyboard: keyboard})
+}
+
+// handleResetInboundCallback resets a member's traffic in the chosen inbound, or in all of them for ID 0
+func (h *AdminHandler) handleResetInboundCallback(c telebot.Context, data string) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	// Drop the inline keyboard so the reset can't be triggered twice
+	if c.Message() != nil {
+		if _, err := c.Bot().EditReplyMarkup(c.Message(), nil); err != nil {
+			h.logger.Errorf("Failed to remove reset keyboard: %v", err)
+		}
+	}
+
+	inboundID, err := strconv.Atoi(strings.TrimPrefix(data, resetInboundPrefix))
 	if err != nil {
+		return c.Send(h.t(i18n.UnknownAction))
+	}
+
+	// The member must still be the one being managed
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
 		return err
 	}
+	if userState.State != models.AwaitMemberAction || userState.Payload == nil {
+		return h.handleExpiredConfirmation(c)
+	}
 
-	// Send QR code
-	return h.sendQRCode(c, subURL)
+	return h.executeResetTraffic(c, *userState.Payload, inboundID)
 }
 
-// handleResetTraffic handles the Reset Traffic action
-func (h *AdminHandler) handleResetTraffic(c telebot.Context, username string) error {
+// executeResetTraffic resets a member's traffic in one inbound, or in every inbound when inboundID is 0
+func (h *AdminHandler) executeResetTraffic(c telebot.Context, username string, inboundID int) error {
 	h.logger.Infof("Starting reset traffic for user: %s", username)
 
 	// Send loading message
-	loadingMsg, _ := h.sendTextMessageWithReturn(c, fmt.Sprintf("⏳ <b>Resetting Traffic...</b>\n\nResetting traffic statistics for user '%s'. Please wait...", username), nil)
+	loadingMsg, _ := h.sendTextMessageWithReturn(c, h.t(i18n.ResetTrafficInProgress, username), nil)
 
 	// Get all inbounds
 	inbounds, err := h.xrayService.GetInbounds(context.Background())
 	if err != nil {
 		h.logger.Errorf("Failed to get inbounds: %v", err)
-		return h.sendTextMessage(c, "❌ <b>Connection Error</b>\n\nCouldn't retrieve server data. Please check your connection and try again.", h.createUserActionKeyboard())
+		return h.sendTextMessage(c, h.panelErrorMessage(err), h.createUserActionKeyboard())
 	}
 
 	// Find all clients with the base username and reset their traffic
@@ -557,17 +906,27 @@ func (h *AdminHandler) handleResetTraffic(c telebot.Context, username string) er
 	successfullyReset := 0
 
 	for _, inbound := range inbounds {
+		if inboundID != 0 && inbound.ID != inboundID {
+			continue
+		}
+
 		for _, clientStat := range inbound.ClientStats {
 			// Check if client email matches the base username using helper function
 			if helpers.IsEmailMatchingBaseUsername(clientStat.Email, username) {
-				h.logger.Infof("Found matching client: %s in inbound %d", clientStat.Email, inbound.ID)
+				log := h.logger.WithFields(logrus.Fields{
+					"operation":  "reset_traffic",
+					"user_id":    c.Sender().ID,
+					"inbound_id": inbound.ID,
+					"email":      clientStat.Email,
+				})
+				log.Info("Found matching client")
 
 				err := h.xrayService.ResetUserTraffic(context.Background(), inbound.ID, clientStat.Email)
 				if err != nil {
-					h.logger.Errorf("Failed to reset traffic for %s in inbound %d: %v", clientStat.Email, inbound.ID, err)
-					resetErrors = append(resetErrors, fmt.Sprintf("Failed to reset %s in inbound %d: %v", clientStat.Email, inbound.ID, err))
+					log.WithError(err).Error("Failed to reset traffic")
+					resetErrors = append(resetErrors, fmt.Sprintf("Failed to reset %s in inbound %d: %s", clientStat.Email, inbound.ID, h.panelErrorText(err)))
 				} else {
-					h.logger.Infof("Successfully reset traffic for %s in inbound %d", clientStat.Email, inbound.ID)
+					log.Info("Successfully reset traffic")
 					successfullyReset++
 				}
 			}
@@ -577,22 +936,125 @@ func (h *AdminHandler) handleResetTraffic(c telebot.Context, username string) er
 	// Send result message
 	var message string
 	if successfullyReset > 0 {
-		message = fmt.Sprintf("✅ <b>Traffic Reset Complete</b>\n\n🔄 Successfully reset traffic for user <b>%s</b> (%d configurations)", username, successfullyReset)
+		message = h.t(i18n.ResetTrafficDone, username, successfullyReset)
 		if len(resetErrors) > 0 {
-			message += fmt.Sprintf("\n\n⚠️ <b>Some errors occurred:</b>\n%s", strings.Join(resetErrors, "\n"))
+			message += h.t(i18n.SomeErrorsOccurred, strings.Join(resetErrors, "\n"))
 		}
 	} else {
-		message = fmt.Sprintf("❌ <b>Reset Failed</b>\n\nNo active configurations found for user '%s'.", username)
+		message = h.t(i18n.ResetTrafficFailed, username)
 		if len(resetErrors) > 0 {
-			message += fmt.Sprintf("\n\n<b>Errors:</b>\n%s", strings.Join(resetErrors, "\n"))
+			message += h.t(i18n.ErrorsList, strings.Join(resetErrors, "\n"))
+		}
+	}
+
+	// Delete loading message
+	if loadingMsg != nil {
+		c.Bot().Delete(loadingMsg)
+	}
+
+	// Offer to tell the member, e.g. at the start of a new billing period
+	if successfullyReset > 0 {
+		if markup := h.resetNotifyMarkup(inbounds, username); markup != nil {
+			return h.sendTextMessage(c, message, markup)
 		}
 	}
 
+	return h.sendTextMessage(c, message, h.createUserActionKeyboard())
+}
+
+// handleRenameRequest handles the Rename action
+func (h *AdminHandler) handleRenameRequest(c telebot.Context, username string) error {
+	err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitingRenameUserName)
+	if err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.RenamePrompt, username), h.createReturnKeyboard())
+}
+
+// processRenameUser processes the new username input for a rename
+func (h *AdminHandler) processRenameUser(c telebot.Context) error {
+	newUsername := c.Text()
+
+	// Check for return to main menu
+	if h.getButtonCommand(newUsername) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+
+	if userState.Payload == nil {
+		return h.sendTextMessage(c, h.t(i18n.SessionUserLost), h.createReturnKeyboard())
+	}
+
+	oldUsername := *userState.Payload
+	newUsername = validation.NormalizeUsername(newUsername)
+
+	// Validate username format
+	if err := validation.ValidateUsername(newUsername); err != nil {
+		return h.sendTextMessage(c, h.t(i18n.RenameInvalidUsername, err.Error()), h.createReturnKeyboard())
+	}
+
+	if newUsername == oldUsername {
+		return h.sendTextMessage(c, h.t(i18n.RenameSameUsername), h.createReturnKeyboard())
+	}
+
+	// Make sure the new name is not taken
+	existing, err := h.findExistingMember(context.Background(), newUsername)
+	if err != nil {
+		h.logger.Errorf("Failed to check existing members: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createReturnKeyboard())
+	}
+	if existing != "" && existing != oldUsername {
+		return h.sendTextMessage(c, h.t(i18n.UsernameTaken, newUsername), h.createReturnKeyboard())
+	}
+
+	// Send loading message
+	loadingMsg, _ := h.sendTextMessageWithReturn(c, h.t(i18n.RenameInProgress, oldUsername, newUsername), nil)
+
+	renamed, renameErrors := h.renameClients(context.Background(), oldUsername, newUsername)
+
 	// Delete loading message
 	if loadingMsg != nil {
 		c.Bot().Delete(loadingMsg)
 	}
 
+	if renamed == 0 {
+		message := h.t(i18n.RenameFailed, oldUsername)
+		if len(renameErrors) > 0 {
+			message += h.t(i18n.ErrorsList, strings.Join(renameErrors, "\n"))
+		}
+		return h.sendTextMessage(c, message, h.createReturnKeyboard())
+	}
+
+	// Keep stored VPN accounts in sync with the panel
+	if err := h.storageService.RenameVpnAccount(oldUsername, newUsername); err != nil {
+		h.logger.Errorf("Failed to rename VPN account in storage: %v", err)
+	}
+	if err := h.storageService.RenameMemberData(oldUsername, newUsername); err != nil {
+		h.logger.Errorf("Failed to rename notes and tags in storage: %v", err)
+	}
+
+	// Continue managing the renamed user
+	if err := h.stateService.WithPayload(c.Sender().ID, newUsername); err != nil {
+		h.logger.Errorf("Failed to set payload: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitMemberAction); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	message := h.t(i18n.RenameDone, oldUsername, newUsername, renamed)
+	if len(renameErrors) > 0 {
+		message += h.t(i18n.SomeErrorsOccurred, strings.Join(renameErrors, "\n"))
+	}
+
 	return h.sendTextMessage(c, message, h.createUserActionKeyboard())
 }
 
@@ -604,9 +1066,13 @@ func (h *AdminHandler) handleConfirmDelete(c telebot.Context, username string) e
 		h.logger.Errorf("Failed to set state: %v", err)
 		return err
 	}
+	if err := h.stateService.WithConfirmationRequested(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to set confirmation time: %v", err)
+		return err
+	}
 	// Показать клавиатуру подтверждения
-	markup := h.createConfirmKeyboard()
-	return h.sendTextMessage(c, fmt.Sprintf("🗑️ <b>Confirm User Deletion</b>\n\n⚠️ You are about to permanently delete user <b>%s</b>\n\n<b>This action will:</b>\n• Remove user from all server configurations\n• Delete all associated data\n• Cannot be undone\n\nAre you absolutely sure?", username), markup)
+	markup := h.createInlineConfirmKeyboard(confirmDeletePrefix + memberToken(username))
+	return h.sendTextMessage(c, h.t(i18n.DeleteConfirm, username), markup)
 }
 
 // processConfirmDeletion processes the deletion confirmation
@@ -621,7 +1087,7 @@ func (h *AdminHandler) processConfirmDeletion(c telebot.Context) error {
 
 	// Check if user confirmed
 	if h.getButtonCommand(confirmation) != commands.Confirm {
-		return h.sendTextMessage(c, "❌ <b>Invalid Selection</b>\n\nPlease click Confirm to proceed with deletion or use the Return button to cancel.", h.createConfirmKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.DeleteInvalidSelection), h.createReturnKeyboard())
 	}
 
 	// Get user state to get the username we want to delete
@@ -632,69 +1098,132 @@ func (h *AdminHandler) processConfirmDeletion(c telebot.Context) error {
 	}
 
 	if userState.Payload == nil {
-		return h.sendTextMessage(c, "❌ <b>Session Error</b>\n\nUser data was lost. Please start the deletion process again.", h.createReturnKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.SessionDeleteLost), h.createReturnKeyboard())
 	}
 
-	username := *userState.Payload
+	if h.isConfirmationExpired(userState) {
+		return h.handleExpiredConfirmation(c)
+	}
 
+	return h.executeDeletion(c, *userState.Payload)
+}
+
+// executeDeletion removes the member from all inbounds and reports the result
+func (h *AdminHandler) executeDeletion(c telebot.Context, username string) error {
 	// Send loading message
-	loadingMsg, _ := h.sendTextMessageWithReturn(c, fmt.Sprintf("⏳ <b>Deleting User...</b>\n\nRemoving user '%s' from all server configurations. Please wait...", username), nil)
+	loadingMsg, _ := h.sendTextMessageWithReturn(c, h.t(i18n.DeleteInProgress, username), nil)
 
 	// Delete client using email
-	err = h.xrayService.RemoveClients(context.Background(), []string{username})
+	err := h.xrayService.RemoveClients(context.Background(), []string{username})
 	// Delete loading message
 	if loadingMsg != nil {
 		c.Bot().Delete(loadingMsg)
 	}
 
+	log := h.logger.WithFields(logrus.Fields{
+		"operation": "delete_member",
+		"user_id":   c.Sender().ID,
+		"username":  username,
+	})
+
 	if err != nil {
-		h.logger.Errorf("Failed to delete client: %v", err)
-		return h.sendTextMessage(c, fmt.Sprintf("❌ <b>Deletion Failed</b>\n\nCouldn't delete user '%s'. Please try again or contact administrator.\n\n<b>Error:</b> %v", username, err), h.createReturnKeyboard())
+		log.WithError(err).Error("Failed to delete client")
+		return h.sendTextMessage(c, h.t(i18n.DeleteFailed, username, h.panelErrorText(err)), h.createReturnKeyboard())
 	}
 
-	return h.sendTextMessage(c, fmt.Sprintf("✅ <b>User Deleted Successfully</b>\n\n🗑️ User '%s' has been permanently removed from all server configurations.", username), h.createReturnKeyboard())
+	log.Info("Deleted member")
+	if err := h.storageService.RemoveMemberData(username); err != nil {
+		log.WithError(err).Error("Failed to remove notes and tags from storage")
+	}
+	if err := h.stateService.ClearState(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to clear user state: %v", err)
+	}
+	return h.sendTextMessage(c, h.t(i18n.DeleteDone, username), h.createReturnKeyboard())
 }
 
-// handleGetDetailedUsersInfo handles the Detailed Usage command
-func (h *AdminHandler) handleGetDetailedUsersInfo(c telebot.Context) error {
+// handleExportUsageCSV handles the Export CSV command
+func (h *AdminHandler) handleExportUsageCSV(c telebot.Context) error {
+	members, err := h.xrayService.GetAllMembersWithInfo(context.Background(), models.SortByName)
+	if err != nil {
+		h.logger.Errorf("Failed to get members with info: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.ExportConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
 
-	// Get inbounds
+	if len(members) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.ExportNoUsers), h.createMainKeyboard(permissions.Admin))
+	}
+
+	csvData, err := helpers.FormatMembersCSV(members)
+	if err != nil {
+		h.logger.Errorf("Failed to build usage CSV: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.ExportBuildFailed), h.createMainKeyboard(permissions.Admin))
+	}
+
+	fileName := fmt.Sprintf("usage-%s.csv", time.Now().Format(constants.DateFormat))
+	caption := h.t(i18n.ExportCaption, len(members))
+	if err := h.sendDocument(c, csvData, fileName, caption); err != nil {
+		return h.sendTextMessage(c, h.t(i18n.ExportSendFailed), h.createMainKeyboard(permissions.Admin))
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.ExportDone), h.createMainKeyboard(permissions.Admin))
+}
+
+// handleTrafficChart handles the Traffic Chart command
+func (h *AdminHandler) handleTrafficChart(c telebot.Context) error {
 	inbounds, err := h.xrayService.GetInbounds(context.Background())
 	if err != nil {
 		h.logger.Errorf("Failed to get inbounds: %v", err)
-		return h.sendTextMessage(c, "❌ <b>Connection Error</b>\n\nCouldn't retrieve detailed usage data. Please check your server connection and try again.", h.createMainKeyboard(permissions.Admin))
+		return h.sendTextMessage(c, h.t(i18n.ChartConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	users := helpers.AggregateUserTraffic(inbounds)
+	if len(users) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.ChartNoUsers), h.createMainKeyboard(permissions.Admin))
 	}
 
-	// Get online users for status indication
-	onlineUsers, err := h.xrayService.GetOnlineUsers(context.Background())
+	if len(users) > constants.DefaultChartTopUsers {
+		users = users[:constants.DefaultChartTopUsers]
+	}
+
+	chart, err := h.chartService.RenderTrafficChart(users, constants.DefaultChartTopUsers)
 	if err != nil {
-		h.logger.Errorf("Failed to get online users: %v", err)
-		// Continue with empty online users list if this fails
-		onlineUsers = []string{}
+		h.logger.Errorf("Failed to render traffic chart: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.ChartRenderFailed), h.createMainKeyboard(permissions.Admin))
 	}
 
-	// Format compact traffic report
-	message := helpers.FormatCompactTrafficReport(inbounds, onlineUsers)
+	// Legend follows the bar order from top to bottom. Users are sorted heaviest first, so
+	// the first one sets the unit.
+	unit := helpers.ResolveTrafficUnit(helpers.ParseTrafficUnit(h.config.TrafficUnit), users[0].TotalUp+users[0].TotalDown)
 
-	return h.sendTextMessage(c, message, h.createMainKeyboard(permissions.Admin))
-}
+	var sb strings.Builder
+	sb.WriteString(h.t(i18n.ChartCaptionHeader, len(users)))
+	for i, user := range users {
+		total := unit.Value(user.TotalUp + user.TotalDown)
+		sb.WriteString(h.t(i18n.ChartLegendLine, i+1, html.EscapeString(user.BaseUsername), total, unit.Name))
+	}
 
-// createConfirmKeyboard creates a keyboard for confirmation
-func (h *AdminHandler) createConfirmKeyboard() *telebot.ReplyMarkup {
-	markup := &telebot.ReplyMarkup{
-		ResizeKeyboard: true,
+	if err := h.sendPhoto(c, chart, sb.String()); err != nil {
+		return h.sendTextMessage(c, h.t(i18n.ChartSendFailed), h.createMainKeyboard(permissions.Admin))
 	}
 
-	markup.Reply(
-		telebot.Row{
-			telebot.Btn{Text: "✅ " + commands.Confirm},
-		},
-		telebot.Row{
-			telebot.Btn{Text: "↩️ " + commands.ReturnToMainMenu},
-		},
-	)
+	return h.sendTextMessage(c, h.t(i18n.MainMenu), h.createMainKeyboard(permissions.Admin))
+}
 
-	return markup
+// isConfirmationExpired checks whether the pending confirmation is older than the configured timeout
+func (h *AdminHandler) isConfirmationExpired(userState *models.UserState) bool {
+	ttl := time.Duration(h.config.Telegram.ConfirmTimeout) * time.Second
+	return userState.IsConfirmationExpired(ttl)
+}
+
+// handleExpiredConfirmation resets the conversation after a stale confirmation
+func (h *AdminHandler) handleExpiredConfirmation(c telebot.Context) error {
+	h.logger.WithField("user_id", c.Sender().ID).Warn("Rejected expired confirmation")
+
+	if err := h.stateService.ClearState(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to clear user state: %v", err)
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.ConfirmationExpired), h.createMainKeyboard(permissions.Admin))
 }
 
 // processConfirmResetUsersNetworkUsage processes the confirmation for resetting network usage
@@ -709,19 +1238,39 @@ func (h *AdminHandler) processConfirmResetUsersNetworkUsage(c telebot.Context) e
 
 	// Check if user confirmed
 	if h.getButtonCommand(confirmation) != commands.Confirm {
-		return h.sendTextMessage(c, "❌ <b>Invalid Selection</b>\n\nPlease click Confirm to proceed with reset or use the Return button to cancel.", h.createConfirmKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.ResetAllInvalidSelection), h.createReturnKeyboard())
 	}
 
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if userState.Payload == nil || h.isConfirmationExpired(userState) {
+		return h.handleExpiredConfirmation(c)
+	}
+
+	expectedClients, err := strconv.Atoi(*userState.Payload)
+	if err != nil {
+		return h.handleExpiredConfirmation(c)
+	}
+
+	return h.executeResetAllTraffic(c, expectedClients)
+}
+
+// executeResetAllTraffic resets traffic for every client in every inbound, provided the
+// number of clients still matches the count the admin confirmed
+func (h *AdminHandler) executeResetAllTraffic(c telebot.Context, expectedClients int) error {
 	h.logger.Infof("Starting reset network usage for all users")
 
 	// Send loading message
-	loadingMsg, _ := h.sendTextMessageWithReturn(c, "⏳ <b>Resetting All Traffic...</b>\n\nThis may take a few moments. Resetting traffic statistics for all users across all servers...", nil)
+	loadingMsg, _ := h.sendTextMessageWithReturn(c, h.t(i18n.ResetAllInProgress), nil)
 
 	// Get all inbounds
 	inbounds, err := h.xrayService.GetInbounds(context.Background())
 	if err != nil {
 		h.logger.Errorf("Failed to get inbounds: %v", err)
-		return h.sendTextMessage(c, "❌ <b>Connection Error</b>\n\nCouldn't retrieve server data for reset operation. Please check your connection and try again.", h.createMainKeyboard(permissions.Admin))
+		return h.sendTextMessage(c, h.t(i18n.ResetAllConnectionError), h.createMainKeyboard(permissions.Admin))
 	}
 
 	// Collect all user emails from all inbounds
@@ -743,22 +1292,53 @@ func (h *AdminHandler) processConfirmResetUsersNetworkUsage(c telebot.Context) e
 	}
 
 	if len(userEmails) == 0 {
-		return h.sendTextMessage(c, "📭 <b>No Users Found</b>\n\nThere are no users in the system to reset traffic for.", h.createMainKeyboard(permissions.Admin))
+		return h.sendTextMessage(c, h.t(i18n.ResetAllNoUsers), h.createMainKeyboard(permissions.Admin))
+	}
+
+	if len(userEmails) != expectedClients {
+		h.logger.Warnf("Client count changed from %d to %d, aborting reset", expectedClients, len(userEmails))
+		if loadingMsg != nil {
+			c.Bot().Delete(loadingMsg)
+		}
+		if err := h.stateService.ClearState(c.Sender().ID); err != nil {
+			h.logger.Errorf("Failed to clear user state: %v", err)
+		}
+		return h.sendTextMessage(c, h.t(i18n.ResetAllCountChanged, expectedClients, len(userEmails)), h.createMainKeyboard(permissions.Admin))
 	}
 
 	h.logger.Infof("Found %d users to reset traffic", len(userEmails))
 
+	// The progress updates already show the reset is moving, so only the timeout applies
+	ctx, stop := h.startOperation(c, nil, "")
+	defer stop()
+
 	// Reset traffic for all users
 	var resetErrors []string
 	successfullyReset := 0
+	processed := 0
+	progress := h.newProgressMessage(c, loadingMsg)
 
-	for _, user := range userEmails {
-		err := h.xrayService.ResetUserTraffic(context.Background(), user.inboundID, user.email)
+	for i, user := range userEmails {
+		if ctx.Err() != nil {
+			h.logger.Warnf("Reset of all traffic timed out after %d of %d clients", i, len(userEmails))
+			break
+		}
+		processed++
+		progress.Update(h.t(i18n.ResetAllProgress, i, len(userEmails)))
+
+		log := h.logger.WithFields(logrus.Fields{
+			"operation":  "reset_all_traffic",
+			"user_id":    c.Sender().ID,
+			"inbound_id": user.inboundID,
+			"email":      user.email,
+		})
+
+		err := h.xrayService.ResetUserTraffic(ctx, user.inboundID, user.email)
 		if err != nil {
-			h.logger.Errorf("Failed to reset traffic for %s in inbound %d: %v", user.email, user.inboundID, err)
-			resetErrors = append(resetErrors, fmt.Sprintf("Failed to reset %s in inbound %d: %v", user.email, user.inboundID, err))
+			log.WithError(err).Error("Failed to reset traffic")
+			resetErrors = append(resetErrors, fmt.Sprintf("Failed to reset %s in inbound %d: %s", user.email, user.inboundID, h.panelErrorText(err)))
 		} else {
-			h.logger.Infof("Successfully reset traffic for %s in inbound %d", user.email, user.inboundID)
+			log.Info("Successfully reset traffic")
 			successfullyReset++
 		}
 	}
@@ -766,12 +1346,15 @@ func (h *AdminHandler) processConfirmResetUsersNetworkUsage(c telebot.Context) e
 	// Send result message
 	var message string
 	if successfullyReset > 0 {
-		message = fmt.Sprintf("✅ <b>Mass Traffic Reset Complete</b>\n\n🔄 Successfully reset traffic for <b>%d users</b>\n\n<i>All user traffic counters have been set to zero</i>", successfullyReset)
+		message = h.t(i18n.ResetAllDone, successfullyReset)
 		if len(resetErrors) > 0 {
-			message += fmt.Sprintf("\n\n⚠️ <b>Some errors occurred:</b>\n%s", strings.Join(resetErrors, "\n"))
+			message += h.t(i18n.SomeErrorsOccurred, strings.Join(resetErrors, "\n"))
 		}
 	} else {
-		message = fmt.Sprintf("❌ <b>Mass Reset Failed</b>\n\nCouldn't reset traffic for any users.\n\n<b>Errors:</b>\n%s", strings.Join(resetErrors, "\n"))
+		message = h.t(i18n.ResetAllFailed, strings.Join(resetErrors, "\n"))
+	}
+	if processed < len(userEmails) {
+		message += h.t(i18n.OperationTimedOut, processed, len(userEmails))
 	}
 
 	// Delete loading message
@@ -780,8 +1363,7 @@ func (h *AdminHandler) processConfirmResetUsersNetworkUsage(c telebot.Context) e
 	}
 
 	// Clear user state and return to main menu
-	err = h.stateService.ClearState(c.Sender().ID)
-	if err != nil {
+	if err := h.stateService.ClearState(c.Sender().ID); err != nil {
 		h.logger.Errorf("Failed to clear user state: %v", err)
 	}
 
@@ -789,18 +1371,30 @@ func (h *AdminHandler) processConfirmResetUsersNetworkUsage(c telebot.Context) e
 }
 
 // showMembersWithSort показывает список пользователей с указанной сортировкой
-func (h *AdminHandler) showMembersWithSort(c telebot.Context, sortType models.SortType, actionType string) error {
+func (h *AdminHandler) showMembersWithSort(c telebot.Context, sortType models.SortType, actionType string, tag string) error {
 	// Get all members with detailed info
 	members, err := h.xrayService.GetAllMembersWithInfo(context.Background(), sortType)
 	if err != nil {
 		h.logger.Errorf("Failed to get members with info: %v", err)
-		return h.sendTextMessage(c, "❌ <b>Connection Error</b>\n\nCouldn't retrieve user list. Please check your server connection and try again.", h.createReturnKeyboard())
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createReturnKeyboard())
+	}
+
+	// Only list members carrying the tag when filtering
+	if tag != "" {
+		tagged := h.storageService.TaggedUsernames(tag)
+		filtered := members[:0]
+		for _, member := range members {
+			if tagged[member.BaseUsername] {
+				filtered = append(filtered, member)
+			}
+		}
+		members = filtered
 	}
 
 	if len(members) == 0 {
-		message := "📭 <b>No Users Found</b>\n\nThere are no users in the system yet."
+		message := h.t(i18n.NoUsersYet)
 		if actionType == "edit" {
-			message += " Use <b>Add Member</b> to create your first user."
+			message += h.t(i18n.NoUsersYetHint)
 		}
 		return h.sendTextMessage(c, message, h.createReturnKeyboard())
 	}
@@ -817,8 +1411,13 @@ func (h *AdminHandler) showMembersWithSort(c telebot.Context, sortType models.So
 		rows = append(rows, telebot.Row{telebot.Btn{Text: buttonText}})
 	}
 
+	// Offer tag filters when picking a member to edit
+	if actionType == "edit" {
+		rows = append(rows, h.createTagFilterRows(tag)...)
+	}
+
 	// Add return button
-	rows = append(rows, telebot.Row{telebot.Btn{Text: "↩️ " + commands.ReturnToMainMenu}})
+	rows = append(rows, telebot.Row{telebot.Btn{Text: "↩️ " + commands.Label(commands.ReturnToMainMenu)}})
 
 	markup.Reply(rows...)
 
@@ -828,10 +1427,13 @@ func (h *AdminHandler) showMembersWithSort(c telebot.Context, sortType models.So
 
 	if actionType == "edit" {
 		nextState = models.AwaitSelectUserName
-		messageText = "✏️ <b>Edit User</b>\n\n👥 Select a user to manage:"
+		messageText = h.t(i18n.SelectMemberToEdit)
+		if tag != "" {
+			messageText += h.t(i18n.TagFilterActive, html.EscapeString(tag))
+		}
 	} else if actionType == "delete" {
 		nextState = models.AwaitConfirmMemberDeletion
-		messageText = "🗑️ <b>Delete User</b>\n\n⚠️ Select a user to permanently delete:"
+		messageText = h.t(i18n.SelectMemberToDelete)
 	}
 
 	err = h.stateService.WithConversationState(c.Sender().ID, nextState)
@@ -851,7 +1453,7 @@ func (h *AdminHandler) formatMemberButtonText(member models.MemberInfo, sortType
 	case models.SortByCreationOrder:
 		return baseText // По дате добавления показываем только имя
 	case models.SortByExpiryDate:
-		return fmt.Sprintf("%s (%s)", baseText, member.GetExpiryStatus())
+		return fmt.Sprintf("%s (%s)", baseText, member.GetExpiryStatus(h.localizer))
 	case models.SortByTrafficTotal:
 		if member.TotalTraffic > 0 {
 			totalGB := float64(member.TotalTraffic) / (1024 * 1024 * 1024)
@@ -892,14 +1494,69 @@ func (h *AdminHandler) processTrustedUsernameInput(c telebot.Context) error {
 func (h *AdminHandler) handleCallback(ctx context.Context, c telebot.Context) error {
 	data := c.Callback().Data
 
+	// Handle inline main menu callbacks
+	if strings.HasPrefix(data, menuCallbackPrefix) {
+		return h.handleMenuCallback(c, data)
+	}
+
+	// Handle inline confirmation callbacks
+	if strings.HasPrefix(data, confirmCallbackPrefix) {
+		return h.handleConfirmCallback(c, data)
+	}
+
+	// Handle bulk enable/disable selection callbacks
+	if strings.HasPrefix(data, bulkCallbackPrefix) {
+		return h.handleBulkCallback(c, data)
+	}
+
+	// Handle inbound enable/disable picks
+	if strings.HasPrefix(data, inboundTogglePrefix) {
+		return h.handleInboundToggleCallback(c, data)
+	}
+
+	// Handle retries of a failed member creation
+	if data == retryCreationData {
+		return h.handleRetryCreationCallback(c)
+	}
+
+	// Handle per-inbound traffic reset callbacks
+	if strings.HasPrefix(data, resetInboundPrefix) {
+		return h.handleResetInboundCallback(c, data)
+	}
+
+	// Handle notices about a traffic reset
+	if data == resetNotifyData {
+		return h.handleResetNotifyCallback(c)
+	}
+
+	// Handle detailed usage filter and sort callbacks
+	if strings.HasPrefix(data, usageReportPrefix) {
+		return h.handleUsageReportCallback(c, data)
+	}
+
+	// Handle plain subscription link requests
+	if strings.HasPrefix(data, copyLinkPrefix) {
+		return h.handleCopyLinkCallback(c, data)
+	}
+
+	// Handle subscription unification callbacks
+	if strings.HasPrefix(data, unifySubIDPrefix) {
+		return h.handleUnifySubIDCallback(c, data)
+	}
+
+	// Handle revoke admin callbacks
+	if strings.HasPrefix(data, revokeAdminPrefix) {
+		return h.handleRevokeAdminCallback(c, data)
+	}
+
 	// Handle revoke trusted user callbacks
 	if strings.HasPrefix(data, "revoke_trusted_") {
 		telegramID, err := ParseRevokeTrustedCallback(data)
 		if err != nil {
-			return c.Send("Invalid selection.")
+			return c.Send(h.t(i18n.InvalidSelectionShort))
 		}
 		return h.trustedHandler.HandleRevokeTrusted(ctx, c, telegramID)
 	}
 
-	return c.Send("Unknown action.")
+	return c.Send(h.t(i18n.UnknownAction))
 }
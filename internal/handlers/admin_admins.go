@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+	"xui-tg-admin/internal/validation"
+)
+
+// revokeAdminPrefix is the callback data prefix for revoking a runtime admin
+const revokeAdminPrefix = "revoke_admin_"
+
+// handleAddAdmin handles the Add Admin command
+func (h *AdminHandler) handleAddAdmin(c telebot.Context) error {
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitingAdminIdentifier); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.AdminAddPrompt), h.createReturnKeyboard())
+}
+
+// processAdminIdentifier processes the Telegram ID or @username of a new admin
+func (h *AdminHandler) processAdminIdentifier(c telebot.Context) error {
+	text := strings.TrimSpace(c.Text())
+
+	// Check for return to main menu
+	if h.getButtonCommand(text) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	var telegramID int64
+	var username string
+	if strings.HasPrefix(text, "@") {
+		username = strings.TrimPrefix(text, "@")
+		if err := validation.ValidateTelegramUsername(username); err != nil {
+			return h.sendTextMessage(c, h.t(i18n.AdminInvalidIdentifier), h.createReturnKeyboard())
+		}
+		// Resolved to the real ID on the admin's first message
+		telegramID = helpers.PseudoTelegramID(username)
+	} else {
+		id, err := strconv.ParseInt(text, 10, 64)
+		if err != nil || id <= 0 {
+			return h.sendTextMessage(c, h.t(i18n.AdminInvalidIdentifier), h.createReturnKeyboard())
+		}
+		telegramID = id
+	}
+
+	admin := models.AdminUser{TelegramID: telegramID, Username: username}
+	if h.isConfigAdmin(telegramID) || h.storageService.IsAdmin(telegramID) {
+		return h.sendTextMessage(c, h.t(i18n.AdminAlreadyAdmin, formatAdminName(admin)), h.createMainKeyboard(permissions.Admin))
+	}
+	if username != "" {
+		if isAdmin, _ := h.storageService.IsAdminByUsername(username); isAdmin {
+			return h.sendTextMessage(c, h.t(i18n.AdminAlreadyAdmin, formatAdminName(admin)), h.createMainKeyboard(permissions.Admin))
+		}
+	}
+
+	if err := h.storageService.AddAdmin(telegramID, username, c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to add admin: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.AdminAddFailed), h.createMainKeyboard(permissions.Admin))
+	}
+
+	h.logger.WithField("user_id", c.Sender().ID).Infof("Granted admin rights to %s", formatAdminName(admin))
+
+	if err := h.stateService.ClearState(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to clear user state: %v", err)
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.AdminAdded, formatAdminName(admin)), h.createMainKeyboard(permissions.Admin))
+}
+
+// handleRevokeAdmin handles the Revoke Admin command
+func (h *AdminHandler) handleRevokeAdmin(c telebot.Context) error {
+	admins := h.storageService.GetAdminUsers()
+	if len(admins) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.AdminNoneToRevoke), h.createMainKeyboard(permissions.Admin))
+	}
+
+	var keyboard [][]telebot.InlineButton
+	for _, admin := range admins {
+		keyboard = append(keyboard, []telebot.InlineButton{
+			{
+				Text: fmt.Sprintf("❌ %s", formatAdminButtonName(admin)),
+				Data: fmt.Sprintf("%s%d", revokeAdminPrefix, admin.TelegramID),
+			},
+		})
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.AdminSelectRevoke), &telebot.ReplyMarkup{InlineKeyboard: keyboard})
+}
+
+// handleRevokeAdminCallback revokes the runtime admin selected in the inline keyboard
+func (h *AdminHandler) handleRevokeAdminCallback(c telebot.Context, data string) error {
+	telegramID, err := strconv.ParseInt(strings.TrimPrefix(data, revokeAdminPrefix), 10, 64)
+	if err != nil {
+		return c.Send(h.t(i18n.InvalidSelectionShort))
+	}
+
+	var target *models.AdminUser
+	admins := h.storageService.GetAdminUsers()
+	for i := range admins {
+		if admins[i].TelegramID == telegramID {
+			target = &admins[i]
+			break
+		}
+	}
+	if target == nil {
+		return h.sendTextMessage(c, h.t(i18n.AdminNotFound), h.createMainKeyboard(permissions.Admin))
+	}
+
+	// Never leave the bot without an admin
+	if len(h.config.Telegram.AdminIDs)+len(admins) <= 1 {
+		return h.sendTextMessage(c, h.t(i18n.AdminLastAdmin), h.createMainKeyboard(permissions.Admin))
+	}
+
+	if err := h.storageService.RemoveAdmin(telegramID); err != nil {
+		h.logger.Errorf("Failed to remove admin: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.AdminRevokeFailed), h.createMainKeyboard(permissions.Admin))
+	}
+
+	h.logger.WithField("user_id", c.Sender().ID).Infof("Revoked admin rights from %s", formatAdminName(*target))
+	return h.sendTextMessage(c, h.t(i18n.AdminRevoked, formatAdminName(*target)), h.createMainKeyboard(permissions.Admin))
+}
+
+// isConfigAdmin checks if the user is an admin from TG_ADMIN_IDS
+func (h *AdminHandler) isConfigAdmin(telegramID int64) bool {
+	for _, id := range h.config.Telegram.AdminIDs {
+		if id == telegramID {
+			return true
+		}
+	}
+	return false
+}
+
+// formatAdminName formats an admin for HTML messages
+func formatAdminName(admin models.AdminUser) string {
+	if admin.Username != "" {
+		return "@" + html.EscapeString(admin.Username)
+	}
+	return fmt.Sprintf("<code>%d</code>", admin.TelegramID)
+}
+
+// formatAdminButtonName formats an admin for button labels
+func formatAdminButtonName(admin models.AdminUser) string {
+	if admin.Username != "" {
+		return "@" + admin.Username
+	}
+	return strconv.FormatInt(admin.TelegramID, 10)
+}
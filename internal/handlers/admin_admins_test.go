@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"xui-tg-admin/internal/models"
+)
+
+func TestProcessAdminIdentifierRejectsInvalidUsername(t *testing.T) {
+	h := newTestAdminHandler(t, "http://127.0.0.1:1")
+	bot, _ := newTestBot(t)
+
+	for _, text := range []string{"@", "@bob", "@<b>mallory</b>", "@" + strings.Repeat("a", 40)} {
+		if err := h.stateService.SetState(testAdminID, models.UserState{State: models.AwaitingAdminIdentifier}); err != nil {
+			t.Fatalf("failed to set state: %v", err)
+		}
+		if err := h.processAdminIdentifier(textUpdate(bot, text)); err != nil {
+			t.Fatalf("processAdminIdentifier(%q) failed: %v", text, err)
+		}
+	}
+
+	if admins := h.storageService.GetAdminUsers(); len(admins) != 0 {
+		t.Errorf("stored admins %v, want invalid usernames rejected", admins)
+	}
+}
+
+func TestFormatAdminNameEscapesUsername(t *testing.T) {
+	got := formatAdminName(models.AdminUser{Username: "<b>x</b>"})
+	if want := "@&lt;b&gt;x&lt;/b&gt;"; got != want {
+		t.Errorf("formatAdminName = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// handleBackup handles the Backup command
+func (h *AdminHandler) handleBackup(c telebot.Context) error {
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.BackupConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	backup := h.storageBackup()
+	backup.Inbounds = inbounds
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		h.logger.Errorf("Failed to marshal backup: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.BackupBuildFailed), h.createMainKeyboard(permissions.Admin))
+	}
+
+	fileName := fmt.Sprintf("backup-%s.json", time.Now().Format(constants.DateFormat))
+	caption := h.t(i18n.BackupCaption, len(backup.TrustedUsers), len(backup.AdminUsers), len(backup.VpnAccounts), len(backup.Inbounds))
+	if err := h.sendDocument(c, data, fileName, caption); err != nil {
+		return h.sendTextMessage(c, h.t(i18n.BackupSendFailed), h.createMainKeyboard(permissions.Admin))
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.BackupDone), h.createMainKeyboard(permissions.Admin))
+}
+
+// storageBackup returns everything the bot keeps in its storage file as a backup, without
+// the panel inbounds
+func (h *AdminHandler) storageBackup() models.Backup {
+	return models.Backup{
+		CreatedAt:    time.Now().Unix(),
+		TrustedUsers: h.storageService.GetTrustedUsers(),
+		VpnAccounts:  h.storageService.GetAllVpnAccounts(),
+		AdminUsers:   h.storageService.GetAdminUsers(),
+		Notes:        h.storageService.GetNotes(),
+		Tags:         h.storageService.GetMemberTags(),
+		Passwords:    h.storageService.GetPasswords(),
+	}
+}
+
+// handleRestore handles the Restore command
+func (h *AdminHandler) handleRestore(c telebot.Context) error {
+	err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitingRestoreDocument)
+	if err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.RestorePrompt), h.createReturnKeyboard())
+}
+
+// processRestoreDocument processes the uploaded backup file
+func (h *AdminHandler) processRestoreDocument(c telebot.Context) error {
+	// Check for return to main menu
+	if h.getButtonCommand(c.Text()) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	if c.Message() == nil || c.Message().Document == nil {
+		return h.sendTextMessage(c, h.t(i18n.RestoreUploadDocument), h.createReturnKeyboard())
+	}
+
+	reader, err := c.Bot().File(&c.Message().Document.File)
+	if err != nil {
+		h.logger.Errorf("Failed to download backup file: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.RestoreDownloadFailed), h.createReturnKeyboard())
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		h.logger.Errorf("Failed to read backup file: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.RestoreReadFailed), h.createReturnKeyboard())
+	}
+
+	var backup models.Backup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return h.sendTextMessage(c, h.t(i18n.RestoreInvalidBackup, err), h.createReturnKeyboard())
+	}
+
+	// Keep only the storage part of the backup for the confirmation step
+	backup.Inbounds = nil
+	restoreData, err := json.Marshal(backup)
+	if err != nil {
+		h.logger.Errorf("Failed to marshal restore data: %v", err)
+		return err
+	}
+
+	if err := h.stateService.WithPayload(c.Sender().ID, string(restoreData)); err != nil {
+		h.logger.Errorf("Failed to set payload: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitConfirmRestore); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConfirmationRequested(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to set confirmation time: %v", err)
+		return err
+	}
+
+	createdAt := time.Unix(backup.CreatedAt, 0).Format(constants.TimestampFormat)
+	return h.sendTextMessage(c, h.t(i18n.RestoreConfirm, createdAt, len(backup.TrustedUsers), len(backup.AdminUsers), len(backup.VpnAccounts), len(backup.Notes)+len(backup.Tags)+len(backup.Passwords)), h.createInlineConfirmKeyboard(confirmRestoreData))
+}
+
+// processConfirmRestore processes the restore confirmation
+func (h *AdminHandler) processConfirmRestore(c telebot.Context) error {
+	confirmation := c.Text()
+
+	// Check for return to main menu
+	if h.getButtonCommand(confirmation) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	if h.getButtonCommand(confirmation) != commands.Confirm {
+		return h.sendTextMessage(c, h.t(i18n.RestoreInvalidSelection), h.createReturnKeyboard())
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+
+	if userState.Payload == nil {
+		return h.sendTextMessage(c, h.t(i18n.SessionBackupLost), h.createReturnKeyboard())
+	}
+
+	if h.isConfirmationExpired(userState) {
+		return h.handleExpiredConfirmation(c)
+	}
+
+	return h.executeRestore(c, *userState.Payload)
+}
+
+// executeRestore applies the backup stored in the confirmation payload
+func (h *AdminHandler) executeRestore(c telebot.Context, payload string) error {
+	var backup models.Backup
+	if err := json.Unmarshal([]byte(payload), &backup); err != nil {
+		h.logger.Errorf("Failed to parse restore data: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.SessionBackupCorrupted), h.createReturnKeyboard())
+	}
+
+	result := h.restoreStorage(backup)
+
+	if err := h.stateService.ClearState(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to clear user state: %v", err)
+	}
+
+	message := h.t(i18n.RestoreDone, result.trusted, result.admins, result.accounts, result.memberData)
+	if len(result.errors) > 0 {
+		message += h.t(i18n.RestorePartialErrors, len(result.errors))
+	}
+
+	return h.sendTextMessage(c, message, h.createMainKeyboard(permissions.Admin))
+}
+
+// restoreResult counts what restoreStorage added
+type restoreResult struct {
+	trusted    int
+	admins     int
+	accounts   int
+	memberData int // notes, tag sets and passwords
+	errors     []error
+}
+
+// restoreStorage adds missing trusted users, admins, VPN accounts and member notes, tags
+// and passwords from a backup. Entries that already exist are kept.
+func (h *AdminHandler) restoreStorage(backup models.Backup) restoreResult {
+	var result restoreResult
+
+	for _, user := range backup.TrustedUsers {
+		if h.storageService.IsTrusted(user.TelegramID) {
+			continue
+		}
+		if err := h.storageService.AddTrusted(user.TelegramID, user.Username); err != nil {
+			h.logger.Errorf("Failed to restore trusted user @%s: %v", user.Username, err)
+			result.errors = append(result.errors, err)
+			continue
+		}
+		result.trusted++
+	}
+
+	for _, admin := range backup.AdminUsers {
+		if h.storageService.IsAdmin(admin.TelegramID) {
+			continue
+		}
+		if exists, _ := h.storageService.IsAdminByUsername(admin.Username); exists {
+			continue
+		}
+		if err := h.storageService.AddAdmin(admin.TelegramID, admin.Username, admin.AddedBy); err != nil {
+			h.logger.Errorf("Failed to restore admin %d: %v", admin.TelegramID, err)
+			result.errors = append(result.errors, err)
+			continue
+		}
+		result.admins++
+	}
+
+	for _, account := range backup.VpnAccounts {
+		if h.hasVpnAccount(account.AddedBy, account.Username) {
+			continue
+		}
+		if err := h.storageService.AddVpnAccount(account.Username, account.Password, account.AddedBy); err != nil {
+			h.logger.Errorf("Failed to restore VPN account %s: %v", account.Username, err)
+			result.errors = append(result.errors, err)
+			continue
+		}
+		result.accounts++
+	}
+
+	for username, note := range backup.Notes {
+		if note == "" || h.storageService.GetNote(username) != "" {
+			continue
+		}
+		if err := h.storageService.SetNote(username, note); err != nil {
+			h.logger.Errorf("Failed to restore note of %s: %v", username, err)
+			result.errors = append(result.errors, err)
+			continue
+		}
+		result.memberData++
+	}
+
+	for username, tags := range backup.Tags {
+		added := false
+		for _, tag := range tags {
+			if slices.Contains(h.storageService.GetTags(username), tag) {
+				continue
+			}
+			if err := h.storageService.AddTag(username, tag); err != nil {
+				h.logger.Errorf("Failed to restore tag %s of %s: %v", tag, username, err)
+				result.errors = append(result.errors, err)
+				continue
+			}
+			added = true
+		}
+		if added {
+			result.memberData++
+		}
+	}
+
+	stored := h.storageService.GetPasswords()
+	for username, password := range backup.Passwords {
+		if _, ok := stored[username]; ok || password == "" {
+			continue
+		}
+		if err := h.storageService.SetPassword(username, password); err != nil {
+			h.logger.Errorf("Failed to restore password of %s: %v", username, err)
+			result.errors = append(result.errors, err)
+			continue
+		}
+		result.memberData++
+	}
+
+	return result
+}
+
+// hasVpnAccount checks whether the given user already owns an account with this username
+func (h *AdminHandler) hasVpnAccount(addedBy int64, username string) bool {
+	for _, account := range h.storageService.GetUserAccounts(addedBy) {
+		if account.Username == username {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"xui-tg-admin/internal/models"
+)
+
+func TestBackupRestoreRoundTrip(t *testing.T) {
+	source := newTestAdminHandler(t, "http://127.0.0.1:1")
+	storage := source.storageService
+
+	for _, err := range []error{
+		storage.AddTrusted(100, "reseller"),
+		storage.AddVpnAccount("bob", "trusted-secret", 100),
+		storage.AddAdmin(200, "helper", testAdminID),
+		storage.SetNote("alice", "pays yearly"),
+		storage.AddTag("alice", "family"),
+		storage.AddTag("alice", "vip"),
+		storage.SetPassword("alice", "admin-secret"),
+	} {
+		if err != nil {
+			t.Fatalf("failed to fill storage: %v", err)
+		}
+	}
+
+	// The target already has a note for carol, which the backup must not overwrite
+	target := newTestAdminHandler(t, "http://127.0.0.1:1")
+	if err := target.storageService.SetNote("carol", "kept"); err != nil {
+		t.Fatalf("failed to set note: %v", err)
+	}
+	backup := source.storageBackup()
+	backup.Notes["carol"] = "from the backup"
+
+	// The backup goes through JSON, as it does in the file and the confirmation payload
+	data, err := json.Marshal(backup)
+	if err != nil {
+		t.Fatalf("failed to marshal backup: %v", err)
+	}
+	backup = models.Backup{}
+	if err := json.Unmarshal(data, &backup); err != nil {
+		t.Fatalf("failed to unmarshal backup: %v", err)
+	}
+
+	result := target.restoreStorage(backup)
+	if len(result.errors) > 0 {
+		t.Fatalf("restore failed: %v", result.errors)
+	}
+	if result.trusted != 1 || result.admins != 1 || result.accounts != 1 || result.memberData != 3 {
+		t.Errorf("restored %+v, want 1 trusted user, 1 admin, 1 account and 3 member entries", result)
+	}
+
+	restored := target.storageService
+	if !restored.IsTrusted(100) {
+		t.Error("trusted user wasn't restored")
+	}
+	if !restored.IsAdmin(200) {
+		t.Error("runtime admin wasn't restored")
+	}
+	if got := restored.GetPassword("bob"); got != "trusted-secret" {
+		t.Errorf("account password = %q, want trusted-secret", got)
+	}
+	if got := restored.GetNote("alice"); got != "pays yearly" {
+		t.Errorf("note = %q, want pays yearly", got)
+	}
+	if got := restored.GetNote("carol"); got != "kept" {
+		t.Errorf("existing note = %q, want it kept", got)
+	}
+	if got := restored.GetTags("alice"); !slices.Equal(got, []string{"family", "vip"}) {
+		t.Errorf("tags = %v, want [family vip]", got)
+	}
+	if got := restored.GetPassword("alice"); got != "admin-secret" {
+		t.Errorf("member password = %q, want admin-secret", got)
+	}
+
+	// Restoring again adds nothing
+	if again := target.restoreStorage(backup); again.trusted+again.admins+again.accounts+again.memberData != 0 {
+		t.Errorf("second restore added %+v, want nothing", again)
+	}
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// broadcastRecipient is a user an announcement is delivered to
+type broadcastRecipient struct {
+	id       int64
+	username string
+}
+
+// label shows the recipient as @username, or by ID when the username isn't known
+func (r broadcastRecipient) label() string {
+	if r.username != "" {
+		return "@" + html.EscapeString(r.username)
+	}
+	return "<code>" + strconv.FormatInt(r.id, 10) + "</code>"
+}
+
+// handleBroadcast asks for the announcement to send to everyone the bot knows
+func (h *AdminHandler) handleBroadcast(c telebot.Context) error {
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitingBroadcastMessage); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.BroadcastPrompt), h.createReturnKeyboard())
+}
+
+// processBroadcastMessage shows the typed announcement with its recipient count and asks
+// for confirmation, offering to include former trusted users who still own accounts
+func (h *AdminHandler) processBroadcastMessage(c telebot.Context) error {
+	text := strings.TrimSpace(c.Text())
+
+	// Check for return to main menu
+	if h.getButtonCommand(text) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	if text == "" {
+		return h.sendTextMessage(c, h.t(i18n.BroadcastEmpty), h.createReturnKeyboard())
+	}
+
+	staff := len(h.broadcastRecipients(c.Sender().ID, false))
+	everyone := len(h.broadcastRecipients(c.Sender().ID, true))
+	if everyone == 0 {
+		if err := h.stateService.ClearState(c.Sender().ID); err != nil {
+			h.logger.Errorf("Failed to clear user state: %v", err)
+		}
+		return h.sendTextMessage(c, h.t(i18n.BroadcastNoRecipients), h.createMainKeyboard(permissions.Admin))
+	}
+
+	if err := h.stateService.WithPayload(c.Sender().ID, text); err != nil {
+		h.logger.Errorf("Failed to set payload: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitConfirmBroadcast); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConfirmationRequested(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to set confirmation time: %v", err)
+		return err
+	}
+
+	message := h.t(i18n.BroadcastConfirm, html.EscapeString(text), staff)
+	var rows [][]telebot.InlineButton
+	if staff > 0 {
+		rows = append(rows, []telebot.InlineButton{{Text: h.t(i18n.BroadcastSendButton, staff), Data: confirmBroadcast}})
+	}
+	if everyone > staff {
+		message += h.t(i18n.BroadcastOwnersHint, everyone-staff)
+		rows = append(rows, []telebot.InlineButton{{Text: h.t(i18n.BroadcastOwnersButton, everyone), Data: confirmBroadcastAll}})
+	}
+	rows = append(rows, []telebot.InlineButton{{Text: "❌ " + commands.Label(commands.Cancel), Data: confirmCancelData}})
+
+	return h.sendTextMessage(c, message, &telebot.ReplyMarkup{InlineKeyboard: rows})
+}
+
+// processConfirmBroadcast processes a typed confirmation, which sends the announcement to
+// admins and trusted users only
+func (h *AdminHandler) processConfirmBroadcast(c telebot.Context) error {
+	confirmation := c.Text()
+
+	// Check for return to main menu
+	if h.getButtonCommand(confirmation) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	if h.getButtonCommand(confirmation) != commands.Confirm {
+		return h.sendTextMessage(c, h.t(i18n.BroadcastInvalidSelection), h.createReturnKeyboard())
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if userState.Payload == nil || h.isConfirmationExpired(userState) {
+		return h.handleExpiredConfirmation(c)
+	}
+
+	return h.executeBroadcast(c, *userState.Payload, false)
+}
+
+// executeBroadcast delivers the confirmed announcement one recipient at a time, paced to
+// stay under Telegram's rate limits, and reports who didn't get it
+func (h *AdminHandler) executeBroadcast(c telebot.Context, text string, includeOwners bool) error {
+	if clearErr := h.stateService.ClearState(c.Sender().ID); clearErr != nil {
+		h.logger.Errorf("Failed to clear user state: %v", clearErr)
+	}
+
+	recipients := h.broadcastRecipients(c.Sender().ID, includeOwners)
+	log := h.logger.WithFields(logrus.Fields{
+		"operation":  "broadcast",
+		"user_id":    c.Sender().ID,
+		"recipients": len(recipients),
+		"owners":     includeOwners,
+	})
+
+	loadingMsg, _ := h.sendTextMessageWithReturn(c, h.t(i18n.BroadcastInProgress, len(recipients)), nil)
+
+	delivered := 0
+	var failures strings.Builder
+	for i, recipient := range recipients {
+		if i > 0 {
+			time.Sleep(constants.BroadcastInterval * time.Millisecond)
+		}
+
+		_, err := h.sendWithFloodRetry(func() (*telebot.Message, error) {
+			return c.Bot().Send(&telebot.User{ID: recipient.id}, text)
+		})
+		if err != nil {
+			log.WithError(err).WithField("recipient", recipient.id).Warn("Failed to deliver broadcast")
+			failures.WriteString(h.t(i18n.BroadcastFailedLine, recipient.label(), html.EscapeString(err.Error())))
+			continue
+		}
+		delivered++
+	}
+
+	if loadingMsg != nil {
+		c.Bot().Delete(loadingMsg)
+	}
+
+	log.WithField("delivered", delivered).Info("Sent broadcast")
+	message := h.t(i18n.BroadcastDone, delivered, len(recipients)) + failures.String()
+	return h.sendLongMessage(c, message, h.createMainKeyboard(permissions.Admin))
+}
+
+// broadcastRecipients lists every admin and trusted user once, and with includeOwners also
+// whoever else still owns VPN accounts. The sender is left out since they wrote the message.
+// Users added by username keep a placeholder ID until they message the bot, so they are
+// skipped like they are for reminders.
+func (h *AdminHandler) broadcastRecipients(senderID int64, includeOwners bool) []broadcastRecipient {
+	seen := map[int64]bool{senderID: true}
+	var recipients []broadcastRecipient
+	add := func(id int64, username string) {
+		if seen[id] {
+			return
+		}
+		// Marked as seen either way, so the owners pass doesn't add a placeholder back
+		seen[id] = true
+		if username != "" && id == helpers.PseudoTelegramID(username) {
+			return
+		}
+		recipients = append(recipients, broadcastRecipient{id: id, username: username})
+	}
+
+	for _, id := range h.config.Telegram.AdminIDs {
+		add(id, "")
+	}
+	for _, admin := range h.storageService.GetAdminUsers() {
+		add(admin.TelegramID, admin.Username)
+	}
+	for _, user := range h.storageService.GetTrustedUsers() {
+		add(user.TelegramID, user.Username)
+	}
+	if includeOwners {
+		for _, account := range h.storageService.GetAllVpnAccounts() {
+			add(account.AddedBy, "")
+		}
+	}
+
+	return recipients
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"testing"
+
+	"xui-tg-admin/internal/helpers"
+)
+
+func TestBroadcastRecipientsSkipsPlaceholderIDs(t *testing.T) {
+	h := newTestAdminHandler(t, "http://127.0.0.1:1")
+	storage := h.storageService
+
+	pending := helpers.PseudoTelegramID("pending_user")
+	for _, err := range []error{
+		storage.AddTrusted(100, "reseller"),
+		storage.AddTrusted(pending, "pending_user"),
+		storage.AddVpnAccount("bob", "secret", pending),
+		storage.AddAdmin(helpers.PseudoTelegramID("new_admin"), "new_admin", testAdminID),
+	} {
+		if err != nil {
+			t.Fatalf("failed to fill storage: %v", err)
+		}
+	}
+
+	recipients := h.broadcastRecipients(testAdminID, true)
+	if len(recipients) != 1 || recipients[0].id != 100 {
+		t.Errorf("recipients = %+v, want only the trusted user with a real ID", recipients)
+	}
+}
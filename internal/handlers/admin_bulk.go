@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// Callback data for the bulk enable/disable selection keyboard
+const (
+	bulkCallbackPrefix = "bulk_"
+	bulkSelectPrefix   = "bulk_sel_"
+	bulkPagePrefix     = "bulk_page_"
+	bulkEnableData     = "bulk_enable"
+	bulkDisableData    = "bulk_disable"
+)
+
+// errBulkNoClients means the member has no clients left to enable or disable
+var errBulkNoClients = errors.New("no clients found")
+
+// bulkInboundError is a failed update of a member's client in one inbound
+type bulkInboundError struct {
+	InboundID int
+	Err       error
+}
+
+func (e *bulkInboundError) Error() string {
+	return fmt.Sprintf("inbound %d: %v", e.InboundID, e.Err)
+}
+
+func (e *bulkInboundError) Unwrap() error {
+	return e.Err
+}
+
+// bulkResult is the outcome of a bulk operation for a single member
+type bulkResult struct {
+	Username string
+	Err      error
+}
+
+// handleBulkToggle starts the multi-select flow for enabling or disabling members
+func (h *AdminHandler) handleBulkToggle(c telebot.Context) error {
+	members, err := h.xrayService.GetAllMembersWithInfo(context.Background(), models.SortByCreationOrder)
+	if err != nil {
+		h.logger.Errorf("Failed to get members with info: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	if len(members) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.NoUsersYet), h.createMainKeyboard(permissions.Admin))
+	}
+
+	// Start with an empty selection
+	if err := h.stateService.SetState(c.Sender().ID, models.UserState{State: models.AwaitBulkSelection}); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	if err := h.sendTextMessage(c, h.t(i18n.BulkReturnHint), h.createReturnKeyboard()); err != nil {
+		return err
+	}
+	return h.sendTextMessage(c, h.t(i18n.BulkSelectPrompt), h.createBulkSelectionKeyboard(members, nil, 0))
+}
+
+// processBulkSelection handles text input while the selection keyboard is open
+func (h *AdminHandler) processBulkSelection(c telebot.Context) error {
+	if h.getButtonCommand(c.Text()) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.BulkUseButtons), h.createReturnKeyboard())
+}
+
+// createBulkSelectionKeyboard builds the member checkboxes of one page, the page navigation
+// and the enable/disable actions. Telegram caps inline keyboards at 100 buttons, so members
+// are shown BulkPageSize at a time.
+func (h *AdminHandler) createBulkSelectionKeyboard(members []models.MemberInfo, selected map[string]bool, page int) *telebot.ReplyMarkup {
+	var rows [][]telebot.InlineButton
+	var row []telebot.InlineButton
+
+	pages := (len(members) + constants.BulkPageSize - 1) / constants.BulkPageSize
+	page = min(max(page, 0), max(pages-1, 0))
+	start := page * constants.BulkPageSize
+	end := min(start+constants.BulkPageSize, len(members))
+
+	for _, member := range members[start:end] {
+		check := "☐"
+		if selected[member.BaseUsername] {
+			check = "☑️"
+		}
+		status := "🟢"
+		if !member.Enable {
+			status = "🔴"
+		}
+
+		row = append(row, telebot.InlineButton{
+			Text: fmt.Sprintf("%s %s %s", check, status, member.BaseUsername),
+			Data: bulkSelectPrefix + memberToken(member.BaseUsername),
+		})
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	if pages > 1 {
+		var nav []telebot.InlineButton
+		if page > 0 {
+			nav = append(nav, telebot.InlineButton{Text: "◀️", Data: fmt.Sprintf("%s%d", bulkPagePrefix, page-1)})
+		}
+		nav = append(nav, telebot.InlineButton{Text: h.t(i18n.BulkPageIndicator, page+1, pages), Data: fmt.Sprintf("%s%d", bulkPagePrefix, page)})
+		if page < pages-1 {
+			nav = append(nav, telebot.InlineButton{Text: "▶️", Data: fmt.Sprintf("%s%d", bulkPagePrefix, page+1)})
+		}
+		rows = append(rows, nav)
+	}
+
+	rows = append(rows, []telebot.InlineButton{
+		{Text: h.t(i18n.BulkEnableButton, len(selected)), Data: bulkEnableData},
+		{Text: h.t(i18n.BulkDisableButton, len(selected)), Data: bulkDisableData},
+	})
+	rows = append(rows, []telebot.InlineButton{
+		{Text: "❌ " + commands.Label(commands.Cancel), Data: confirmCancelData},
+	})
+
+	return &telebot.ReplyMarkup{InlineKeyboard: rows}
+}
+
+// handleBulkCallback toggles a member in the selection or applies the chosen action
+func (h *AdminHandler) handleBulkCallback(c telebot.Context, data string) error {
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+
+	if userState.State != models.AwaitBulkSelection {
+		if err := c.Respond(); err != nil {
+			h.logger.Errorf("Failed to answer callback: %v", err)
+		}
+		if c.Message() != nil {
+			if _, err := c.Bot().EditReplyMarkup(c.Message(), nil); err != nil {
+				h.logger.Errorf("Failed to remove selection keyboard: %v", err)
+			}
+		}
+		return h.handleExpiredConfirmation(c)
+	}
+
+	switch {
+	case strings.HasPrefix(data, bulkSelectPrefix):
+		return h.toggleBulkSelection(c, strings.TrimPrefix(data, bulkSelectPrefix))
+	case strings.HasPrefix(data, bulkPagePrefix):
+		page, err := strconv.Atoi(strings.TrimPrefix(data, bulkPagePrefix))
+		if err != nil {
+			break
+		}
+		return h.showBulkPage(c, userState, page)
+	case data == bulkEnableData:
+		return h.applyBulkToggle(c, userState.Selected, true)
+	case data == bulkDisableData:
+		return h.applyBulkToggle(c, userState.Selected, false)
+	}
+
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+	return c.Send(h.t(i18n.UnknownAction))
+}
+
+// toggleBulkSelection flips the checkbox of the member the token stands for and redraws the
+// keyboard. A member deleted since the keyboard was drawn just drops out of it.
+func (h *AdminHandler) toggleBulkSelection(c telebot.Context, token string) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	members, err := h.xrayService.GetAllMembersWithInfo(context.Background(), models.SortByCreationOrder)
+	if err != nil {
+		h.logger.Errorf("Failed to get members with info: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	for _, member := range members {
+		if memberToken(member.BaseUsername) != token {
+			continue
+		}
+		if err := h.stateService.ToggleSelected(c.Sender().ID, member.BaseUsername); err != nil {
+			h.logger.Errorf("Failed to update selection: %v", err)
+			return err
+		}
+		break
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+
+	if c.Message() != nil {
+		if _, err := c.Bot().EditReplyMarkup(c.Message(), h.createBulkSelectionKeyboard(members, userState.Selected, userState.Page)); err != nil {
+			h.logger.Errorf("Failed to update selection keyboard: %v", err)
+		}
+	}
+	return nil
+}
+
+// showBulkPage redraws the selection keyboard on another page, keeping the selection
+func (h *AdminHandler) showBulkPage(c telebot.Context, userState *models.UserState, page int) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	// The page indicator button leads to the page already shown
+	if page == userState.Page {
+		return nil
+	}
+
+	if err := h.stateService.WithPage(c.Sender().ID, page); err != nil {
+		h.logger.Errorf("Failed to set page: %v", err)
+		return err
+	}
+
+	members, err := h.xrayService.GetAllMembersWithInfo(context.Background(), models.SortByCreationOrder)
+	if err != nil {
+		h.logger.Errorf("Failed to get members with info: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	if c.Message() != nil {
+		if _, err := c.Bot().EditReplyMarkup(c.Message(), h.createBulkSelectionKeyboard(members, userState.Selected, page)); err != nil {
+			h.logger.Errorf("Failed to update selection keyboard: %v", err)
+		}
+	}
+	return nil
+}
+
+// applyBulkToggle enables or disables every selected member and reports the result per member
+func (h *AdminHandler) applyBulkToggle(c telebot.Context, selected map[string]bool, enable bool) error {
+	if len(selected) == 0 {
+		return c.Respond(&telebot.CallbackResponse{Text: h.t(i18n.BulkNothingSelected)})
+	}
+
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+	if c.Message() != nil {
+		if _, err := c.Bot().EditReplyMarkup(c.Message(), nil); err != nil {
+			h.logger.Errorf("Failed to remove selection keyboard: %v", err)
+		}
+	}
+
+	loadingText := h.t(i18n.BulkInProgress, len(selected))
+	loadingMsg, _ := h.sendTextMessageWithReturn(c, loadingText, nil)
+	ctx, stop := h.startOperation(c, loadingMsg, loadingText)
+	defer func() {
+		stop()
+		if loadingMsg != nil {
+			c.Bot().Delete(loadingMsg)
+		}
+	}()
+
+	inbounds, err := h.xrayService.GetInbounds(ctx)
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	usernames := make([]string, 0, len(selected))
+	for username := range selected {
+		usernames = append(usernames, username)
+	}
+	sort.Slice(usernames, func(i, j int) bool {
+		return strings.ToLower(usernames[i]) < strings.ToLower(usernames[j])
+	})
+
+	results := make([]bulkResult, 0, len(usernames))
+	for _, username := range usernames {
+		if ctx.Err() != nil {
+			h.logger.Warnf("Bulk toggle timed out after %d of %d users", len(results), len(usernames))
+			break
+		}
+		results = append(results, bulkResult{
+			Username: username,
+			Err:      h.setClientsEnabled(ctx, c.Sender().ID, inbounds, username, enable),
+		})
+	}
+
+	if err := h.stateService.ClearState(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to clear user state: %v", err)
+	}
+
+	message := h.formatBulkResults(results, enable)
+	if len(results) < len(usernames) {
+		message += h.t(i18n.OperationTimedOut, len(results), len(usernames))
+	}
+	return h.sendTextMessage(c, message, h.createMainKeyboard(permissions.Admin))
+}
+
+// setClientsEnabled sets the enable flag on every client of a member. It returns the
+// failed inbounds joined as *bulkInboundError, or errBulkNoClients if the member has none.
+func (h *AdminHandler) setClientsEnabled(ctx context.Context, senderID int64, inbounds []models.Inbound, username string, enable bool) error {
+	updated := 0
+	var errs []error
+
+	for _, inbound := range inbounds {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			h.logger.Warnf("Failed to parse inbound settings: %v", err)
+			continue
+		}
+
+		for _, inboundClient := range clients {
+			if !helpers.IsEmailMatchingBaseUsername(inboundClient.Email, username) {
+				continue
+			}
+
+			client := inboundClient.ToClient()
+			client.Enable = enable
+
+			log := h.logger.WithFields(logrus.Fields{
+				"operation":  "bulk_toggle",
+				"user_id":    senderID,
+				"inbound_id": inbound.ID,
+				"email":      inboundClient.Email,
+				"enable":     enable,
+			})
+
+			if err := h.xrayService.UpdateClient(ctx, inbound.ID, inbound.ClientKey(inboundClient), client); err != nil {
+				log.WithError(err).Error("Failed to update client")
+				errs = append(errs, &bulkInboundError{InboundID: inbound.ID, Err: err})
+				continue
+			}
+
+			log.Info("Updated client")
+			updated++
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if updated == 0 {
+		return errBulkNoClients
+	}
+	return nil
+}
+
+// formatBulkResults builds the per-member report for a bulk enable/disable
+func (h *AdminHandler) formatBulkResults(results []bulkResult, enable bool) string {
+	action := h.t(i18n.BulkActionDisabled)
+	if enable {
+		action = h.t(i18n.BulkActionEnabled)
+	}
+
+	var sb strings.Builder
+	succeeded := 0
+	for _, result := range results {
+		if result.Err != nil {
+			sb.WriteString(h.t(i18n.BulkResultFailed, html.EscapeString(result.Username), html.EscapeString(h.bulkErrorText(result.Err))))
+			continue
+		}
+		succeeded++
+		sb.WriteString(h.t(i18n.BulkResultOK, html.EscapeString(result.Username)))
+	}
+
+	return h.t(i18n.BulkResultHeader, action, succeeded, len(results), sb.String())
+}
+
+// bulkErrorText describes why a member couldn't be toggled, one part per failed inbound
+func (h *AdminHandler) bulkErrorText(err error) string {
+	if errors.Is(err, errBulkNoClients) {
+		return h.t(i18n.BulkNoClients)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return h.panelErrorText(err)
+	}
+
+	var parts []string
+	for _, inboundErr := range joined.Unwrap() {
+		var failed *bulkInboundError
+		if errors.As(inboundErr, &failed) {
+			parts = append(parts, h.t(i18n.PanelErrInbound, failed.InboundID, h.panelErrorText(failed.Err)))
+			continue
+		}
+		parts = append(parts, h.panelErrorText(inboundErr))
+	}
+	return strings.Join(parts, "; ")
+}
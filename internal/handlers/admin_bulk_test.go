@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"xui-tg-admin/internal/models"
+)
+
+func TestBulkSelectionCallbackDataFits(t *testing.T) {
+	h := newTestAdminHandler(t, "http://127.0.0.1:1")
+	members := []models.MemberInfo{
+		{BaseUsername: "alice"},
+		{BaseUsername: strings.Repeat("a_very_long_panel_username_", 4)},
+	}
+
+	markup := h.createBulkSelectionKeyboard(members, nil, 0)
+	for _, row := range markup.InlineKeyboard {
+		for _, button := range row {
+			if len(button.Data) > 64 {
+				t.Errorf("button %q has %d bytes of callback data, want at most 64", button.Text, len(button.Data))
+			}
+		}
+	}
+
+	if a, b := memberToken(members[0].BaseUsername), memberToken(members[1].BaseUsername); a == b {
+		t.Errorf("members share the token %q", a)
+	}
+}
+
+func TestSetClientsEnabledErrors(t *testing.T) {
+	panel := &updateClientPanel{failInbounds: map[int]bool{2: true}}
+	server := httptest.NewServer(panel)
+	t.Cleanup(server.Close)
+	h := newTestAdminHandler(t, server.URL)
+
+	inbounds := []models.Inbound{
+		{ID: 1, Protocol: "vless", Settings: `{"clients":[{"id":"a","email":"alice-1"}]}`},
+		{ID: 2, Protocol: "vless", Settings: `{"clients":[{"id":"b","email":"alice-2"}]}`},
+	}
+
+	err := h.setClientsEnabled(context.Background(), testAdminID, inbounds, "alice", false)
+	var failed *bulkInboundError
+	if !errors.As(err, &failed) || failed.InboundID != 2 {
+		t.Fatalf("err = %v, want a failure in inbound 2", err)
+	}
+	if text := h.bulkErrorText(err); !strings.HasPrefix(text, "Inbound 2: ") {
+		t.Errorf("bulkErrorText = %q, want it to name inbound 2", text)
+	}
+
+	err = h.setClientsEnabled(context.Background(), testAdminID, inbounds, "bob", false)
+	if !errors.Is(err, errBulkNoClients) {
+		t.Errorf("err = %v, want errBulkNoClients for a member without clients", err)
+	}
+}
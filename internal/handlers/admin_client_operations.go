@@ -3,12 +3,17 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	telebot "gopkg.in/telebot.v3"
 
 	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/constants"
 	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
 	"xui-tg-admin/internal/models"
 	"xui-tg-admin/internal/permissions"
 	"xui-tg-admin/internal/validation"
@@ -22,67 +27,153 @@ type ClientCreationParams struct {
 	CommonSubId     string
 	BaseFingerprint string
 	SenderID        int64
+	Password        string // member secret for password-based protocols, empty if none is used
 }
 
-// createClientsForAllInbounds creates clients for all enabled inbounds
+// inboundResult is the outcome of adding a client to a single inbound
+type inboundResult struct {
+	email string
+	err   error
+}
+
+// createClientsForAllInbounds creates clients for all enabled inbounds, a few inbounds at a time
 func (h *AdminHandler) createClientsForAllInbounds(ctx context.Context, params ClientCreationParams, enabledInbounds []models.Inbound) ([]string, []string, bool) {
+	// Each inbound writes only its own slot, so results keep the inbound order
+	results := make([]inboundResult, len(enabledInbounds))
+	sem := make(chan struct{}, constants.MaxConcurrentInboundRequests)
+	var wg sync.WaitGroup
+
+	for i, inbound := range enabledInbounds {
+		wg.Add(1)
+		go func(i int, inbound models.Inbound) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = h.createClientForInbound(ctx, params, inbound, i+1)
+		}(i, inbound)
+	}
+	wg.Wait()
+
 	var addErrors []string
 	var createdEmails []string
 	var addedToAny bool
 
-	for i, inbound := range enabledInbounds {
-		email := helpers.FormatEmailWithInboundNumber(params.BaseUsername, i+1)
-		fingerprint := fmt.Sprintf("%s-%d", params.BaseFingerprint, i+1)
-
-		client := models.Client{
-			ID:          email,
-			Enable:      true,
-			Email:       email,
-			TotalGB:     0, // Unlimited traffic
-			LimitIP:     0, // No IP limit
-			ExpiryTime:  &params.ExpiryTime,
-			TgID:        fmt.Sprintf("%d", params.SenderID),
-			SubID:       params.CommonSubId,
-			Fingerprint: fingerprint,
-		}
-
-		if err := h.xrayService.AddClient(ctx, inbound.ID, client); err != nil {
-			h.logger.Errorf("Failed to add client to inbound %d: %v", inbound.ID, err)
-			addErrors = append(addErrors, fmt.Sprintf("Inbound %d: %v", inbound.ID, err))
+	for i, result := range results {
+		if result.err != nil {
+			addErrors = append(addErrors, h.t(i18n.PanelErrInbound, enabledInbounds[i].ID, h.panelErrorText(result.err)))
 			continue
 		}
-
 		addedToAny = true
-		createdEmails = append(createdEmails, email)
-		h.logger.Infof("Successfully added client %s to inbound %d", email, inbound.ID)
+		createdEmails = append(createdEmails, result.email)
 	}
 
 	return createdEmails, addErrors, addedToAny
 }
 
-// getEnabledInbounds filters and returns only enabled inbounds
-func (h *AdminHandler) getEnabledInbounds(ctx context.Context) ([]models.Inbound, error) {
-	inbounds, err := h.xrayService.GetInbounds(ctx)
+// createClientForInbound adds the member's client with the given inbound number to one inbound
+func (h *AdminHandler) createClientForInbound(ctx context.Context, params ClientCreationParams, inbound models.Inbound, number int) inboundResult {
+	email := helpers.FormatEmailWithInboundNumber(params.BaseUsername, number)
+	fingerprint := fmt.Sprintf("%s-%d", params.BaseFingerprint, number)
+
+	log := h.logger.WithFields(logrus.Fields{
+		"operation":  "create_client",
+		"user_id":    params.SenderID,
+		"inbound_id": inbound.ID,
+		"email":      email,
+	})
+
+	clientID, err := models.GenerateUUID()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get inbounds: %w", err)
+		log.WithError(err).Error("Failed to generate client ID")
+		return inboundResult{email: email, err: err}
 	}
 
-	if len(inbounds) == 0 {
-		return nil, fmt.Errorf("no inbounds available")
+	client := models.Client{
+		ID:          clientID,
+		Enable:      true,
+		Email:       email,
+		TotalGB:     0, // Unlimited traffic
+		LimitIP:     0, // No IP limit
+		ExpiryTime:  &params.ExpiryTime,
+		TgID:        fmt.Sprintf("%d", params.SenderID),
+		SubID:       params.CommonSubId,
+		Fingerprint: fingerprint,
+		Password:    inbound.ClientPassword(params.Password),
 	}
 
-	var enabledInbounds []models.Inbound
-	for _, inbound := range inbounds {
-		if inbound.Enable {
-			enabledInbounds = append(enabledInbounds, inbound)
+	if err := h.xrayService.AddClient(ctx, inbound.ID, client); err != nil {
+		log.WithError(err).Error("Failed to add client to inbound")
+		return inboundResult{email: email, err: err}
+	}
+
+	log.Info("Successfully added client to inbound")
+	return inboundResult{email: email}
+}
+
+// findExistingMember returns the base username of an existing member matching baseUsername
+// case-insensitively, or an empty string if there is none
+func (h *AdminHandler) findExistingMember(ctx context.Context, baseUsername string) (string, error) {
+	emails, err := h.xrayService.GetAllMembers(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, email := range emails {
+		existing := helpers.ExtractBaseUsername(email)
+		if strings.EqualFold(existing, baseUsername) {
+			return existing, nil
 		}
 	}
 
-	if len(enabledInbounds) == 0 {
-		return nil, fmt.Errorf("no enabled inbounds available")
+	return "", nil
+}
+
+// renameClients rewrites the email of every client belonging to oldUsername, keeping the inbound suffix
+func (h *AdminHandler) renameClients(ctx context.Context, oldUsername, newUsername string) (int, []string) {
+	inbounds, err := h.xrayService.GetInbounds(ctx)
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return 0, []string{"Failed to get server configuration"}
+	}
+
+	var renameErrors []string
+	renamed := 0
+
+	for _, inbound := range inbounds {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			h.logger.Warnf("Failed to parse inbound settings: %v", err)
+			continue
+		}
+
+		for _, inboundClient := range clients {
+			if !helpers.IsEmailMatchingBaseUsername(inboundClient.Email, oldUsername) {
+				continue
+			}
+
+			client := inboundClient.ToClient()
+			client.Email = helpers.ReplaceBaseUsername(inboundClient.Email, newUsername)
+
+			log := h.logger.WithFields(logrus.Fields{
+				"operation":  "rename_client",
+				"inbound_id": inbound.ID,
+				"email":      inboundClient.Email,
+				"new_email":  client.Email,
+			})
+
+			if err := h.xrayService.UpdateClient(ctx, inbound.ID, inbound.ClientKey(inboundClient), client); err != nil {
+				log.WithError(err).Error("Failed to rename client")
+				renameErrors = append(renameErrors, h.t(i18n.PanelErrInbound, inbound.ID, h.panelErrorText(err)))
+				continue
+			}
+
+			log.Info("Renamed client")
+			renamed++
+		}
 	}
 
-	return enabledInbounds, nil
+	return renamed, renameErrors
 }
 
 // sendSubscriptionInfo sends subscription information and QR code to user
@@ -93,6 +184,7 @@ func (h *AdminHandler) sendSubscriptionInfo(c telebot.Context, params ClientCrea
 		params.ExpiryTime,
 		createdEmails,
 		params.CommonSubId,
+		params.Password,
 		addErrors,
 		h.config.Server.SubURLPrefix,
 	)
@@ -102,10 +194,9 @@ func (h *AdminHandler) sendSubscriptionInfo(c telebot.Context, params ClientCrea
 	}
 
 	if len(createdEmails) > 0 {
-		subURL := fmt.Sprintf("%s%s?name=%s", h.config.Server.SubURLPrefix, params.CommonSubId, params.CommonSubId)
-		if err := h.sendTextMessage(c, "QR code for subscription:", nil); err != nil {
+		if err := h.sendTextMessage(c, h.t(i18n.SubscriptionQRCaption), nil); err != nil {
 			h.logger.Errorf("Failed to send QR code message: %v", err)
-		} else if err := h.sendQRCode(c, subURL); err != nil {
+		} else if err := h.sendSubscriptionQR(c, params.CommonSubId, h.qrCaption(params.BaseUsername)); err != nil {
 			h.logger.Errorf("Failed to send QR code: %v", err)
 		}
 	}
@@ -117,16 +208,16 @@ func (h *AdminHandler) sendSubscriptionInfo(c telebot.Context, params ClientCrea
 
 	// Show main menu
 	markup := h.createMainKeyboard(permissions.Admin)
-	return h.sendTextMessage(c, "🎉 <b>User Created Successfully!</b>\n\nThe new user is ready to connect to the VPN.", markup)
+	return h.sendTextMessage(c, h.t(i18n.AddMemberDone), markup)
 }
 
-// calculateExpiryTime calculates expiry time based on duration
-func calculateExpiryTime(durationStr string) (int64, error) {
+// calculateExpiryTime calculates expiry time based on a duration of at most maxDays days
+func calculateExpiryTime(durationStr string, maxDays int) (int64, error) {
 	if durationStr == commands.Infinite {
 		return 0, nil
 	}
 
-	days, err := validation.ValidateDuration(durationStr)
+	days, err := validation.ValidateDuration(durationStr, maxDays)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/models"
+)
+
+// addClientPanel is an X-UI panel whose addClient endpoint is slow, fails for some
+// inbounds and records how many requests it served at once
+type addClientPanel struct {
+	failInbounds map[int]bool
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	added       map[int]string // inbound ID -> email
+}
+
+func (p *addClientPanel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/login":
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "test"})
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	case "/xui/API/inbounds/addClient":
+		p.mu.Lock()
+		p.inFlight++
+		p.maxInFlight = max(p.maxInFlight, p.inFlight)
+		p.mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		var body struct {
+			ID       int    `json:"id"`
+			Settings string `json:"settings"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var settings models.InboundSettings
+		json.Unmarshal([]byte(body.Settings), &settings)
+
+		p.mu.Lock()
+		p.inFlight--
+		if !p.failInbounds[body.ID] && len(settings.Clients) == 1 {
+			p.added[body.ID] = settings.Clients[0].Email
+		}
+		p.mu.Unlock()
+
+		if p.failInbounds[body.ID] {
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "msg": "Duplicate email"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestCreateClientsForAllInboundsOrderAndConcurrency(t *testing.T) {
+	panel := &addClientPanel{failInbounds: map[int]bool{3: true, 7: true}, added: make(map[int]string)}
+	server := httptest.NewServer(panel)
+	t.Cleanup(server.Close)
+	h := newTestAdminHandler(t, server.URL)
+
+	var inbounds []models.Inbound
+	for id := 1; id <= 10; id++ {
+		inbounds = append(inbounds, models.Inbound{ID: id, Protocol: "vless"})
+	}
+
+	params := ClientCreationParams{BaseUsername: "alice", BaseFingerprint: "fp", SenderID: testAdminID}
+	created, addErrors, addedToAny := h.createClientsForAllInbounds(context.Background(), params, inbounds)
+
+	if !addedToAny {
+		t.Fatalf("addedToAny = false, want true")
+	}
+
+	var wantCreated []string
+	for id := 1; id <= 10; id++ {
+		if !panel.failInbounds[id] {
+			wantCreated = append(wantCreated, fmt.Sprintf("alice-%d", id))
+		}
+	}
+	if !reflect.DeepEqual(created, wantCreated) {
+		t.Errorf("created = %v, want %v in inbound order", created, wantCreated)
+	}
+
+	// The n-th inbound gets the client numbered n
+	for id, email := range panel.added {
+		if want := fmt.Sprintf("alice-%d", id); email != want {
+			t.Errorf("inbound %d got client %s, want %s", id, email, want)
+		}
+	}
+
+	if len(addErrors) != 2 {
+		t.Fatalf("addErrors = %v, want 2 errors", addErrors)
+	}
+	for i, id := range []int{3, 7} {
+		if want := fmt.Sprintf("Inbound %d: ", id); !strings.HasPrefix(addErrors[i], want) {
+			t.Errorf("addErrors[%d] = %q, want it to start with %q", i, addErrors[i], want)
+		}
+	}
+
+	if panel.maxInFlight > constants.MaxConcurrentInboundRequests {
+		t.Errorf("%d addClient requests ran at once, want at most %d", panel.maxInFlight, constants.MaxConcurrentInboundRequests)
+	}
+	if panel.maxInFlight < 2 {
+		t.Errorf("addClient requests never overlapped, want them to run concurrently")
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+)
+
+// handleExportConfig sends a member's subscription link, connection details and QR code as one zip document
+func (h *AdminHandler) handleExportConfig(c telebot.Context, username string) error {
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.ViewConfigInboundsFailed, h.panelErrorText(err)), h.createUserActionKeyboard())
+	}
+
+	connections := h.collectConnections(inbounds, username)
+	if len(connections) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.MemberNotFound, username), h.createUserActionKeyboard())
+	}
+
+	var sb strings.Builder
+	sb.WriteString(h.t(i18n.ConfigExportTitle, username, h.config.Server.Name))
+
+	files := []helpers.ExportFile{{Name: username + ".txt"}}
+
+	// Clients without a subscription ID still get their connection details
+	if subID := primarySubID(h.collectSubIDs(inbounds, username)); subID != "" {
+		subURL := h.subscriptionURL(subID)
+		sb.WriteString(h.t(i18n.ConfigExportSubscription, subURL))
+
+		qrBytes, err := h.qrService.GenerateQR(subURL)
+		if err != nil {
+			h.logger.Errorf("Failed to generate QR code: %v", err)
+			return h.sendTextMessage(c, h.t(i18n.ConfigExportFailed, username), h.createUserActionKeyboard())
+		}
+		files = append(files, helpers.ExportFile{Name: username + "-qr.png", Data: qrBytes})
+	}
+
+	for _, conn := range connections {
+		inbound := conn.Inbound
+		uri := conn.URI
+		switch {
+		case errors.Is(conn.Err, helpers.ErrUnsupportedProtocol):
+			uri = h.t(i18n.ConfigExportUnsupported)
+		case conn.Err != nil:
+			uri = h.t(i18n.ConfigExportInboundFailed)
+		}
+		sb.WriteString(h.t(i18n.ConfigExportInbound, inbound.Remark, inbound.Protocol, inbound.Port, uri))
+	}
+	files[0].Data = []byte(sb.String())
+
+	archive, err := helpers.ZipFiles(files)
+	if err != nil {
+		h.logger.Errorf("Failed to build config archive for %s: %v", username, err)
+		return h.sendTextMessage(c, h.t(i18n.ConfigExportFailed, username), h.createUserActionKeyboard())
+	}
+
+	fileName := fmt.Sprintf("%s-config.zip", username)
+	if err := h.sendDocument(c, archive, fileName, h.t(i18n.ConfigExportCaption, username)); err != nil {
+		return h.sendTextMessage(c, h.t(i18n.ConfigExportFailed, username), h.createUserActionKeyboard())
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+)
+
+// Callback data for inline confirmations of destructive admin actions
+const (
+	confirmCallbackPrefix = "confirm_"
+	confirmDeletePrefix   = "confirm_delete_"
+	confirmResetAllPrefix = "confirm_reset_all_"
+	confirmRestoreData    = "confirm_restore"
+	confirmPurgeExpired   = "confirm_purge_expired"
+	confirmPruneAccounts  = "confirm_prune_accounts"
+	confirmInboundPrefix  = "confirm_inbound_"
+	confirmBroadcast      = "confirm_broadcast"
+	confirmBroadcastAll   = "confirm_broadcast_all"
+	confirmCancelData     = "confirm_cancel"
+)
+
+// memberToken identifies a member in callback data. Panel usernames can be long enough to
+// push the data past Telegram's 64-byte limit, a hash of them can't.
+func memberToken(username string) string {
+	h := fnv.New64a()
+	h.Write([]byte(username))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// createInlineConfirmKeyboard creates an inline Confirm/Cancel keyboard for the given confirm callback data
+func (h *AdminHandler) createInlineConfirmKeyboard(confirmData string) *telebot.ReplyMarkup {
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{
+				{Text: "✅ " + commands.Label(commands.Confirm), Data: confirmData},
+				{Text: "❌ " + commands.Label(commands.Cancel), Data: confirmCancelData},
+			},
+		},
+	}
+}
+
+// handleConfirmCallback validates an inline confirmation against the current state and runs the action
+func (h *AdminHandler) handleConfirmCallback(c telebot.Context, data string) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	// Drop the inline keyboard so the prompt can't be tapped again
+	if c.Message() != nil {
+		if _, err := c.Bot().EditReplyMarkup(c.Message(), nil); err != nil {
+			h.logger.Errorf("Failed to remove confirmation keyboard: %v", err)
+		}
+	}
+
+	if data == confirmCancelData {
+		return h.handleStart(c)
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+
+	// The callback must match the confirmation the user is currently being asked for
+	switch {
+	case strings.HasPrefix(data, confirmDeletePrefix):
+		// The member is kept in the payload, the token only ties the keyboard to it
+		token := strings.TrimPrefix(data, confirmDeletePrefix)
+		if userState.State != models.AwaitConfirmMemberDeletion || userState.Payload == nil || memberToken(*userState.Payload) != token {
+			return h.handleExpiredConfirmation(c)
+		}
+		if h.isConfirmationExpired(userState) {
+			return h.handleExpiredConfirmation(c)
+		}
+		return h.executeDeletion(c, *userState.Payload)
+	case strings.HasPrefix(data, confirmResetAllPrefix):
+		clientCount := strings.TrimPrefix(data, confirmResetAllPrefix)
+		if userState.State != models.AwaitConfirmResetUsersNetworkUsage || userState.Payload == nil || *userState.Payload != clientCount {
+			return h.handleExpiredConfirmation(c)
+		}
+		expectedClients, err := strconv.Atoi(clientCount)
+		if err != nil || h.isConfirmationExpired(userState) {
+			return h.handleExpiredConfirmation(c)
+		}
+		return h.executeResetAllTraffic(c, expectedClients)
+	case data == confirmPurgeExpired:
+		if userState.State != models.AwaitConfirmDeleteExpired || userState.Payload == nil || h.isConfirmationExpired(userState) {
+			return h.handleExpiredConfirmation(c)
+		}
+		return h.executeDeleteExpired(c, strings.Split(*userState.Payload, ","))
+	case data == confirmPruneAccounts:
+		if userState.State != models.AwaitConfirmPruneAccounts || userState.Payload == nil || h.isConfirmationExpired(userState) {
+			return h.handleExpiredConfirmation(c)
+		}
+		return h.executePruneAccounts(c, *userState.Payload)
+	case strings.HasPrefix(data, confirmInboundPrefix):
+		choice := strings.TrimPrefix(data, confirmInboundPrefix)
+		if userState.State != models.AwaitConfirmInboundToggle || userState.Payload == nil || *userState.Payload != choice {
+			return h.handleExpiredConfirmation(c)
+		}
+		if h.isConfirmationExpired(userState) {
+			return h.handleExpiredConfirmation(c)
+		}
+		return h.executeInboundToggle(c, choice)
+	case data == confirmBroadcast || data == confirmBroadcastAll:
+		if userState.State != models.AwaitConfirmBroadcast || userState.Payload == nil || h.isConfirmationExpired(userState) {
+			return h.handleExpiredConfirmation(c)
+		}
+		return h.executeBroadcast(c, *userState.Payload, data == confirmBroadcastAll)
+	case data == confirmRestoreData:
+		if userState.State != models.AwaitConfirmRestore || userState.Payload == nil || h.isConfirmationExpired(userState) {
+			return h.handleExpiredConfirmation(c)
+		}
+		return h.executeRestore(c, *userState.Payload)
+	default:
+		return c.Send(h.t(i18n.UnknownAction))
+	}
+}
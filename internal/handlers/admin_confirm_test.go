@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfirmDeleteCallbackDataFits(t *testing.T) {
+	h := newTestAdminHandler(t, "http://127.0.0.1:1")
+	username := strings.Repeat("a_very_long_panel_username_", 4)
+
+	markup := h.createInlineConfirmKeyboard(confirmDeletePrefix + memberToken(username))
+	for _, button := range markup.InlineKeyboard[0] {
+		if len(button.Data) > 64 {
+			t.Errorf("button %q has %d bytes of callback data, want at most 64", button.Text, len(button.Data))
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+	"xui-tg-admin/pkg/xrayclient"
+)
+
+// handleDeleteExpired lists expired members and asks to delete all of them at once
+func (h *AdminHandler) handleDeleteExpired(c telebot.Context) error {
+	members, err := h.xrayService.GetAllMembersWithInfo(context.Background(), models.SortByExpiryDate)
+	if err != nil {
+		h.logger.Errorf("Failed to get members with info: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	var usernames []string
+	var sb strings.Builder
+	for _, member := range members {
+		if !member.IsExpired {
+			continue
+		}
+		usernames = append(usernames, member.BaseUsername)
+		sb.WriteString(h.t(i18n.ExpiredMemberLine, html.EscapeString(member.BaseUsername)))
+	}
+
+	if len(usernames) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.ExpiredNone), h.createMainKeyboard(permissions.Admin))
+	}
+
+	// Usernames can't contain commas, so the list fits in the payload
+	if err := h.stateService.WithPayload(c.Sender().ID, strings.Join(usernames, ",")); err != nil {
+		h.logger.Errorf("Failed to set payload: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitConfirmDeleteExpired); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConfirmationRequested(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to set confirmation time: %v", err)
+		return err
+	}
+
+	// A long list is split across messages, with the confirm buttons on the last one
+	markup := h.createInlineConfirmKeyboard(confirmPurgeExpired)
+	return h.sendLongMessage(c, h.t(i18n.ExpiredConfirm, len(usernames), sb.String()), markup)
+}
+
+// processConfirmDeleteExpired processes a typed confirmation for deleting expired members
+func (h *AdminHandler) processConfirmDeleteExpired(c telebot.Context) error {
+	confirmation := c.Text()
+
+	// Check for return to main menu
+	if h.getButtonCommand(confirmation) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	if h.getButtonCommand(confirmation) != commands.Confirm {
+		return h.sendTextMessage(c, h.t(i18n.ExpiredInvalidSelection), h.createReturnKeyboard())
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if userState.Payload == nil || h.isConfirmationExpired(userState) {
+		return h.handleExpiredConfirmation(c)
+	}
+
+	return h.executeDeleteExpired(c, strings.Split(*userState.Payload, ","))
+}
+
+// executeDeleteExpired removes the confirmed expired members from every inbound, one at a
+// time, so a timeout reports how far it got and only the deleted members lose their data
+func (h *AdminHandler) executeDeleteExpired(c telebot.Context, usernames []string) error {
+	loadingText := h.t(i18n.ExpiredDeleteInProgress, len(usernames))
+	loadingMsg, _ := h.sendTextMessageWithReturn(c, loadingText, nil)
+
+	ctx, stop := h.startOperation(c, loadingMsg, loadingText)
+	defer func() {
+		stop()
+		if loadingMsg != nil {
+			c.Bot().Delete(loadingMsg)
+		}
+	}()
+
+	log := h.logger.WithFields(logrus.Fields{
+		"operation": "delete_expired",
+		"user_id":   c.Sender().ID,
+		"count":     len(usernames),
+	})
+
+	var deleted []string
+	var deleteErrors []string
+	for _, username := range usernames {
+		if ctx.Err() != nil {
+			break
+		}
+
+		err := h.xrayService.RemoveClients(ctx, []string{username})
+		if err != nil && ctx.Err() != nil {
+			// The watchdog cut this request short; it counts as not processed
+			break
+		}
+		// A member whose clients are already gone is as good as deleted
+		if err != nil && !errors.Is(err, xrayclient.ErrClientNotFound) {
+			log.WithError(err).WithField("username", username).Error("Failed to delete expired member")
+			deleteErrors = append(deleteErrors, fmt.Sprintf("%s: %s", html.EscapeString(username), h.panelErrorText(err)))
+			continue
+		}
+		deleted = append(deleted, username)
+	}
+	processed := len(deleted) + len(deleteErrors)
+
+	if clearErr := h.stateService.ClearState(c.Sender().ID); clearErr != nil {
+		h.logger.Errorf("Failed to clear user state: %v", clearErr)
+	}
+
+	if len(deleted) > 0 {
+		if err := h.storageService.RemoveMemberData(deleted...); err != nil {
+			log.WithError(err).Error("Failed to remove notes and tags from storage")
+		}
+	}
+
+	log.WithFields(logrus.Fields{
+		"deleted": len(deleted),
+		"failed":  len(deleteErrors),
+	}).Info("Deleted expired members")
+
+	var message string
+	if len(deleted) == 0 && len(deleteErrors) > 0 {
+		message = h.t(i18n.ExpiredDeleteFailed, strings.Join(deleteErrors, "\n"))
+	} else {
+		message = h.t(i18n.ExpiredDeleteDone, len(deleted))
+		if len(deleteErrors) > 0 {
+			message += h.t(i18n.SomeErrorsOccurred, strings.Join(deleteErrors, "\n"))
+		}
+	}
+	if processed < len(usernames) {
+		log.Warnf("Deleting expired members timed out after %d of %d", processed, len(usernames))
+		message += h.t(i18n.OperationTimedOut, processed, len(usernames))
+	}
+	return h.sendTextMessage(c, message, h.createMainKeyboard(permissions.Admin))
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"xui-tg-admin/internal/models"
+)
+
+func TestDeleteExpiredSplitsLongList(t *testing.T) {
+	expired := time.Now().Add(-24 * time.Hour).UnixMilli()
+	inbound := models.Inbound{ID: 1, Protocol: "vless", Settings: `{"clients":[]}`}
+	for i := 1; i <= 200; i++ {
+		inbound.ClientStats = append(inbound.ClientStats, models.ClientStat{
+			ID:         i,
+			InboundID:  1,
+			Email:      fmt.Sprintf("expired_member_%s_%d-1", strings.Repeat("x", 20), i),
+			ExpiryTime: expired,
+		})
+	}
+	server := newInboundsPanel(t, []models.Inbound{inbound})
+	h := newTestAdminHandler(t, server.URL)
+	bot, api := newTestBot(t)
+
+	if err := h.handleDeleteExpired(textUpdate(bot, "")); err != nil {
+		t.Fatalf("handleDeleteExpired failed: %v", err)
+	}
+	if n := api.calls("sendMessage"); n < 2 {
+		t.Errorf("sent %d messages, want the list of 200 users split across several", n)
+	}
+}
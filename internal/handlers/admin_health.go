@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/permissions"
+)
+
+// handleHealthCheck handles the Health Check command
+func (h *AdminHandler) handleHealthCheck(c telebot.Context) error {
+	apiURL := redactURL(h.config.Server.APIURL)
+
+	result, err := h.xrayService.Ping(context.Background())
+	if err != nil {
+		h.logger.Errorf("Health check failed: %v", err)
+
+		message := h.t(i18n.HealthFailed, apiURL, formatLatency(result.LoginLatency))
+		if result.StatusCode != 0 {
+			message += h.t(i18n.HealthInbounds, result.StatusCode, formatLatency(result.InboundsLatency))
+		}
+		message += h.t(i18n.HealthError, err)
+
+		return h.sendTextMessage(c, message, h.createMainKeyboard(permissions.Admin))
+	}
+
+	message := h.t(i18n.HealthPassed,
+		apiURL,
+		formatLatency(result.LoginLatency),
+		result.StatusCode,
+		formatLatency(result.InboundsLatency),
+		result.InboundCount)
+
+	return h.sendTextMessage(c, message, h.createMainKeyboard(permissions.Admin))
+}
+
+// redactURL strips credentials from a URL so it can be shown to users
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "(invalid URL)"
+	}
+	parsed.User = nil
+	return parsed.String()
+}
+
+// formatLatency formats a latency in milliseconds
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%d ms", d.Milliseconds())
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// inboundTogglePrefix is the callback data prefix for picking an inbound to enable or disable
+const inboundTogglePrefix = "inbound_toggle_"
+
+// handleToggleInbound lists the inbounds with their state so one can be enabled or disabled
+func (h *AdminHandler) handleToggleInbound(c telebot.Context) error {
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.panelErrorMessage(err), h.createMainKeyboard(permissions.Admin))
+	}
+
+	return h.sendInboundToggleList(c, inbounds)
+}
+
+// sendInboundToggleList sends the inbound table with a button to flip each inbound
+func (h *AdminHandler) sendInboundToggleList(c telebot.Context, inbounds []models.Inbound) error {
+	if len(inbounds) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.InboundsOverviewEmpty), h.createMainKeyboard(permissions.Admin))
+	}
+
+	message := h.formatInboundsOverview(inbounds) + h.t(i18n.InboundTogglePrompt)
+	return h.sendTextMessage(c, message, h.createInboundToggleKeyboard(inbounds))
+}
+
+// createInboundToggleKeyboard creates one button per inbound showing whether it's enabled
+func (h *AdminHandler) createInboundToggleKeyboard(inbounds []models.Inbound) *telebot.ReplyMarkup {
+	var rows [][]telebot.InlineButton
+	for _, inbound := range inbounds {
+		status := "🔴"
+		if inbound.Enable {
+			status = "🟢"
+		}
+		rows = append(rows, []telebot.InlineButton{{
+			Text: fmt.Sprintf("%s #%d %s · %s :%d", status, inbound.ID, inbound.Remark, inbound.Protocol, inbound.Port),
+			Data: inboundTogglePrefix + strconv.Itoa(inbound.ID),
+		}})
+	}
+	return &telebot.ReplyMarkup{InlineKeyboard: rows}
+}
+
+// handleInboundToggleCallback asks to confirm flipping the picked inbound, since disabling
+// one cuts off every client on it
+func (h *AdminHandler) handleInboundToggleCallback(c telebot.Context, data string) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	inboundID, err := strconv.Atoi(strings.TrimPrefix(data, inboundTogglePrefix))
+	if err != nil {
+		return c.Send(h.t(i18n.InvalidSelectionShort))
+	}
+
+	inbound, err := h.findInbound(inboundID)
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.panelErrorMessage(err), h.createMainKeyboard(permissions.Admin))
+	}
+	if inbound == nil {
+		return h.sendTextMessage(c, h.t(i18n.InboundToggleNotFound, inboundID), h.createMainKeyboard(permissions.Admin))
+	}
+
+	// The payload pins the inbound and the target state, so a stale confirmation can't flip it back
+	choice := inboundToggleChoice(inbound.ID, !inbound.Enable)
+	if err := h.stateService.WithPayload(c.Sender().ID, choice); err != nil {
+		h.logger.Errorf("Failed to set payload: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitConfirmInboundToggle); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConfirmationRequested(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to set confirmation time: %v", err)
+		return err
+	}
+
+	key := i18n.InboundToggleConfirmDisable
+	if !inbound.Enable {
+		key = i18n.InboundToggleConfirmEnable
+	}
+	message := h.t(key, inbound.ID, html.EscapeString(inbound.Remark), inbound.Protocol, inbound.Port,
+		helpers.CountInboundClients(*inbound))
+
+	return h.sendTextMessage(c, message, h.createInlineConfirmKeyboard(confirmInboundPrefix+choice))
+}
+
+// processConfirmInboundToggle processes a typed confirmation for enabling or disabling an inbound
+func (h *AdminHandler) processConfirmInboundToggle(c telebot.Context) error {
+	confirmation := c.Text()
+
+	// Check for return to main menu
+	if h.getButtonCommand(confirmation) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	if h.getButtonCommand(confirmation) != commands.Confirm {
+		return h.sendTextMessage(c, h.t(i18n.InboundToggleInvalidSelection), h.createReturnKeyboard())
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if userState.Payload == nil || h.isConfirmationExpired(userState) {
+		return h.handleExpiredConfirmation(c)
+	}
+
+	return h.executeInboundToggle(c, *userState.Payload)
+}
+
+// executeInboundToggle applies a confirmed enable or disable and shows the updated list
+func (h *AdminHandler) executeInboundToggle(c telebot.Context, choice string) error {
+	if clearErr := h.stateService.ClearState(c.Sender().ID); clearErr != nil {
+		h.logger.Errorf("Failed to clear user state: %v", clearErr)
+	}
+
+	inboundID, enable, ok := parseInboundToggleChoice(choice)
+	if !ok {
+		return h.handleExpiredConfirmation(c)
+	}
+
+	log := h.logger.WithFields(logrus.Fields{
+		"operation":  "toggle_inbound",
+		"user_id":    c.Sender().ID,
+		"inbound_id": inboundID,
+		"enable":     enable,
+	})
+
+	if err := h.xrayService.SetInboundEnabled(context.Background(), inboundID, enable); err != nil {
+		log.WithError(err).Error("Failed to change inbound")
+		return h.sendTextMessage(c, h.t(i18n.InboundToggleFailed, inboundID, h.panelErrorText(err)), h.createMainKeyboard(permissions.Admin))
+	}
+	log.Info("Changed inbound")
+
+	// Re-read the inbounds so the list shows the state the panel actually saved
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+	}
+
+	remark := ""
+	for _, inbound := range inbounds {
+		if inbound.ID == inboundID {
+			remark = html.EscapeString(inbound.Remark)
+		}
+	}
+	key := i18n.InboundToggleDisabled
+	if enable {
+		key = i18n.InboundToggleEnabled
+	}
+	if err := h.sendTextMessage(c, h.t(key, inboundID, remark), h.createMainKeyboard(permissions.Admin)); err != nil {
+		return err
+	}
+
+	if len(inbounds) == 0 {
+		return nil
+	}
+	return h.sendInboundToggleList(c, inbounds)
+}
+
+// findInbound returns the inbound with the given ID, or nil if the panel has none
+func (h *AdminHandler) findInbound(inboundID int) (*models.Inbound, error) {
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for i := range inbounds {
+		if inbounds[i].ID == inboundID {
+			return &inbounds[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// inboundToggleChoice encodes an inbound and its target state, e.g. "3_off"
+func inboundToggleChoice(inboundID int, enable bool) string {
+	state := "off"
+	if enable {
+		state = "on"
+	}
+	return strconv.Itoa(inboundID) + "_" + state
+}
+
+// parseInboundToggleChoice decodes a choice made by inboundToggleChoice
+func parseInboundToggleChoice(choice string) (inboundID int, enable bool, ok bool) {
+	idPart, state, found := strings.Cut(choice, "_")
+	if !found || (state != "on" && state != "off") {
+		return 0, false, false
+	}
+	inboundID, err := strconv.Atoi(idPart)
+	if err != nil {
+		return 0, false, false
+	}
+	return inboundID, state == "on", true
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// handleFindInbounds lists the inbounds holding a member's clients, e.g. "/inbounds alice",
+// and the ones missing them after a partial creation failure. Without a username it shows
+// the setup of every inbound instead.
+func (h *AdminHandler) handleFindInbounds(c telebot.Context) error {
+	fields := strings.Fields(c.Text())
+	if len(fields) < 2 {
+		return h.handleInboundsOverview(c)
+	}
+	username := fields[1]
+
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.panelErrorMessage(err), h.createMainKeyboard(permissions.Admin))
+	}
+
+	var found, missing, excluded strings.Builder
+	foundCount := 0
+
+	for _, inbound := range inbounds {
+		// Clients are looked up in the settings and the stats, since either can lag behind the other
+		emails := make(map[string]bool)
+		var order []string
+
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			h.logger.Warnf("Failed to parse inbound settings: %v", err)
+		}
+		for _, client := range clients {
+			if helpers.IsEmailMatchingBaseUsername(client.Email, username) && !emails[client.Email] {
+				emails[client.Email] = true
+				order = append(order, client.Email)
+			}
+		}
+		for _, clientStat := range inbound.ClientStats {
+			if helpers.IsEmailMatchingBaseUsername(clientStat.Email, username) && !emails[clientStat.Email] {
+				emails[clientStat.Email] = true
+				order = append(order, clientStat.Email)
+			}
+		}
+
+		remark := html.EscapeString(inbound.Remark)
+		if len(order) == 0 && !h.config.Server.TargetsInbound(inbound.ID, inbound.Remark) {
+			// Deliberately left out of auto-creation, so not being there is expected
+			excluded.WriteString(h.t(i18n.FindInboundsMissingLine, inbound.ID, remark))
+			continue
+		}
+		if len(order) == 0 {
+			missing.WriteString(h.t(i18n.FindInboundsMissingLine, inbound.ID, remark))
+			continue
+		}
+
+		foundCount++
+		found.WriteString(h.t(i18n.FindInboundsLine, inbound.ID, remark, inbound.Protocol, inbound.Port,
+			html.EscapeString(strings.Join(order, ", "))))
+	}
+
+	if foundCount == 0 {
+		return h.sendTextMessage(c, h.t(i18n.MemberNotFound, html.EscapeString(username)), h.createMainKeyboard(permissions.Admin))
+	}
+
+	message := h.t(i18n.FindInboundsHeader, html.EscapeString(username), foundCount, len(inbounds)) + found.String()
+	if missing.Len() > 0 {
+		message += h.t(i18n.FindInboundsMissingHeader) + missing.String()
+	}
+	if excluded.Len() > 0 {
+		message += h.t(i18n.FindInboundsExcludedHeader) + excluded.String()
+	}
+
+	return h.sendLongMessage(c, message, h.createMainKeyboard(permissions.Admin))
+}
+
+// handleInboundsOverview shows every inbound's ID, remark, protocol, port, listen address,
+// enable status and client count, so admins can see which inbounds new members go to
+func (h *AdminHandler) handleInboundsOverview(c telebot.Context) error {
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.panelErrorMessage(err), h.createMainKeyboard(permissions.Admin))
+	}
+
+	if len(inbounds) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.InboundsOverviewEmpty), h.createMainKeyboard(permissions.Admin))
+	}
+
+	message := h.formatInboundsOverview(inbounds) + h.t(i18n.FindInboundsUsage)
+	return h.sendLongMessage(c, message, h.createMainKeyboard(permissions.Admin))
+}
+
+// formatInboundsOverview formats the number of enabled inbounds and the inbound table
+func (h *AdminHandler) formatInboundsOverview(inbounds []models.Inbound) string {
+	enabled := 0
+	for _, inbound := range inbounds {
+		if inbound.Enable {
+			enabled++
+		}
+	}
+
+	message := h.t(i18n.InboundsOverviewHeader, enabled, len(inbounds))
+
+	var excluded []string
+	for _, inbound := range inbounds {
+		if !h.config.Server.TargetsInbound(inbound.ID, inbound.Remark) {
+			excluded = append(excluded, fmt.Sprintf("#%d %s", inbound.ID, html.EscapeString(inbound.Remark)))
+		}
+	}
+	if len(excluded) > 0 {
+		message += h.t(i18n.InboundsOverviewExcluded, strings.Join(excluded, ", "))
+	}
+
+	var malformed []string
+	for _, inbound := range helpers.MalformedInbounds(inbounds) {
+		malformed = append(malformed, fmt.Sprintf("#%d %s", inbound.ID, html.EscapeString(inbound.Remark)))
+	}
+	if len(malformed) > 0 {
+		message += h.t(i18n.InboundsOverviewMalformed, strings.Join(malformed, ", "))
+	}
+
+	return message + helpers.FormatInboundsTable(inbounds)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"html"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+)
+
+// clearNoteInput is the text that removes a member's note
+const clearNoteInput = "-"
+
+// handleSetNoteRequest asks for a new note for the member
+func (h *AdminHandler) handleSetNoteRequest(c telebot.Context, username string) error {
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitingUserNote); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	message := h.t(i18n.NotePrompt, username)
+	if note := h.storageService.GetNote(username); note != "" {
+		message += h.t(i18n.NoteCurrent, html.EscapeString(note))
+	}
+
+	return h.sendTextMessage(c, message, h.createReturnKeyboard())
+}
+
+// processUserNote saves the note typed for the member and returns to the member actions
+func (h *AdminHandler) processUserNote(c telebot.Context) error {
+	text := strings.TrimSpace(c.Text())
+
+	// Check for return to main menu
+	if h.getButtonCommand(text) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if userState.Payload == nil {
+		return h.sendTextMessage(c, h.t(i18n.SessionUserLost), h.createReturnKeyboard())
+	}
+	username := *userState.Payload
+
+	if text == "" {
+		return h.sendTextMessage(c, h.t(i18n.NoteEmpty), h.createReturnKeyboard())
+	}
+
+	note := text
+	if note == clearNoteInput {
+		note = ""
+	}
+
+	if err := h.storageService.SetNote(username, note); err != nil {
+		h.logger.Errorf("Failed to save note for %s: %v", username, err)
+		return h.sendTextMessage(c, h.t(i18n.NoteSaveFailed), h.createUserActionKeyboard())
+	}
+
+	// Continue managing the member
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitMemberAction); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	confirmation := h.t(i18n.NoteSaved, username)
+	if note == "" {
+		confirmation = h.t(i18n.NoteCleared, username)
+	}
+
+	return h.sendTextMessage(c, confirmation+"\n\n"+h.manageMemberMessage(username), h.createUserActionKeyboard())
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// handleGetOnlineMembers handles the Online Members command
+func (h *AdminHandler) handleGetOnlineMembers(c telebot.Context) error {
+	ctx := context.Background()
+
+	// Get online users
+	onlineUsers, err := h.xrayService.GetOnlineUsers(ctx)
+	if err != nil {
+		h.logger.Errorf("Failed to get online users: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.OnlineConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	// Member info adds traffic and last-seen details; without it fall back to raw emails
+	members, err := h.xrayService.GetAllMembersWithInfo(ctx, models.SortByName)
+	if err != nil {
+		h.logger.Warnf("Failed to get member info for online list: %v", err)
+	}
+
+	return h.sendLongMessage(c, h.formatOnlineMembers(onlineUsers, members), h.createMainKeyboard(permissions.Admin))
+}
+
+// formatOnlineMembers groups online emails by member and lists recently seen offline members
+func (h *AdminHandler) formatOnlineMembers(onlineUsers []string, members []models.MemberInfo) string {
+	memberMap := make(map[string]models.MemberInfo, len(members))
+	for _, member := range members {
+		memberMap[member.BaseUsername] = member
+	}
+
+	// Count connections per member, keeping the panel's order
+	var onlineOrder []string
+	connections := make(map[string]int)
+	for _, email := range onlineUsers {
+		baseUsername := helpers.ExtractBaseUsername(email)
+		if _, ok := memberMap[baseUsername]; !ok {
+			baseUsername = email
+		}
+		if connections[baseUsername] == 0 {
+			onlineOrder = append(onlineOrder, baseUsername)
+		}
+		connections[baseUsername]++
+	}
+
+	var sb strings.Builder
+	if len(onlineOrder) == 0 {
+		sb.WriteString(h.t(i18n.OnlineNone))
+	} else {
+		sb.WriteString(h.t(i18n.OnlineHeader, len(onlineOrder)))
+
+		var largest int64
+		for _, name := range onlineOrder {
+			largest = max(largest, memberMap[name].TotalDown, memberMap[name].TotalUp)
+		}
+		unit := helpers.ResolveTrafficUnit(helpers.ParseTrafficUnit(h.config.TrafficUnit), largest)
+
+		for _, name := range onlineOrder {
+			member, ok := memberMap[name]
+			if !ok {
+				sb.WriteString(fmt.Sprintf("👤 %s\n", html.EscapeString(name)))
+				continue
+			}
+			sb.WriteString(h.t(i18n.OnlineMemberLine,
+				html.EscapeString(name),
+				connections[name],
+				unit.Value(member.TotalDown),
+				unit.Name,
+				unit.Value(member.TotalUp),
+				unit.Name))
+		}
+	}
+
+	// Members that dropped off recently, most recent first
+	now := time.Now()
+	cutoff := now.Add(-constants.RecentlySeenHours * time.Hour).UnixMilli()
+	var recent []models.MemberInfo
+	for _, member := range members {
+		if connections[member.BaseUsername] == 0 && member.LastOnline >= cutoff {
+			recent = append(recent, member)
+		}
+	}
+	sort.Slice(recent, func(i, j int) bool {
+		return recent[i].LastOnline > recent[j].LastOnline
+	})
+
+	if len(recent) > 0 {
+		sb.WriteString(h.t(i18n.OnlineRecentHeader, constants.RecentlySeenHours))
+		for _, member := range recent {
+			lastSeen := now.Sub(time.UnixMilli(member.LastOnline))
+			sb.WriteString(h.t(i18n.OnlineRecentLine, html.EscapeString(member.BaseUsername), h.formatAgo(lastSeen)))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatAgo formats how long ago something happened in the largest whole unit
+func (h *AdminHandler) formatAgo(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return h.t(i18n.AgoJustNow)
+	case d < time.Hour:
+		return h.t(i18n.AgoMinutes, int(d.Minutes()))
+	default:
+		return h.t(i18n.AgoHours, int(d.Hours()))
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"xui-tg-admin/internal/models"
+)
+
+func TestFormatOnlineMembersTrafficUnit(t *testing.T) {
+	members := []models.MemberInfo{{BaseUsername: "alice", TotalDown: 5 * 1024 * 1024, TotalUp: 1024 * 1024}}
+
+	tests := []struct {
+		unit string
+		want string
+	}{
+		{"", "↓ 5.00 MB ↑ 1.00 MB"},
+		{"GB", "↓ 0.00 GB ↑ 0.00 GB"},
+	}
+
+	for _, tt := range tests {
+		h := newTestAdminHandler(t, "http://127.0.0.1:1")
+		h.config.TrafficUnit = tt.unit
+
+		text := h.formatOnlineMembers([]string{"alice-1"}, members)
+		if !strings.Contains(text, tt.want) {
+			t.Errorf("unit %q: got %q, want it to contain %q", tt.unit, text, tt.want)
+		}
+	}
+}
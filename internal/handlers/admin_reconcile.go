@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// accountDrift lists where stored VPN accounts and the panel disagree
+type accountDrift struct {
+	// Orphaned are stored accounts whose user no longer exists in the panel
+	Orphaned []models.VpnAccount
+	// Untracked are panel users named like a trusted user's account but missing from storage
+	Untracked []untrackedAccount
+}
+
+// untrackedAccount is a panel user that looks like it belongs to a trusted user
+type untrackedAccount struct {
+	Username string
+	Owner    models.TrustedUser
+}
+
+// findAccountDrift compares stored VPN accounts with the base usernames present in the panel
+func findAccountDrift(accounts []models.VpnAccount, trusted []models.TrustedUser, members map[string]bool) accountDrift {
+	var drift accountDrift
+
+	stored := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		stored[strings.ToLower(account.Username)] = true
+		if !members[strings.ToLower(account.Username)] {
+			drift.Orphaned = append(drift.Orphaned, account)
+		}
+	}
+
+	// Trusted users create accounts named "<telegram username>-add<N>"
+	for member := range members {
+		if stored[member] {
+			continue
+		}
+		for _, user := range trusted {
+			prefix := strings.ToLower(strings.TrimPrefix(user.Username, "@")) + "-add"
+			if prefix == "-add" || !strings.HasPrefix(member, prefix) {
+				continue
+			}
+			if _, err := strconv.Atoi(strings.TrimPrefix(member, prefix)); err == nil {
+				drift.Untracked = append(drift.Untracked, untrackedAccount{Username: member, Owner: user})
+				break
+			}
+		}
+	}
+
+	return drift
+}
+
+// handleReconcile reports stored VPN accounts that drifted from the panel and offers to prune orphans
+func (h *AdminHandler) handleReconcile(c telebot.Context) error {
+	emails, err := h.xrayService.GetAllMembers(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get members: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	members := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		members[strings.ToLower(helpers.ExtractBaseUsername(email))] = true
+	}
+
+	drift := findAccountDrift(h.storageService.GetAllVpnAccounts(), h.storageService.GetTrustedUsers(), members)
+	if len(drift.Orphaned) == 0 && len(drift.Untracked) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.ReconcileClean), h.createMainKeyboard(permissions.Admin))
+	}
+
+	owners := make(map[int64]string)
+	for _, user := range h.storageService.GetTrustedUsers() {
+		owners[user.TelegramID] = user.Username
+	}
+
+	var sb strings.Builder
+	sb.WriteString(h.t(i18n.ReconcileHeader))
+	if len(drift.Orphaned) > 0 {
+		sb.WriteString(h.t(i18n.ReconcileOrphanedHeader, len(drift.Orphaned)))
+		for _, account := range drift.Orphaned {
+			owner, ok := owners[account.AddedBy]
+			if !ok {
+				owner = strconv.FormatInt(account.AddedBy, 10)
+			}
+			sb.WriteString(h.t(i18n.ReconcileAccountLine, html.EscapeString(account.Username), html.EscapeString(owner)))
+		}
+	}
+	if len(drift.Untracked) > 0 {
+		sb.WriteString(h.t(i18n.ReconcileUntrackedHeader, len(drift.Untracked)))
+		for _, account := range drift.Untracked {
+			sb.WriteString(h.t(i18n.ReconcileAccountLine, html.EscapeString(account.Username), html.EscapeString(account.Owner.Username)))
+		}
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"operation": "reconcile_accounts",
+		"orphaned":  len(drift.Orphaned),
+		"untracked": len(drift.Untracked),
+	}).Warn("Stored VPN accounts differ from the panel")
+
+	// Untracked users are left alone, there's no safe way to tell who really owns them
+	if len(drift.Orphaned) == 0 {
+		return h.sendLongMessage(c, sb.String(), h.createMainKeyboard(permissions.Admin))
+	}
+
+	ids := make([]string, len(drift.Orphaned))
+	for i, account := range drift.Orphaned {
+		ids[i] = strconv.Itoa(account.ID)
+	}
+	if err := h.stateService.WithPayload(c.Sender().ID, strings.Join(ids, ",")); err != nil {
+		h.logger.Errorf("Failed to set payload: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitConfirmPruneAccounts); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+	if err := h.stateService.WithConfirmationRequested(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to set confirmation time: %v", err)
+		return err
+	}
+
+	sb.WriteString(h.t(i18n.ReconcilePruneQuestion, len(drift.Orphaned)))
+	return h.sendLongMessage(c, sb.String(), h.createInlineConfirmKeyboard(confirmPruneAccounts))
+}
+
+// processConfirmPruneAccounts processes a typed confirmation for pruning orphaned accounts
+func (h *AdminHandler) processConfirmPruneAccounts(c telebot.Context) error {
+	confirmation := c.Text()
+
+	// Check for return to main menu
+	if h.getButtonCommand(confirmation) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	if h.getButtonCommand(confirmation) != commands.Confirm {
+		return h.sendTextMessage(c, h.t(i18n.ReconcileInvalidSelection), h.createReturnKeyboard())
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if userState.Payload == nil || h.isConfirmationExpired(userState) {
+		return h.handleExpiredConfirmation(c)
+	}
+
+	return h.executePruneAccounts(c, *userState.Payload)
+}
+
+// executePruneAccounts removes the confirmed orphaned VPN account records from storage
+func (h *AdminHandler) executePruneAccounts(c telebot.Context, payload string) error {
+	if clearErr := h.stateService.ClearState(c.Sender().ID); clearErr != nil {
+		h.logger.Errorf("Failed to clear user state: %v", clearErr)
+	}
+
+	var ids []int
+	for _, field := range strings.Split(payload, ",") {
+		id, err := strconv.Atoi(field)
+		if err != nil {
+			return h.handleExpiredConfirmation(c)
+		}
+		ids = append(ids, id)
+	}
+
+	log := h.logger.WithFields(logrus.Fields{
+		"operation": "prune_accounts",
+		"user_id":   c.Sender().ID,
+		"ids":       fmt.Sprint(ids),
+	})
+
+	removed, err := h.storageService.RemoveVpnAccountsByID(ids)
+	if err != nil {
+		log.WithError(err).Error("Failed to prune orphaned VPN accounts")
+		return h.sendTextMessage(c, h.t(i18n.ReconcilePruneFailed, err), h.createMainKeyboard(permissions.Admin))
+	}
+
+	log.WithField("removed", removed).Info("Pruned orphaned VPN accounts")
+	return h.sendTextMessage(c, h.t(i18n.ReconcilePruneDone, removed), h.createMainKeyboard(permissions.Admin))
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"xui-tg-admin/internal/models"
+)
+
+func TestReconcileSplitsLongReport(t *testing.T) {
+	const count = 200
+	padding := strings.Repeat("x", 20)
+
+	t.Run("orphaned", func(t *testing.T) {
+		server := newInboundsPanel(t, nil)
+		h := newTestAdminHandler(t, server.URL)
+		if err := h.storageService.AddTrusted(100, "reseller"); err != nil {
+			t.Fatalf("failed to add trusted user: %v", err)
+		}
+		for i := 1; i <= count; i++ {
+			if err := h.storageService.AddVpnAccount(fmt.Sprintf("orphan_%s_%d", padding, i), "secret", 100); err != nil {
+				t.Fatalf("failed to add account: %v", err)
+			}
+		}
+		bot, api := newTestBot(t)
+
+		if err := h.handleReconcile(textUpdate(bot, "")); err != nil {
+			t.Fatalf("handleReconcile failed: %v", err)
+		}
+		if n := api.calls("sendMessage"); n < 2 {
+			t.Errorf("sent %d messages, want the report of %d accounts split across several", n, count)
+		}
+	})
+
+	t.Run("untracked", func(t *testing.T) {
+		inbound := models.Inbound{ID: 1, Protocol: "vless", Settings: `{"clients":[]}`}
+		for i := 1; i <= count; i++ {
+			inbound.ClientStats = append(inbound.ClientStats, models.ClientStat{
+				ID:        i,
+				InboundID: 1,
+				Email:     fmt.Sprintf("reseller_%s-add%d-1", padding, i),
+			})
+		}
+		server := newInboundsPanel(t, []models.Inbound{inbound})
+		h := newTestAdminHandler(t, server.URL)
+		if err := h.storageService.AddTrusted(100, "reseller_"+padding); err != nil {
+			t.Fatalf("failed to add trusted user: %v", err)
+		}
+		bot, api := newTestBot(t)
+
+		if err := h.handleReconcile(textUpdate(bot, "")); err != nil {
+			t.Fatalf("handleReconcile failed: %v", err)
+		}
+		if n := api.calls("sendMessage"); n < 2 {
+			t.Errorf("sent %d messages, want the report of %d accounts split across several", n, count)
+		}
+	})
+}
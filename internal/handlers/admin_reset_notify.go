@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"html"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+)
+
+// resetNotifyData is the callback data of the button that tells a member their traffic was reset
+const resetNotifyData = "reset_notify"
+
+// resetNotifyMarkup offers to tell the member about the reset when the clients carry a
+// Telegram ID to message, or returns nil
+func (h *AdminHandler) resetNotifyMarkup(inbounds []models.Inbound, username string) *telebot.ReplyMarkup {
+	recipients := h.resetNotifyRecipients(inbounds, username)
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{{Text: h.t(i18n.ResetNotifyButton, len(recipients)), Data: resetNotifyData}},
+		},
+	}
+}
+
+// handleResetNotifyCallback messages the member being managed that their traffic was reset
+func (h *AdminHandler) handleResetNotifyCallback(c telebot.Context) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	// Drop the button so the member isn't told twice
+	if c.Message() != nil {
+		if _, err := c.Bot().EditReplyMarkup(c.Message(), nil); err != nil {
+			h.logger.Errorf("Failed to remove notify keyboard: %v", err)
+		}
+	}
+
+	// The member must still be the one being managed
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if userState.State != models.AwaitMemberAction || userState.Payload == nil {
+		return h.handleExpiredConfirmation(c)
+	}
+	username := *userState.Payload
+
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.panelErrorMessage(err), h.createUserActionKeyboard())
+	}
+
+	recipients := h.resetNotifyRecipients(inbounds, username)
+	if len(recipients) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.ResetNotifyNoRecipients, html.EscapeString(username)), h.createUserActionKeyboard())
+	}
+
+	log := h.logger.WithFields(logrus.Fields{
+		"operation": "reset_notify",
+		"user_id":   c.Sender().ID,
+		"username":  username,
+	})
+
+	notice := h.t(i18n.ResetNotifyMessage, html.EscapeString(username))
+	sent := 0
+	for _, recipient := range recipients {
+		_, err := h.sendWithFloodRetry(func() (*telebot.Message, error) {
+			return c.Bot().Send(&telebot.User{ID: recipient}, notice, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+		})
+		if err != nil {
+			log.WithError(err).WithField("recipient", recipient).Warn("Failed to send reset notice")
+			continue
+		}
+		sent++
+	}
+
+	log.WithField("sent", sent).Info("Sent reset notice")
+	if sent == 0 {
+		return h.sendTextMessage(c, h.t(i18n.ResetNotifyFailed, html.EscapeString(username)), h.createUserActionKeyboard())
+	}
+	return h.sendTextMessage(c, h.t(i18n.ResetNotifyDone, html.EscapeString(username), sent, len(recipients)), h.createUserActionKeyboard())
+}
+
+// resetNotifyRecipients returns the Telegram IDs stored on the member's clients. Clients
+// created by an admin carry that admin's ID rather than the member's, so admins are left out.
+func (h *AdminHandler) resetNotifyRecipients(inbounds []models.Inbound, username string) []int64 {
+	admins := make(map[int64]bool)
+	for _, id := range h.config.Telegram.AdminIDs {
+		admins[id] = true
+	}
+
+	seen := make(map[int64]bool)
+	var recipients []int64
+	for _, inbound := range inbounds {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			h.logger.Warnf("Failed to parse inbound settings: %v", err)
+			continue
+		}
+
+		for _, client := range clients {
+			if !helpers.IsEmailMatchingBaseUsername(client.Email, username) {
+				continue
+			}
+			id, err := strconv.ParseInt(client.TgID, 10, 64)
+			if err != nil || id <= 0 || seen[id] || admins[id] || h.storageService.IsAdmin(id) {
+				continue
+			}
+			seen[id] = true
+			recipients = append(recipients, id)
+		}
+	}
+	return recipients
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// retryCreationData is the callback data of the button retrying a failed member creation
+const retryCreationData = "retry_create"
+
+// offerCreationRetry reports a member creation that failed on every inbound and keeps its
+// params in the user state so the Retry button can repeat it without prompting again
+func (h *AdminHandler) offerCreationRetry(c telebot.Context, params ClientCreationParams, addErrors []string) error {
+	message := h.t(i18n.AddMemberFailed, params.BaseUsername, strings.Join(addErrors, "\n"))
+
+	failed := models.FailedCreation{
+		BaseUsername: params.BaseUsername,
+		DurationStr:  params.DurationStr,
+		ExpiryTime:   params.ExpiryTime,
+		SubID:        params.CommonSubId,
+		Password:     params.Password,
+		FailedAt:     time.Now(),
+	}
+	if err := h.stateService.WithFailedCreation(c.Sender().ID, failed); err != nil {
+		h.logger.Errorf("Failed to keep failed creation for retry: %v", err)
+		return h.sendTextMessage(c, message, h.createReturnKeyboard())
+	}
+
+	markup := &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{
+				{Text: h.t(i18n.AddMemberRetryButton), Data: retryCreationData},
+			},
+		},
+	}
+	return h.sendTextMessage(c, message, markup)
+}
+
+// handleRetryCreationCallback repeats the last failed member creation if it hasn't expired
+func (h *AdminHandler) handleRetryCreationCallback(c telebot.Context) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	// Drop the button so the creation can't be retried twice at once
+	if c.Message() != nil {
+		if _, err := c.Bot().EditReplyMarkup(c.Message(), nil); err != nil {
+			h.logger.Errorf("Failed to remove retry keyboard: %v", err)
+		}
+	}
+
+	// Taking the retry forgets it right away; a new failure stores a fresh one
+	failed, err := h.stateService.TakeFailedCreation(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if failed == nil || time.Since(failed.FailedAt) > constants.CreationRetryTimeout*time.Second {
+		return h.sendTextMessage(c, h.t(i18n.AddMemberRetryExpired), h.createReturnKeyboard())
+	}
+
+	// Someone may have created the member in the meantime
+	existing, err := h.findExistingMember(context.Background(), failed.BaseUsername)
+	if err != nil {
+		h.logger.Errorf("Failed to check existing members: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createReturnKeyboard())
+	}
+	if existing != "" {
+		return h.offerExistingMember(c, existing)
+	}
+
+	enabledInbounds, err := h.getEnabledInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get enabled inbounds: %v", err)
+		return h.sendTextMessage(c, h.enabledInboundsErrorMessage(err, permissions.Admin), h.createReturnKeyboard())
+	}
+
+	password := failed.Password
+	if password == "" {
+		if password, err = models.NewMemberPassword(enabledInbounds); err != nil {
+			h.logger.Errorf("Failed to generate password: %v", err)
+			return h.sendTextMessage(c, h.t(i18n.AddMemberFailed, failed.BaseUsername, html.EscapeString(err.Error())), h.createReturnKeyboard())
+		}
+	}
+
+	params := ClientCreationParams{
+		BaseUsername:    failed.BaseUsername,
+		DurationStr:     failed.DurationStr,
+		ExpiryTime:      failed.ExpiryTime,
+		CommonSubId:     failed.SubID,
+		BaseFingerprint: fmt.Sprintf("%x", time.Now().UnixNano()),
+		SenderID:        c.Sender().ID,
+		Password:        password,
+	}
+
+	h.logger.WithField("user_id", c.Sender().ID).Infof("Retrying creation of member %s", failed.BaseUsername)
+	return h.createMember(c, params, enabledInbounds)
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+)
+
+// unifySubIDPrefix is the callback data prefix for unifying a member's subscription IDs
+const unifySubIDPrefix = "unify_subid_"
+
+// subIDUsage counts how many of a member's clients use a subscription ID
+type subIDUsage struct {
+	SubID string
+	Count int
+}
+
+// collectSubIDs returns the subscription IDs used by the member's clients, most common first
+func (h *BaseHandler) collectSubIDs(inbounds []models.Inbound, username string) []subIDUsage {
+	var usages []subIDUsage
+	index := make(map[string]int)
+
+	for _, inbound := range inbounds {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			h.logger.Warnf("Failed to parse inbound settings: %v", err)
+			continue
+		}
+
+		for _, client := range clients {
+			if !helpers.IsEmailMatchingBaseUsername(client.Email, username) {
+				continue
+			}
+
+			if i, ok := index[client.SubID]; ok {
+				usages[i].Count++
+				continue
+			}
+			index[client.SubID] = len(usages)
+			usages = append(usages, subIDUsage{SubID: client.SubID, Count: 1})
+		}
+	}
+
+	// Keep first-seen order among equally used SubIDs
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].Count > usages[j].Count
+	})
+
+	return usages
+}
+
+// primarySubID returns the most common non-empty subscription ID
+func primarySubID(usages []subIDUsage) string {
+	for _, usage := range usages {
+		if usage.SubID != "" {
+			return usage.SubID
+		}
+	}
+	return ""
+}
+
+// sendSubIDMismatch reports a member's mismatched subscription IDs and offers to unify them
+func (h *AdminHandler) sendSubIDMismatch(c telebot.Context, username string, usages []subIDUsage, target string) error {
+	var sb strings.Builder
+	for _, usage := range usages {
+		subID := usage.SubID
+		if subID == "" {
+			subID = "—"
+		}
+		sb.WriteString(h.t(i18n.SubIDMismatchLine, subID, usage.Count))
+	}
+
+	markup := &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{
+				{Text: h.t(i18n.SubIDUnifyButton), Data: unifySubIDPrefix + username},
+			},
+		},
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.SubIDMismatch, username, len(usages), sb.String(), target), markup)
+}
+
+// handleUnifySubIDCallback moves all of a member's clients to the primary subscription ID
+func (h *AdminHandler) handleUnifySubIDCallback(c telebot.Context, data string) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	// Drop the inline keyboard so the unification can't be triggered twice
+	if c.Message() != nil {
+		if _, err := c.Bot().EditReplyMarkup(c.Message(), nil); err != nil {
+			h.logger.Errorf("Failed to remove unify keyboard: %v", err)
+		}
+	}
+
+	username := strings.TrimPrefix(data, unifySubIDPrefix)
+
+	// The member must still be the one being managed
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if userState.State != models.AwaitMemberAction || userState.Payload == nil || *userState.Payload != username {
+		return h.handleExpiredConfirmation(c)
+	}
+
+	updated, target, unifyErrors := h.unifySubIDs(context.Background(), username)
+	if target == "" {
+		return h.sendTextMessage(c, h.t(i18n.MemberNotFound, username), h.createUserActionKeyboard())
+	}
+	if updated == 0 && len(unifyErrors) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.SubIDAlreadyUnified, username), h.createUserActionKeyboard())
+	}
+	if updated == 0 {
+		message := h.t(i18n.SubIDUnifyFailed, username) + h.t(i18n.ErrorsList, strings.Join(unifyErrors, "\n"))
+		return h.sendTextMessage(c, message, h.createUserActionKeyboard())
+	}
+
+	message := h.t(i18n.SubIDUnified, updated, username, target)
+	if len(unifyErrors) > 0 {
+		message += h.t(i18n.SomeErrorsOccurred, strings.Join(unifyErrors, "\n"))
+	}
+	if err := h.sendTextMessage(c, message, h.createUserActionKeyboard()); err != nil {
+		return err
+	}
+
+	// Show the link and QR that now cover every inbound
+	return h.handleViewConfig(c, username)
+}
+
+// memberConnection is the import URI of one of a member's clients, or why it couldn't be built
+type memberConnection struct {
+	Inbound models.Inbound
+	URI     string
+	Err     error
+}
+
+// collectConnections builds the import URIs of the member's clients in every inbound
+func (h *AdminHandler) collectConnections(inbounds []models.Inbound, username string) []memberConnection {
+	host := ""
+	if parsed, err := url.Parse(h.config.Server.APIURL); err == nil {
+		host = parsed.Hostname()
+	}
+
+	var connections []memberConnection
+	for _, inbound := range inbounds {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			h.logger.Warnf("Failed to parse inbound settings: %v", err)
+			continue
+		}
+
+		for _, client := range clients {
+			if !helpers.IsEmailMatchingBaseUsername(client.Email, username) {
+				continue
+			}
+
+			uri, err := helpers.BuildConnectionURI(inbound, client, host)
+			if err != nil && !errors.Is(err, helpers.ErrUnsupportedProtocol) {
+				h.logger.Errorf("Failed to build connection URI for inbound %d: %v", inbound.ID, err)
+			}
+			connections = append(connections, memberConnection{Inbound: inbound, URI: uri, Err: err})
+		}
+	}
+
+	return connections
+}
+
+// handleConnectionLinks sends per-inbound import URIs for clients that can't use a subscription link
+func (h *AdminHandler) handleConnectionLinks(c telebot.Context, username string) error {
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.ViewConfigInboundsFailed, h.panelErrorText(err)), h.createUserActionKeyboard())
+	}
+
+	connections := h.collectConnections(inbounds, username)
+	if len(connections) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.MemberNotFound, username), h.createUserActionKeyboard())
+	}
+
+	var sb strings.Builder
+	for _, conn := range connections {
+		inbound := conn.Inbound
+		remark := html.EscapeString(inbound.Remark)
+		switch {
+		case errors.Is(conn.Err, helpers.ErrUnsupportedProtocol):
+			sb.WriteString(h.t(i18n.ConnectionUnsupported, remark, inbound.Protocol))
+		case conn.Err != nil:
+			sb.WriteString(h.t(i18n.ConnectionFailed, remark, inbound.Protocol))
+		default:
+			sb.WriteString(h.t(i18n.ConnectionLine, remark, inbound.Protocol, inbound.Port, html.EscapeString(conn.URI)))
+		}
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.ConnectionHeader, username)+sb.String(), h.createUserActionKeyboard())
+}
+
+// unifySubIDs updates the member's clients to share the primary subscription ID
+func (h *AdminHandler) unifySubIDs(ctx context.Context, username string) (int, string, []string) {
+	inbounds, err := h.xrayService.GetInbounds(ctx)
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return 0, "", []string{"Failed to get server configuration"}
+	}
+
+	target := primarySubID(h.collectSubIDs(inbounds, username))
+	if target == "" {
+		return 0, "", nil
+	}
+
+	var unifyErrors []string
+	updated := 0
+
+	for _, inbound := range inbounds {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			h.logger.Warnf("Failed to parse inbound settings: %v", err)
+			continue
+		}
+
+		for _, inboundClient := range clients {
+			if !helpers.IsEmailMatchingBaseUsername(inboundClient.Email, username) || inboundClient.SubID == target {
+				continue
+			}
+
+			client := inboundClient.ToClient()
+			client.SubID = target
+
+			log := h.logger.WithFields(logrus.Fields{
+				"operation":  "unify_subid",
+				"inbound_id": inbound.ID,
+				"email":      inboundClient.Email,
+				"sub_id":     target,
+			})
+
+			if err := h.xrayService.UpdateClient(ctx, inbound.ID, inbound.ClientKey(inboundClient), client); err != nil {
+				log.WithError(err).Error("Failed to update client subscription ID")
+				unifyErrors = append(unifyErrors, h.t(i18n.PanelErrInbound, inbound.ID, h.panelErrorText(err)))
+				continue
+			}
+
+			log.Info("Updated client subscription ID")
+			updated++
+		}
+	}
+
+	return updated, target, unifyErrors
+}
+
+// unreadableInboundsOf lists the inbounds with unparseable settings whose traffic stats
+// still show a client of the member
+func unreadableInboundsOf(inbounds []models.Inbound, username string) []string {
+	var labels []string
+	for _, inbound := range helpers.MalformedInbounds(inbounds) {
+		for _, stat := range inbound.ClientStats {
+			if helpers.IsEmailMatchingBaseUsername(stat.Email, username) {
+				labels = append(labels, fmt.Sprintf("#%d %s", inbound.ID, html.EscapeString(inbound.Remark)))
+				break
+			}
+		}
+	}
+	return labels
+}
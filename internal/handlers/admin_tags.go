@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"html"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/validation"
+)
+
+// tagFilterButtonPrefix marks buttons that filter member lists and reports by tag
+const tagFilterButtonPrefix = "🏷 "
+
+// handleTagsRequest shows the member's tags and asks which to add or remove
+func (h *AdminHandler) handleTagsRequest(c telebot.Context, username string) error {
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitingUserTags); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	current := h.t(i18n.TagsNone)
+	if tags := h.storageService.GetTags(username); len(tags) > 0 {
+		current = formatTags(tags)
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.TagsPrompt, username, current), h.createReturnKeyboard())
+}
+
+// processUserTags applies "+tag" and "-tag" changes typed for the member
+func (h *AdminHandler) processUserTags(c telebot.Context) error {
+	text := c.Text()
+
+	// Check for return to main menu
+	if h.getButtonCommand(text) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if userState.Payload == nil {
+		return h.sendTextMessage(c, h.t(i18n.SessionUserLost), h.createReturnKeyboard())
+	}
+	username := *userState.Payload
+
+	// Validate every change before applying any of them
+	var add, remove []string
+	for _, field := range strings.Fields(text) {
+		op, tag := field[:1], validation.NormalizeTag(field[1:])
+		if op != "+" && op != "-" {
+			return h.sendTextMessage(c, h.t(i18n.TagsInvalidInput, html.EscapeString(field)), h.createReturnKeyboard())
+		}
+		if err := validation.ValidateTag(tag); err != nil {
+			return h.sendTextMessage(c, h.t(i18n.TagsInvalidTag, html.EscapeString(field), err.Error()), h.createReturnKeyboard())
+		}
+		if op == "+" {
+			add = append(add, tag)
+		} else {
+			remove = append(remove, tag)
+		}
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.TagsInvalidInput, ""), h.createReturnKeyboard())
+	}
+
+	for _, tag := range add {
+		if err := h.storageService.AddTag(username, tag); err != nil {
+			h.logger.Errorf("Failed to add tag %s to %s: %v", tag, username, err)
+			return h.sendTextMessage(c, h.t(i18n.TagsSaveFailed), h.createUserActionKeyboard())
+		}
+	}
+	for _, tag := range remove {
+		if err := h.storageService.RemoveTag(username, tag); err != nil {
+			h.logger.Errorf("Failed to remove tag %s from %s: %v", tag, username, err)
+			return h.sendTextMessage(c, h.t(i18n.TagsSaveFailed), h.createUserActionKeyboard())
+		}
+	}
+
+	// Continue managing the member
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitMemberAction); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.TagsSaved, username)+"\n\n"+h.manageMemberMessage(username), h.createUserActionKeyboard())
+}
+
+// createTagFilterRows creates reply keyboard rows with a filter button per tag in use,
+// plus one to clear the current filter
+func (h *AdminHandler) createTagFilterRows(currentTag string) []telebot.Row {
+	var rows []telebot.Row
+	var row telebot.Row
+
+	for _, tag := range h.storageService.GetAllTags() {
+		if tag == currentTag {
+			continue
+		}
+		row = append(row, telebot.Btn{Text: tagFilterButtonPrefix + tag})
+		if len(row) == 3 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if currentTag != "" {
+		row = append(row, telebot.Btn{Text: tagFilterButtonPrefix + commands.Label(commands.AllTags)})
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// parseTagFilterButton returns the tag of a tag filter button, or an empty tag for "All"
+func parseTagFilterButton(text string) (string, bool) {
+	if !strings.HasPrefix(text, tagFilterButtonPrefix) {
+		return "", false
+	}
+
+	tag := strings.TrimPrefix(text, tagFilterButtonPrefix)
+	if tag == commands.Label(commands.AllTags) {
+		return "", true
+	}
+	return tag, true
+}
+
+// formatTags formats tags for HTML messages
+func formatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "#" + html.EscapeString(tag)
+	}
+	return strings.Join(formatted, " ")
+}
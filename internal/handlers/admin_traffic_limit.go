@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/validation"
+)
+
+// handleTrafficLimitRequest asks for the member's new traffic limit, showing the current one
+func (h *AdminHandler) handleTrafficLimitRequest(c telebot.Context, username string) error {
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.panelErrorMessage(err), h.createUserActionKeyboard())
+	}
+
+	limit, found := memberTrafficLimit(inbounds, username)
+	if !found {
+		return h.sendTextMessage(c, h.t(i18n.MemberNotFound, username), h.createUserActionKeyboard())
+	}
+
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitingTrafficLimit); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.TrafficLimitPrompt, username, h.formatTrafficLimit(limit)), h.createReturnKeyboard())
+}
+
+// processTrafficLimit validates the typed limit, applies it to every client of the member
+// and returns to the member actions
+func (h *AdminHandler) processTrafficLimit(c telebot.Context) error {
+	text := strings.TrimSpace(c.Text())
+
+	// Check for return to main menu
+	if h.getButtonCommand(text) == commands.ReturnToMainMenu {
+		return h.handleStart(c)
+	}
+
+	userState, err := h.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		h.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+	if userState.Payload == nil {
+		return h.sendTextMessage(c, h.t(i18n.SessionUserLost), h.createReturnKeyboard())
+	}
+	username := *userState.Payload
+
+	gb, err := validation.ValidateTrafficLimit(text)
+	if err != nil {
+		return h.sendTextMessage(c, h.t(i18n.TrafficLimitInvalid, err.Error()), h.createReturnKeyboard())
+	}
+	limit := int64(gb * constants.BytesInGB)
+
+	updated, limitErrors := h.setTrafficLimit(context.Background(), c.Sender().ID, username, limit)
+
+	// Continue managing the member
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.AwaitMemberAction); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	if updated == 0 {
+		message := h.t(i18n.TrafficLimitFailed, username)
+		if len(limitErrors) > 0 {
+			message += h.t(i18n.ErrorsList, strings.Join(limitErrors, "\n"))
+		}
+		return h.sendTextMessage(c, message, h.createUserActionKeyboard())
+	}
+
+	message := h.t(i18n.TrafficLimitSet, username, h.formatTrafficLimit(limit), updated)
+	if len(limitErrors) > 0 {
+		message += h.t(i18n.SomeErrorsOccurred, strings.Join(limitErrors, "\n"))
+	}
+	return h.sendTextMessage(c, message+"\n\n"+h.manageMemberMessage(username), h.createUserActionKeyboard())
+}
+
+// setTrafficLimit sets the traffic cap, in bytes, on every client of the member. Usage so far
+// is kept, so the member can use the rest of the new limit. It returns how many clients were
+// updated and the errors for the others.
+func (h *AdminHandler) setTrafficLimit(ctx context.Context, senderID int64, username string, limit int64) (int, []string) {
+	inbounds, err := h.xrayService.GetInbounds(ctx)
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return 0, []string{h.panelErrorText(err)}
+	}
+
+	updated := 0
+	var limitErrors []string
+	for _, inbound := range inbounds {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			h.logger.Warnf("Failed to parse inbound settings: %v", err)
+			continue
+		}
+
+		for _, inboundClient := range clients {
+			if !helpers.IsEmailMatchingBaseUsername(inboundClient.Email, username) {
+				continue
+			}
+
+			client := inboundClient.ToClient()
+			client.TotalGB = int(limit)
+
+			log := h.logger.WithFields(logrus.Fields{
+				"operation":  "set_traffic_limit",
+				"user_id":    senderID,
+				"inbound_id": inbound.ID,
+				"email":      inboundClient.Email,
+				"limit":      limit,
+			})
+
+			if err := h.xrayService.UpdateClient(ctx, inbound.ID, inbound.ClientKey(inboundClient), client); err != nil {
+				log.WithError(err).Error("Failed to update client")
+				limitErrors = append(limitErrors, h.t(i18n.PanelErrInbound, inbound.ID, h.panelErrorText(err)))
+				continue
+			}
+
+			log.Info("Set traffic limit")
+			updated++
+		}
+	}
+
+	return updated, limitErrors
+}
+
+// memberTrafficLimit returns the traffic cap, in bytes, of the member's first client, and
+// whether the member has any client at all
+func memberTrafficLimit(inbounds []models.Inbound, username string) (int64, bool) {
+	for _, inbound := range inbounds {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			continue
+		}
+		for _, client := range clients {
+			if helpers.IsEmailMatchingBaseUsername(client.Email, username) {
+				return client.TotalGB, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// formatTrafficLimit shows a traffic cap in GB, or as unlimited when it is 0
+func (h *BaseHandler) formatTrafficLimit(limit int64) string {
+	if limit == 0 {
+		return h.t(i18n.TrafficLimitUnlimited)
+	}
+	return fmt.Sprintf("%.2f GB", float64(limit)/constants.BytesInGB)
+}
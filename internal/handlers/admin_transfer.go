@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+	"xui-tg-admin/internal/services"
+)
+
+// handleTransferAccount hands a trusted user's VPN account to another trusted user, e.g.
+// "/transfer 12 @bob". The account counts towards the new owner's limit from then on.
+func (h *AdminHandler) handleTransferAccount(c telebot.Context) error {
+	fields := strings.Fields(c.Text())
+	if len(fields) < 3 {
+		return h.sendTextMessage(c, h.t(i18n.TransferUsage), h.createMainKeyboard(permissions.Admin))
+	}
+	accountArg, ownerArg := fields[1], fields[2]
+
+	account, message := h.findTransferAccount(accountArg)
+	if message != "" {
+		return h.sendTextMessage(c, message, h.createMainKeyboard(permissions.Admin))
+	}
+
+	target, ok := h.findTrustedUser(ownerArg)
+	if !ok {
+		return h.sendTextMessage(c, h.t(i18n.TransferTargetNotTrusted, html.EscapeString(ownerArg)), h.createMainKeyboard(permissions.Admin))
+	}
+
+	log := h.logger.WithFields(logrus.Fields{
+		"operation":  "transfer_account",
+		"user_id":    c.Sender().ID,
+		"account_id": account.ID,
+		"username":   account.Username,
+		"from":       account.AddedBy,
+		"to":         target,
+	})
+
+	from := h.trustedUserLabel(account.AddedBy)
+	to := h.trustedUserLabel(target)
+	username := html.EscapeString(account.Username)
+
+	err := h.storageService.TransferVpnAccount(account.ID, target, constants.MaxTrustedAccounts)
+	switch {
+	case errors.Is(err, services.ErrTransferSameOwner):
+		return h.sendTextMessage(c, h.t(i18n.TransferSameOwner, username, to), h.createMainKeyboard(permissions.Admin))
+	case errors.Is(err, services.ErrAccountLimitReached):
+		return h.sendTextMessage(c, h.t(i18n.TransferLimitReached, to, constants.MaxTrustedAccounts), h.createMainKeyboard(permissions.Admin))
+	case errors.Is(err, services.ErrTransferTarget):
+		return h.sendTextMessage(c, h.t(i18n.TransferTargetNotTrusted, to), h.createMainKeyboard(permissions.Admin))
+	case errors.Is(err, services.ErrAccountNotFound):
+		return h.sendTextMessage(c, h.t(i18n.TransferAccountNotFound, html.EscapeString(accountArg)), h.createMainKeyboard(permissions.Admin))
+	case err != nil:
+		log.WithError(err).Error("Failed to transfer account")
+		return h.sendTextMessage(c, h.t(i18n.TransferFailed, err), h.createMainKeyboard(permissions.Admin))
+	}
+	log.Info("Transferred account")
+
+	// Reminders and per-creator lookups read the owner from the clients' tgId, so the panel
+	// has to follow the storage
+	text := h.t(i18n.TransferDone, account.ID, username, from, to)
+	if ownerErrors := h.setClientsOwner(context.Background(), c.Sender().ID, account.Username, target); len(ownerErrors) > 0 {
+		text += h.t(i18n.TransferPanelFailed, strings.Join(ownerErrors, "\n"))
+	}
+	return h.sendTextMessage(c, text, h.createMainKeyboard(permissions.Admin))
+}
+
+// setClientsOwner points the tgId of every client of the member at the new owner and
+// returns the errors for the clients it couldn't update
+func (h *AdminHandler) setClientsOwner(ctx context.Context, senderID int64, username string, owner int64) []string {
+	inbounds, err := h.xrayService.GetInbounds(ctx)
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return []string{h.panelErrorText(err)}
+	}
+
+	tgID := strconv.FormatInt(owner, 10)
+	var ownerErrors []string
+	for _, inbound := range inbounds {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			h.logger.Warnf("Failed to parse inbound settings: %v", err)
+			continue
+		}
+
+		for _, inboundClient := range clients {
+			if inboundClient.TgID == tgID || !helpers.IsEmailMatchingBaseUsername(inboundClient.Email, username) {
+				continue
+			}
+
+			client := inboundClient.ToClient()
+			client.TgID = tgID
+
+			log := h.logger.WithFields(logrus.Fields{
+				"operation":  "transfer_account",
+				"user_id":    senderID,
+				"inbound_id": inbound.ID,
+				"email":      inboundClient.Email,
+				"to":         owner,
+			})
+
+			if err := h.xrayService.UpdateClient(ctx, inbound.ID, inbound.ClientKey(inboundClient), client); err != nil {
+				log.WithError(err).Error("Failed to update client owner")
+				ownerErrors = append(ownerErrors, h.t(i18n.PanelErrInbound, inbound.ID, h.panelErrorText(err)))
+				continue
+			}
+			log.Info("Updated client owner")
+		}
+	}
+
+	return ownerErrors
+}
+
+// findTransferAccount looks a stored account up by ID or username. A username shared by
+// accounts of different owners is ambiguous, so the message then lists their IDs instead.
+func (h *AdminHandler) findTransferAccount(arg string) (models.VpnAccount, string) {
+	id, err := strconv.Atoi(arg)
+	var matches []models.VpnAccount
+	for _, account := range h.storageService.GetAllVpnAccounts() {
+		if (err == nil && account.ID == id) || account.Username == arg {
+			matches = append(matches, account)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return models.VpnAccount{}, h.t(i18n.TransferAccountNotFound, html.EscapeString(arg))
+	case 1:
+		return matches[0], ""
+	}
+
+	ids := make([]string, 0, len(matches))
+	for _, account := range matches {
+		ids = append(ids, "<code>"+strconv.Itoa(account.ID)+"</code>")
+	}
+	return models.VpnAccount{}, h.t(i18n.TransferAccountAmbiguous, html.EscapeString(arg), strings.Join(ids, ", "))
+}
+
+// findTrustedUser resolves a @username or Telegram ID to a trusted user's stored ID
+func (h *AdminHandler) findTrustedUser(arg string) (int64, bool) {
+	if username, ok := strings.CutPrefix(arg, "@"); ok {
+		trusted, telegramID := h.storageService.IsTrustedByUsername(username)
+		return telegramID, trusted
+	}
+
+	telegramID, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return telegramID, h.storageService.IsTrusted(telegramID)
+}
+
+// trustedUserLabel shows a trusted user as @username, or by ID once they have been revoked
+func (h *AdminHandler) trustedUserLabel(telegramID int64) string {
+	for _, user := range h.storageService.GetTrustedUsers() {
+		if user.TelegramID == telegramID && user.Username != "" {
+			return "@" + html.EscapeString(user.Username)
+		}
+	}
+	return "<code>" + strconv.FormatInt(telegramID, 10) + "</code>"
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"xui-tg-admin/internal/models"
+)
+
+func TestTransferAccountUpdatesPanelOwner(t *testing.T) {
+	panel := &updateClientPanel{inbounds: []models.Inbound{
+		{ID: 1, Protocol: "vless", Settings: `{"clients":[{"id":"a","email":"bob-add1-1","tgId":"100"},{"id":"b","email":"carol-add1-1","tgId":"100"}]}`},
+		{ID: 2, Protocol: "vless", Settings: `{"clients":[{"id":"c","email":"bob-add1-2","tgId":"100"}]}`},
+	}}
+	server := httptest.NewServer(panel)
+	t.Cleanup(server.Close)
+	h := newTestAdminHandler(t, server.URL)
+	storage := h.storageService
+
+	for _, err := range []error{
+		storage.AddTrusted(100, "alice"),
+		storage.AddTrusted(200, "dave"),
+		storage.AddVpnAccount("bob-add1", "secret", 100),
+	} {
+		if err != nil {
+			t.Fatalf("failed to fill storage: %v", err)
+		}
+	}
+	bot, _ := newTestBot(t)
+
+	if err := h.handleTransferAccount(textUpdate(bot, "/transfer bob-add1 @dave")); err != nil {
+		t.Fatalf("handleTransferAccount failed: %v", err)
+	}
+
+	if accounts := storage.GetUserAccounts(200); len(accounts) != 1 {
+		t.Errorf("new owner has %d accounts, want 1", len(accounts))
+	}
+	if len(panel.updated) != 2 {
+		t.Fatalf("updated %d clients, want both clients of bob-add1", len(panel.updated))
+	}
+	for _, client := range panel.updated {
+		if client["tgId"] != "200" {
+			t.Errorf("client %v has tgId %v, want 200", client["email"], client["tgId"])
+		}
+	}
+}
@@ -3,12 +3,13 @@ package handlers
 import (
 	"context"
 	"fmt"
-	"hash/fnv"
 	"strconv"
 	"strings"
 
 	telebot "gopkg.in/telebot.v3"
 
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
 	"xui-tg-admin/internal/models"
 	"xui-tg-admin/internal/services"
 )
@@ -34,7 +35,7 @@ func (h *AdminTrustedHandler) HandleAddTrustedRequest(ctx context.Context, c tel
 	}
 	h.stateService.SetState(c.Sender().ID, state)
 
-	msg := "Send @username to add to trusted list:"
+	msg := h.t(i18n.TrustedAddPrompt)
 	return c.Send(msg)
 }
 
@@ -43,44 +44,44 @@ func (h *AdminTrustedHandler) HandleRevokeTrustedRequest(ctx context.Context, c
 	trustedUsers := h.storageService.GetTrustedUsers()
 
 	if len(trustedUsers) == 0 {
-		return c.Send("No trusted users found.")
+		return c.Send(h.t(i18n.TrustedNone))
 	}
 
 	keyboard := h.createRevokeTrustedKeyboard(trustedUsers)
-	return c.Send("Select user to revoke:", &telebot.ReplyMarkup{InlineKeyboard: keyboard})
+	return c.Send(h.t(i18n.TrustedSelectRevoke), &telebot.ReplyMarkup{InlineKeyboard: keyboard})
 }
 
 // HandleRevokeTrusted handles revoking a trusted user
 func (h *AdminTrustedHandler) HandleRevokeTrusted(ctx context.Context, c telebot.Context, telegramID int64) error {
 	if err := h.storageService.RemoveTrusted(telegramID); err != nil {
 		h.logger.Errorf("Failed to remove trusted user: %v", err)
-		return c.Send("Failed to revoke user.")
+		return c.Send(h.t(i18n.TrustedRevokeFailed))
 	}
 
-	return c.Send("User revoked from trusted list.")
+	return c.Send(h.t(i18n.TrustedRevoked))
 }
 
 // HandleTrustedUsernameInput handles username input for adding trusted user
 func (h *AdminTrustedHandler) HandleTrustedUsernameInput(ctx context.Context, c telebot.Context, text string) error {
 	if !strings.HasPrefix(text, "@") {
-		return c.Send("Please send a valid @username:")
+		return c.Send(h.t(i18n.TrustedInvalidUsername))
 	}
 
 	username := strings.TrimPrefix(text, "@")
 
 	// Generate pseudo telegram ID from username hash for consistency
-	telegramID := generatePseudoTelegramID(username)
+	telegramID := helpers.PseudoTelegramID(username)
 
 	if err := h.storageService.AddTrusted(telegramID, username); err != nil {
 		h.logger.Errorf("Failed to add trusted user: %v", err)
-		return c.Send("Failed to add user to trusted list.")
+		return c.Send(h.t(i18n.TrustedAddFailed))
 	}
 
 	state := models.UserState{
 		State: models.Default,
 	}
 	h.stateService.SetState(c.Sender().ID, state)
-	return c.Send(fmt.Sprintf("@%s added to trusted list.", username))
+	return c.Send(h.t(i18n.TrustedAdded, username))
 }
 
 // createRevokeTrustedKeyboard creates keyboard for revoking trusted users
@@ -109,17 +110,3 @@ func ParseRevokeTrustedCallback(data string) (int64, error) {
 	idStr := strings.TrimPrefix(data, "revoke_trusted_")
 	return strconv.ParseInt(idStr, 10, 64)
 }
-
-// generatePseudoTelegramID generates a consistent pseudo telegram ID from username
-func generatePseudoTelegramID(username string) int64 {
-	h := fnv.New64a()
-	h.Write([]byte(username))
-	hash := h.Sum64()
-	// Convert to int64 and ensure it's positive (Telegram IDs are positive)
-	id := int64(hash & 0x7FFFFFFFFFFFFFFF)
-	// Ensure it's not 0 (which we used as placeholder)
-	if id == 0 {
-		id = 1
-	}
-	return id
-}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// Callback data prefixes for the detailed usage report, followed by the inbound ID
+// (0 for all inbounds), the sort type and an optional tag
+const (
+	usageReportPrefix = "usage_"
+	// usageActivePrefix also toggles showing only active subscriptions
+	usageActivePrefix = "usage_active_"
+)
+
+// usageSortTypes lists the sort options offered under the detailed usage report
+var usageSortTypes = []models.SortType{
+	models.SortByTrafficTotal,
+	models.SortByName,
+	models.SortByExpiryDate,
+	models.SortByStatus,
+}
+
+// handleGetDetailedUsersInfo handles the Detailed Usage command
+func (h *AdminHandler) handleGetDetailedUsersInfo(c telebot.Context) error {
+	// Heaviest users first by default, across all inbounds
+	return h.showUsageReport(c, 0, models.SortByTrafficTotal, "")
+}
+
+// handleUsageReportCallback re-renders the detailed usage report with another inbound filter or sort order
+func (h *AdminHandler) handleUsageReportCallback(c telebot.Context, data string) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	// The active-only toggle is kept in the user's state like the member list sort
+	if strings.HasPrefix(data, usageActivePrefix) {
+		if _, err := h.stateService.ToggleActiveOnly(c.Sender().ID); err != nil {
+			h.logger.Errorf("Failed to toggle active-only filter: %v", err)
+		}
+		data = usageReportPrefix + strings.TrimPrefix(data, usageActivePrefix)
+	}
+
+	// Tags may contain underscores, so everything after the sort type is the tag
+	parts := strings.SplitN(strings.TrimPrefix(data, usageReportPrefix), "_", 3)
+	if len(parts) < 2 {
+		return c.Send(h.t(i18n.UnknownAction))
+	}
+	inboundID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return c.Send(h.t(i18n.UnknownAction))
+	}
+	sortType, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return c.Send(h.t(i18n.UnknownAction))
+	}
+	var tag string
+	if len(parts) == 3 {
+		tag = parts[2]
+	}
+
+	return h.showUsageReport(c, inboundID, models.SortType(sortType), tag)
+}
+
+// showUsageReport sends the detailed usage report for one inbound, or all of them when inboundID is 0,
+// limited to members with the tag when one is given
+func (h *AdminHandler) showUsageReport(c telebot.Context, inboundID int, sortType models.SortType, tag string) error {
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.DetailedUsageConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	markup := h.createUsageReportKeyboard(inbounds, inboundID, sortType, tag, h.stateService.GetActiveOnly(c.Sender().ID))
+
+	var header string
+	if h.stateService.GetActiveOnly(c.Sender().ID) {
+		inbounds = helpers.FilterActiveInbounds(inbounds)
+		header = h.t(i18n.UsageActiveOnlyHeader)
+	}
+	if tag != "" {
+		inbounds = helpers.FilterInboundsByUsers(inbounds, h.storageService.TaggedUsernames(tag))
+		header += h.t(i18n.UsageTagHeader, html.EscapeString(tag))
+	}
+
+	unit := helpers.ParseTrafficUnit(h.config.TrafficUnit)
+
+	// Get online users for status indication, continuing with none if this fails
+	onlineUsers, err := h.xrayService.GetOnlineUsers(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get online users: %v", err)
+		onlineUsers = []string{}
+	}
+
+	if inboundID == 0 {
+		message := header + helpers.FormatCompactTrafficReport(inbounds, onlineUsers, unit, sortType)
+		return h.sendLongMessage(c, message, markup)
+	}
+
+	for _, inbound := range inbounds {
+		if inbound.ID != inboundID {
+			continue
+		}
+
+		message := header + h.t(i18n.UsageInboundHeader, html.EscapeString(inbound.Remark), inbound.Protocol, inbound.Port) +
+			helpers.FormatCompactTrafficReport([]models.Inbound{inbound}, onlineUsers, unit, sortType)
+		return h.sendLongMessage(c, message, markup)
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.UsageInboundNotFound), h.createMainKeyboard(permissions.Admin))
+}
+
+// createUsageReportKeyboard creates inline buttons to filter the usage report by inbound, tag or
+// active subscriptions and change its sort order, leaving out the current choices
+func (h *AdminHandler) createUsageReportKeyboard(inbounds []models.Inbound, currentID int, currentSort models.SortType, currentTag string, activeOnly bool) *telebot.ReplyMarkup {
+	var keyboard [][]telebot.InlineButton
+	var row []telebot.InlineButton
+
+	activeButton := h.t(i18n.UsageActiveOnlyButton)
+	if activeOnly {
+		activeButton = h.t(i18n.UsageShowAllButton)
+	}
+	keyboard = append(keyboard, []telebot.InlineButton{
+		{Text: activeButton, Data: usageActivePrefix + strings.TrimPrefix(usageReportData(currentID, currentSort, currentTag), usageReportPrefix)},
+	})
+
+	for _, inbound := range inbounds {
+		if inbound.ID == currentID || len(inbound.ClientStats) == 0 {
+			continue
+		}
+
+		row = append(row, telebot.InlineButton{
+			Text: "📡 " + inbound.Remark,
+			Data: usageReportData(inbound.ID, currentSort, currentTag),
+		})
+		if len(row) == 2 {
+			keyboard = append(keyboard, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		keyboard = append(keyboard, row)
+	}
+
+	if currentID != 0 {
+		keyboard = append(keyboard, []telebot.InlineButton{
+			{Text: h.t(i18n.UsageAllInboundsButton), Data: usageReportData(0, currentSort, currentTag)},
+		})
+	}
+
+	row = nil
+	for _, sortType := range usageSortTypes {
+		if sortType == currentSort {
+			continue
+		}
+		row = append(row, telebot.InlineButton{
+			Text: sortType.GetSortName(h.localizer),
+			Data: usageReportData(currentID, sortType, currentTag),
+		})
+		if len(row) == 2 {
+			keyboard = append(keyboard, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		keyboard = append(keyboard, row)
+	}
+
+	row = nil
+	for _, tag := range h.storageService.GetAllTags() {
+		if tag == currentTag {
+			continue
+		}
+		row = append(row, telebot.InlineButton{
+			Text: tagFilterButtonPrefix + tag,
+			Data: usageReportData(currentID, currentSort, tag),
+		})
+		if len(row) == 3 {
+			keyboard = append(keyboard, row)
+			row = nil
+		}
+	}
+	if currentTag != "" {
+		row = append(row, telebot.InlineButton{
+			Text: tagFilterButtonPrefix + commands.Label(commands.AllTags),
+			Data: usageReportData(currentID, currentSort, ""),
+		})
+	}
+	if len(row) > 0 {
+		keyboard = append(keyboard, row)
+	}
+
+	return &telebot.ReplyMarkup{InlineKeyboard: keyboard}
+}
+
+// handleTopUsers shows the heaviest users by total traffic. The count comes from
+// the command argument ("/top 20") or the TOP_USERS setting.
+func (h *AdminHandler) handleTopUsers(c telebot.Context) error {
+	limit := h.config.TopUsers
+	if fields := strings.Fields(c.Text()); len(fields) > 1 && fields[0] == commands.Top {
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 1 {
+			return h.sendTextMessage(c, h.t(i18n.TopUsersInvalidCount), h.createMainKeyboard(permissions.Admin))
+		}
+		limit = n
+	}
+
+	members, err := h.xrayService.GetAllMembersWithInfo(context.Background(), models.SortByTrafficTotal)
+	if err != nil {
+		h.logger.Errorf("Failed to get members with info: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.UserListConnectionError), h.createMainKeyboard(permissions.Admin))
+	}
+
+	if len(members) == 0 {
+		return h.sendTextMessage(c, h.t(i18n.NoUsersYet), h.createMainKeyboard(permissions.Admin))
+	}
+
+	if len(members) > limit {
+		members = members[:limit]
+	}
+
+	// Members are sorted heaviest first, so the first one sets the unit
+	unit := helpers.ResolveTrafficUnit(helpers.ParseTrafficUnit(h.config.TrafficUnit), members[0].TotalTraffic)
+
+	var sb strings.Builder
+	sb.WriteString(h.t(i18n.TopUsersHeader, len(members)))
+	for i, member := range members {
+		sb.WriteString(h.t(i18n.TopUsersLine,
+			i+1,
+			html.EscapeString(member.BaseUsername),
+			unit.Value(member.TotalTraffic),
+			unit.Name,
+			member.GetExpiryStatus(h.localizer)))
+	}
+
+	return h.sendLongMessage(c, sb.String(), h.createMainKeyboard(permissions.Admin))
+}
+
+// usageReportData builds the callback data for a usage report view
+func usageReportData(inboundID int, sortType models.SortType, tag string) string {
+	if tag == "" {
+		return fmt.Sprintf("%s%d_%d", usageReportPrefix, inboundID, sortType)
+	}
+	return fmt.Sprintf("%s%d_%d_%s", usageReportPrefix, inboundID, sortType, tag)
+}
@@ -2,14 +2,33 @@ package handlers
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	telebot "gopkg.in/telebot.v3"
 
 	"xui-tg-admin/internal/commands"
 	"xui-tg-admin/internal/config"
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
 	"xui-tg-admin/internal/permissions"
 	"xui-tg-admin/internal/services"
+	"xui-tg-admin/pkg/xrayclient"
+)
+
+// Callback data prefixes shared by all handlers
+const (
+	// copyLinkPrefix resends a subscription link as text
+	copyLinkPrefix = "copy_link_"
+	// menuCallbackPrefix runs a main menu command from the inline menu
+	menuCallbackPrefix = "menu_"
 )
 
 // BaseHandler provides common functionality for all handlers
@@ -18,9 +37,13 @@ type BaseHandler struct {
 	stateService *services.UserStateService
 	qrService    *services.QRService
 	config       *config.Config
+	localizer    *i18n.Localizer
 	logger       *logrus.Logger
 }
 
+// ErrNoEnabledInbounds is returned when the panel has no enabled inbound to create clients on
+var ErrNoEnabledInbounds = errors.New("no enabled inbounds available")
+
 // NewBaseHandler creates a new base handler
 func NewBaseHandler(
 	xrayService *services.XrayService,
@@ -34,10 +57,37 @@ func NewBaseHandler(
 		stateService: stateService,
 		qrService:    qrService,
 		config:       config,
+		localizer:    i18n.NewLocalizer(config.Language),
 		logger:       logger,
 	}
 }
 
+// t returns the localized message for key, formatted with args
+func (h *BaseHandler) t(key i18n.Key, args ...interface{}) string {
+	return h.localizer.T(key, args...)
+}
+
+// welcomeMessage returns the /start message for the access type, preferring the configured one
+func (h *BaseHandler) welcomeMessage(accessType permissions.AccessType) string {
+	switch accessType {
+	case permissions.Admin:
+		if h.config.Welcome.Admin != "" {
+			return h.config.Welcome.Admin
+		}
+		return h.t(i18n.AdminWelcome)
+	case permissions.Trusted:
+		if h.config.Welcome.Trusted != "" {
+			return h.config.Welcome.Trusted
+		}
+		return h.t(i18n.TrustedWelcome)
+	default:
+		if h.config.Welcome.None != "" {
+			return h.config.Welcome.None
+		}
+		return h.t(i18n.NoPermission)
+	}
+}
+
 // CanHandle checks if the handler can handle the given access type
 func (h *BaseHandler) CanHandle(accessType permissions.AccessType) bool {
 	// Base handler can't handle any access type directly
@@ -54,13 +104,47 @@ func (h *BaseHandler) sendTextMessage(c telebot.Context, text string, markup *te
 		opts.ReplyMarkup = markup
 	}
 
-	_, err := c.Bot().Send(c.Recipient(), text, opts)
+	_, err := h.sendWithFloodRetry(func() (*telebot.Message, error) {
+		return c.Bot().Send(c.Recipient(), text, opts)
+	})
 	if err != nil {
 		h.logger.Errorf("Failed to send message: %v", err)
 	}
 	return err
 }
 
+// sendLongMessage sends text that may exceed Telegram's length limit as several messages,
+// split between lines, with the markup attached only to the last one
+func (h *BaseHandler) sendLongMessage(c telebot.Context, text string, markup *telebot.ReplyMarkup) error {
+	chunks := helpers.SplitMessage(text, constants.MaxMessageLength)
+	for i, chunk := range chunks {
+		var chunkMarkup *telebot.ReplyMarkup
+		if i == len(chunks)-1 {
+			chunkMarkup = markup
+		}
+		if err := h.sendTextMessage(c, chunk, chunkMarkup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendWithFloodRetry runs send, waiting out Telegram's retry_after and trying again when
+// the bot hits a flood limit. Waits longer than MaxFloodWait are not worth blocking on.
+func (h *BaseHandler) sendWithFloodRetry(send func() (*telebot.Message, error)) (*telebot.Message, error) {
+	for attempt := 0; ; attempt++ {
+		msg, err := send()
+
+		var flood telebot.FloodError
+		if !errors.As(err, &flood) || attempt >= constants.MaxFloodRetries || flood.RetryAfter > constants.MaxFloodWait {
+			return msg, err
+		}
+
+		h.logger.Warnf("Hit Telegram flood limit, retrying in %d seconds", flood.RetryAfter)
+		time.Sleep(time.Duration(flood.RetryAfter) * time.Second)
+	}
+}
+
 // sendTextMessageWithReturn sends a text message and returns the message for deletion
 func (h *BaseHandler) sendTextMessageWithReturn(c telebot.Context, text string, markup *telebot.ReplyMarkup) (*telebot.Message, error) {
 	opts := &telebot.SendOptions{
@@ -71,15 +155,54 @@ func (h *BaseHandler) sendTextMessageWithReturn(c telebot.Context, text string,
 		opts.ReplyMarkup = markup
 	}
 
-	msg, err := c.Bot().Send(c.Recipient(), text, opts)
+	msg, err := h.sendWithFloodRetry(func() (*telebot.Message, error) {
+		return c.Bot().Send(c.Recipient(), text, opts)
+	})
 	if err != nil {
 		h.logger.Errorf("Failed to send message: %v", err)
 	}
 	return msg, err
 }
 
-// sendQRCode sends a QR code for the given URL
-func (h *BaseHandler) sendQRCode(c telebot.Context, url string) error {
+// sendQRCode sends a QR code for the given URL with an optional caption
+func (h *BaseHandler) sendQRCode(c telebot.Context, url string, caption string) error {
+	return h.sendQRCodeWithMarkup(c, url, caption, nil)
+}
+
+// sendSubscriptionQR sends the QR code for a subscription with a button that resends the plain link
+func (h *BaseHandler) sendSubscriptionQR(c telebot.Context, subID string, caption string) error {
+	markup := &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{
+				{Text: h.t(i18n.CopyLinkButton), Data: copyLinkPrefix + subID},
+			},
+		},
+	}
+
+	return h.sendQRCodeWithMarkup(c, h.subscriptionURL(subID), caption, markup)
+}
+
+// handleCopyLinkCallback resends a subscription link as text for copying on desktop
+func (h *BaseHandler) handleCopyLinkCallback(c telebot.Context, data string) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	subID := strings.TrimPrefix(data, copyLinkPrefix)
+	if subID == "" {
+		return c.Send(h.t(i18n.UnknownAction))
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.CopyLinkText, html.EscapeString(h.subscriptionURL(subID))), nil)
+}
+
+// subscriptionURL builds the subscription URL for a SubID
+func (h *BaseHandler) subscriptionURL(subID string) string {
+	return fmt.Sprintf("%s%s?name=%s", h.config.Server.SubURLPrefix, subID, subID)
+}
+
+// sendQRCodeWithMarkup sends a QR code for the given URL with an optional caption and inline keyboard
+func (h *BaseHandler) sendQRCodeWithMarkup(c telebot.Context, url string, caption string, markup *telebot.ReplyMarkup) error {
 	// Generate QR code
 	qrBytes, err := h.qrService.GenerateQR(url)
 	if err != nil {
@@ -87,18 +210,197 @@ func (h *BaseHandler) sendQRCode(c telebot.Context, url string) error {
 		return err
 	}
 
-	// Create photo from bytes
-	reader := bytes.NewReader(qrBytes)
-	photo := &telebot.Photo{File: telebot.FromReader(reader)}
-
-	// Send photo
-	_, err = c.Bot().Send(c.Recipient(), photo)
+	// Send photo, with a fresh reader for every attempt
+	_, err = h.sendWithFloodRetry(func() (*telebot.Message, error) {
+		photo := &telebot.Photo{File: telebot.FromReader(bytes.NewReader(qrBytes)), Caption: caption}
+		return c.Bot().Send(c.Recipient(), photo, &telebot.SendOptions{ParseMode: telebot.ModeHTML, ReplyMarkup: markup})
+	})
 	if err != nil {
 		h.logger.Errorf("Failed to send QR code: %v", err)
 	}
 	return err
 }
 
+// progressMessage edits a loading message with progress, throttled to stay within Telegram limits
+type progressMessage struct {
+	bot    *telebot.Bot
+	msg    *telebot.Message
+	last   time.Time
+	logger *logrus.Logger
+}
+
+// newProgressMessage wraps a loading message so long operations can report progress on it
+func (h *BaseHandler) newProgressMessage(c telebot.Context, msg *telebot.Message) *progressMessage {
+	return &progressMessage{
+		bot:    c.Bot(),
+		msg:    msg,
+		last:   time.Now(),
+		logger: h.logger,
+	}
+}
+
+// Update replaces the message text unless it was edited less than ProgressUpdateInterval ago
+func (p *progressMessage) Update(text string) {
+	if p.msg == nil || time.Since(p.last) < constants.ProgressUpdateInterval*time.Second {
+		return
+	}
+	p.last = time.Now()
+
+	if _, err := p.bot.Edit(p.msg, text, &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+		p.logger.Warnf("Failed to update progress message: %v", err)
+	}
+}
+
+// qrCaption builds the QR code caption naming the user and the server, followed by the
+// configured connection instructions if any
+func (h *BaseHandler) qrCaption(username string) string {
+	caption := h.t(i18n.QRCaption, html.EscapeString(username), html.EscapeString(h.config.Server.Name))
+	if h.config.QR.Caption == "" {
+		return caption
+	}
+
+	instructions := strings.NewReplacer(
+		"{username}", html.EscapeString(username),
+		"{server}", html.EscapeString(h.config.Server.Name),
+	).Replace(h.config.QR.Caption)
+	return caption + "\n\n" + instructions
+}
+
+// sendPhoto sends the given image bytes as a photo with an optional caption
+func (h *BaseHandler) sendPhoto(c telebot.Context, data []byte, caption string) error {
+	_, err := h.sendWithFloodRetry(func() (*telebot.Message, error) {
+		photo := &telebot.Photo{
+			File:    telebot.FromReader(bytes.NewReader(data)),
+			Caption: caption,
+		}
+		return c.Bot().Send(c.Recipient(), photo, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to send photo: %v", err)
+	}
+	return err
+}
+
+// sendDocument sends the given bytes as a document attachment
+func (h *BaseHandler) sendDocument(c telebot.Context, data []byte, fileName string, caption string) error {
+	_, err := h.sendWithFloodRetry(func() (*telebot.Message, error) {
+		doc := &telebot.Document{
+			File:     telebot.FromReader(bytes.NewReader(data)),
+			FileName: fileName,
+			Caption:  caption,
+		}
+		return c.Bot().Send(c.Recipient(), doc)
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to send document: %v", err)
+	}
+	return err
+}
+
+// verifyCreatedClients re-reads the inbounds when client verification is on and splits the
+// created emails into those the panel really has and errors for the ones it doesn't
+func (h *BaseHandler) verifyCreatedClients(ctx context.Context, createdEmails []string) ([]string, []string) {
+	if !h.config.Server.VerifyClients || len(createdEmails) == 0 {
+		return createdEmails, nil
+	}
+
+	inbounds, err := h.xrayService.GetInbounds(ctx)
+	if err != nil {
+		h.logger.Errorf("Failed to verify created clients: %v", err)
+		return createdEmails, []string{h.t(i18n.AddMemberVerifyFailed, h.panelErrorText(err))}
+	}
+
+	missing := helpers.MissingClients(inbounds, createdEmails)
+	if len(missing) == 0 {
+		return createdEmails, nil
+	}
+
+	isMissing := make(map[string]bool, len(missing))
+	verifyErrors := make([]string, 0, len(missing))
+	for _, email := range missing {
+		h.logger.Warnf("Panel reported success but client %s is missing", email)
+		isMissing[email] = true
+		verifyErrors = append(verifyErrors, h.t(i18n.AddMemberVerifyMissing, email))
+	}
+
+	var verified []string
+	for _, email := range createdEmails {
+		if !isMissing[email] {
+			verified = append(verified, email)
+		}
+	}
+	return verified, verifyErrors
+}
+
+// deepLinkHandler returns the handler for a /start deep-link payload such as
+// "/start add_member". Plain /start, unknown payloads and commands missing from the
+// caller's handlers, i.e. not allowed for their access type, report false.
+func (h *BaseHandler) deepLinkHandler(c telebot.Context, handlers map[string]func(telebot.Context) error) (func(telebot.Context) error, bool) {
+	if c.Message() == nil || !strings.HasPrefix(c.Text(), commands.Start+" ") {
+		return nil, false
+	}
+
+	payload := strings.TrimSpace(c.Message().Payload)
+	command, ok := commands.CommandForKey(payload)
+	if !ok {
+		h.logger.Debugf("Ignoring unknown deep-link payload %q", payload)
+		return nil, false
+	}
+
+	handler, ok := handlers[command]
+	if !ok {
+		h.logger.Debugf("Ignoring deep-link payload %q not available to user %d", payload, c.Sender().ID)
+	}
+	return handler, ok
+}
+
+// isCancelRequest reports whether text asks to abort the current flow,
+// either typed as "Cancel" or /cancel or sent with the Cancel button
+func isCancelRequest(text string) bool {
+	text = strings.TrimSpace(strings.TrimPrefix(text, "❌"))
+	return strings.EqualFold(text, commands.Cancel) || strings.EqualFold(text, commands.Label(commands.Cancel)) ||
+		strings.EqualFold(text, commands.CancelCommand)
+}
+
+// menuItem is a main menu button: an emoji and the command it triggers
+type menuItem struct {
+	emoji   string
+	command string
+}
+
+// label returns the button text for the menu item
+func (m menuItem) label() string {
+	return m.emoji + " " + commands.Label(m.command)
+}
+
+// adminMenu is the admin main menu layout, shared by the reply and inline keyboards
+var adminMenu = [][]menuItem{
+	{{"👤", commands.AddMember}, {"🟢", commands.OnlineMembers}},
+	{{"✏️", commands.EditMember}, {"📈", commands.DetailedUsage}},
+	{{"📄", commands.ExportUsageCSV}, {"📊", commands.TrafficChart}},
+	{{"➕", commands.AddTrusted}, {"🚫", commands.RevokeTrusted}},
+	{{"👑", commands.AddAdmin}, {"❎", commands.RevokeAdmin}},
+	{{"🔄", commands.ResetNetworkUsage}, {"🧹", commands.DeleteExpired}},
+	{{"☑️", commands.BulkToggle}, {"🔍", commands.Reconcile}},
+	{{"💾", commands.Backup}, {"♻️", commands.Restore}},
+	{{"🏆", commands.TopUsers}, {"🩺", commands.HealthCheck}},
+	{{"🔌", commands.ToggleInbound}, {"📣", commands.Broadcast}},
+}
+
+// createInlineMainMenu creates the admin main menu as inline callback buttons
+func (h *BaseHandler) createInlineMainMenu() *telebot.ReplyMarkup {
+	var rows [][]telebot.InlineButton
+	for _, items := range adminMenu {
+		var row []telebot.InlineButton
+		for _, item := range items {
+			row = append(row, telebot.InlineButton{Text: item.label(), Data: menuCallbackPrefix + item.command})
+		}
+		rows = append(rows, row)
+	}
+
+	return &telebot.ReplyMarkup{InlineKeyboard: rows}
+}
+
 // createMainKeyboard creates the main keyboard for the given access type
 func (h *BaseHandler) createMainKeyboard(accessType permissions.AccessType) *telebot.ReplyMarkup {
 	markup := &telebot.ReplyMarkup{
@@ -109,28 +411,25 @@ func (h *BaseHandler) createMainKeyboard(accessType permissions.AccessType) *tel
 
 	switch accessType {
 	case permissions.Admin:
-		rows = []telebot.Row{
-			{
-				telebot.Btn{Text: "👤 " + commands.AddMember},
-				telebot.Btn{Text: "🟢 " + commands.OnlineMembers},
-			},
-			{
-				telebot.Btn{Text: "✏️ " + commands.EditMember},
-				telebot.Btn{Text: "📈 " + commands.DetailedUsage},
-			},
-			{
-				telebot.Btn{Text: "➕ " + commands.AddTrusted},
-				telebot.Btn{Text: "🚫 " + commands.RevokeTrusted},
-			},
-			{
-				telebot.Btn{Text: "🔄 " + commands.ResetNetworkUsage},
-			},
+		if h.config.Telegram.InlineMenu {
+			return h.createInlineMainMenu()
+		}
+		for _, items := range adminMenu {
+			var row telebot.Row
+			for _, item := range items {
+				row = append(row, telebot.Btn{Text: item.label()})
+			}
+			rows = append(rows, row)
 		}
 	case permissions.Trusted:
 		rows = []telebot.Row{
 			{
-				telebot.Btn{Text: "➕ " + commands.AddMember},
-				telebot.Btn{Text: "🗑 " + commands.DeleteMember},
+				telebot.Btn{Text: "➕ " + commands.Label(commands.AddMember)},
+				telebot.Btn{Text: "🗑 " + commands.Label(commands.DeleteMember)},
+			},
+			{
+				telebot.Btn{Text: "📋 " + commands.Label(commands.MyConfigs)},
+				telebot.Btn{Text: "📊 " + commands.Label(commands.MyUsage)},
 			},
 		}
 	}
@@ -147,7 +446,7 @@ func (h *BaseHandler) createReturnKeyboard() *telebot.ReplyMarkup {
 
 	markup.Reply(
 		telebot.Row{
-			telebot.Btn{Text: "↩️ " + commands.ReturnToMainMenu},
+			telebot.Btn{Text: "↩️ " + commands.Label(commands.ReturnToMainMenu)},
 		},
 	)
 
@@ -157,7 +456,7 @@ func (h *BaseHandler) createReturnKeyboard() *telebot.ReplyMarkup {
 // HandleSelectServer handles server selection
 func (h *BaseHandler) HandleSelectServer(c telebot.Context) error {
 	// Since we have a single server configuration, just show a message
-	return h.sendTextMessage(c, "Server configuration is handled automatically.", h.createReturnKeyboard())
+	return h.sendTextMessage(c, h.t(i18n.ServerSelectionAutomatic), h.createReturnKeyboard())
 }
 
 // validateServerSelection validates that a server is selected
@@ -165,3 +464,57 @@ func (h *BaseHandler) validateServerSelection(userID int64) error {
 	// Since we have a single server configuration, always return nil
 	return nil
 }
+
+// getEnabledInbounds returns the inbounds new clients are created on: the enabled ones that
+// INBOUNDS_INCLUDE and INBOUNDS_EXCLUDE allow. It returns ErrNoEnabledInbounds if none is left.
+func (h *BaseHandler) getEnabledInbounds(ctx context.Context) ([]models.Inbound, error) {
+	inbounds, err := h.xrayService.GetInbounds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inbounds: %w", err)
+	}
+
+	var enabledInbounds []models.Inbound
+	for _, inbound := range inbounds {
+		if inbound.Enable && h.config.Server.TargetsInbound(inbound.ID, inbound.Remark) {
+			enabledInbounds = append(enabledInbounds, inbound)
+		}
+	}
+
+	if len(enabledInbounds) == 0 {
+		if len(inbounds) > 0 {
+			h.logger.Warnf("None of the %d inbounds is both enabled and allowed by INBOUNDS_INCLUDE/INBOUNDS_EXCLUDE", len(inbounds))
+		}
+		return nil, ErrNoEnabledInbounds
+	}
+
+	return enabledInbounds, nil
+}
+
+// panelErrorMessage explains a failed panel request, telling rejected credentials and
+// panel-side errors apart from connection problems
+func (h *BaseHandler) panelErrorMessage(err error) string {
+	var apiErr *xrayclient.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.IsAuth():
+			return h.t(i18n.PanelAuthError)
+		case apiErr.IsServer():
+			return h.t(i18n.PanelServerError, apiErr.Status)
+		}
+	}
+	return h.t(i18n.ServerDataConnectionError)
+}
+
+// enabledInboundsErrorMessage explains a getEnabledInbounds failure. Admins also get a hint
+// on how to enable an inbound, since only they can fix it.
+func (h *BaseHandler) enabledInboundsErrorMessage(err error, accessType permissions.AccessType) string {
+	if !errors.Is(err, ErrNoEnabledInbounds) {
+		return h.panelErrorMessage(err)
+	}
+
+	message := h.t(i18n.NoEnabledInbounds)
+	if accessType == permissions.Admin {
+		message += h.t(i18n.NoEnabledInboundsHint, "🔌 "+commands.Label(commands.ToggleInbound))
+	}
+	return message
+}
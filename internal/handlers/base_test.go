@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifyCreatedClientsHidesPanelError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "test"})
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+			return
+		}
+		http.Error(w, "open /etc/x-ui/x-ui.db: permission denied", http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+	h := newTestAdminHandler(t, server.URL)
+	h.config.Server.VerifyClients = true
+
+	created := []string{"alice-1", "alice-2"}
+	verified, verifyErrors := h.verifyCreatedClients(context.Background(), created)
+
+	if len(verified) != len(created) {
+		t.Errorf("verified = %v, want the created clients kept when verification fails", verified)
+	}
+	if len(verifyErrors) != 1 {
+		t.Fatalf("verifyErrors = %v, want 1 error", verifyErrors)
+	}
+	if strings.Contains(verifyErrors[0], "x-ui.db") {
+		t.Errorf("verifyErrors[0] = %q, want the raw panel error left out", verifyErrors[0])
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/models"
+)
+
+func TestIsCancelRequest(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "/cancel", want: true},
+		{text: "Cancel", want: true},
+		{text: "cancel", want: true},
+		{text: "  CANCEL ", want: true},
+		{text: "❌ Cancel", want: true},
+		{text: "❌ " + commands.Label(commands.Cancel), want: true},
+		{text: "", want: false},
+		{text: "Cancellation", want: false},
+		{text: "alice", want: false},
+		{text: "/cancel now", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isCancelRequest(tt.text); got != tt.want {
+			t.Errorf("isCancelRequest(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAdminCancelFromEveryState(t *testing.T) {
+	bot, api := newTestBot(t)
+	// The panel is never reached: cancelling must not depend on it
+	h := newTestAdminHandler(t, "http://127.0.0.1:1")
+
+	for state := models.Default; state <= models.AwaitConfirmBroadcast; state++ {
+		for _, text := range []string{"/cancel", "❌ " + commands.Label(commands.Cancel)} {
+			payload := "alice"
+			if err := h.stateService.SetState(testAdminID, models.UserState{
+				State:    state,
+				Payload:  &payload,
+				Selected: map[string]bool{"alice": true},
+			}); err != nil {
+				t.Fatalf("failed to set state: %v", err)
+			}
+
+			sent := api.calls("sendMessage")
+			if err := h.Handle(context.Background(), textUpdate(bot, text)); err != nil {
+				t.Fatalf("state %d: Handle(%q) returned error: %v", state, text, err)
+			}
+
+			userState, err := h.stateService.GetState(testAdminID)
+			if err != nil {
+				t.Fatalf("failed to get state: %v", err)
+			}
+			if userState.State != models.Default || userState.Payload != nil || len(userState.Selected) != 0 {
+				t.Errorf("state %d: %q left state %d, payload %v, selection %v; want a cleared state",
+					state, text, userState.State, userState.Payload, userState.Selected)
+			}
+			if api.calls("sendMessage") != sent+1 {
+				t.Errorf("state %d: %q didn't send the main menu", state, text)
+			}
+		}
+	}
+}
+
+func TestTrustedCancelFromEveryState(t *testing.T) {
+	bot, api := newTestBot(t)
+	admin := newTestAdminHandler(t, "http://127.0.0.1:1")
+	h := NewTrustedHandler(&admin.BaseHandler, admin.storageService)
+
+	for _, state := range []models.ConversationState{
+		models.Default,
+		models.AwaitConfirmMemberDeletion,
+		models.StateAwaitingVpnUsername,
+		models.StateAwaitingVpnPassword,
+	} {
+		payload := "alice"
+		if err := h.stateService.SetState(testAdminID, models.UserState{State: state, Payload: &payload}); err != nil {
+			t.Fatalf("failed to set state: %v", err)
+		}
+
+		sent := api.calls("sendMessage")
+		if err := h.Handle(context.Background(), textUpdate(bot, "/cancel")); err != nil {
+			t.Fatalf("state %d: Handle returned error: %v", state, err)
+		}
+
+		userState, err := h.stateService.GetState(testAdminID)
+		if err != nil {
+			t.Fatalf("failed to get state: %v", err)
+		}
+		if userState.State != models.Default || userState.Payload != nil {
+			t.Errorf("state %d: /cancel left state %d, payload %v; want a cleared state", state, userState.State, userState.Payload)
+		}
+		if api.calls("sendMessage") != sent+1 {
+			t.Errorf("state %d: /cancel didn't send the main menu", state)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"html"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -12,6 +14,7 @@ import (
 	"xui-tg-admin/internal/models"
 	"xui-tg-admin/internal/permissions"
 	"xui-tg-admin/internal/services"
+	"xui-tg-admin/internal/version"
 )
 
 // DemoHandler handles demo commands
@@ -79,26 +82,26 @@ func (h *DemoHandler) initializeCommands() {
 func (h *DemoHandler) getButtonCommand(text string) string {
 	// Check for specific button patterns
 	switch text {
-	case "↩️ " + commands.ReturnToMainMenu:
+	case "↩️ " + commands.Label(commands.ReturnToMainMenu):
 		return commands.ReturnToMainMenu
-	case "∞ " + commands.Infinite:
+	case "∞ " + commands.Label(commands.Infinite):
 		return commands.Infinite
-	case "✅ " + commands.Confirm:
+	case "✅ " + commands.Label(commands.Confirm):
 		return commands.Confirm
-	case "❌ " + commands.Cancel:
+	case "❌ " + commands.Label(commands.Cancel):
 		return commands.Cancel
-	case "🔗 " + commands.ViewConfig:
+	case "🔗 " + commands.Label(commands.ViewConfig):
 		return commands.ViewConfig
-	case "🔄 " + commands.ResetTraffic:
+	case "🔄 " + commands.Label(commands.ResetTraffic):
 		return commands.ResetTraffic
-	case "🗑️ " + commands.Delete:
+	case "🗑️ " + commands.Label(commands.Delete):
 		return commands.Delete
 	}
 
 	// For other buttons, try to extract command after emoji
 	if len(text) > 2 && text[0] != '/' {
 		if spaceIndex := strings.Index(text, " "); spaceIndex > 0 {
-			return text[spaceIndex+1:]
+			return commands.Resolve(text[spaceIndex+1:])
 		}
 	}
 
@@ -129,12 +132,12 @@ func (h *DemoHandler) handleStart(c telebot.Context) error {
 	}
 
 	// Demo permission no longer exists
-	return c.Send("You don't have permission to use this bot.")
+	return h.sendTextMessage(c, h.welcomeMessage(permissions.None), nil)
 }
 
 // handleAbout handles the About command
 func (h *DemoHandler) handleAbout(c telebot.Context) error {
-	aboutText := `<b>X-UI Telegram Bot</b>
+	aboutText := fmt.Sprintf(`<b>X-UI Telegram Bot</b>
 
 This bot allows you to manage your X-ray VPN configurations through Telegram.
 
@@ -144,10 +147,10 @@ This bot allows you to manage your X-ray VPN configurations through Telegram.
 • Reset traffic usage
 • Generate QR codes for easy configuration
 
-<b>Version:</b> 1.0.0
+<b>Version:</b> %s (%s)
 <b>Developed by:</b> X-UI Team
 
-For more information, please contact an administrator.`
+For more information, please contact an administrator.`, html.EscapeString(version.Version), html.EscapeString(version.Commit))
 
 	return h.sendTextMessage(c, aboutText, h.createReturnKeyboard())
 }
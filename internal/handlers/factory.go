@@ -15,6 +15,8 @@ import (
 type MessageHandler interface {
 	Handle(ctx context.Context, c telebot.Context) error
 	CanHandle(accessType permissions.AccessType) bool
+	// ShowMenu re-sends the main keyboard, keeping the selected member and other payload
+	ShowMenu(c telebot.Context) error
 }
 
 // HandlerFactory creates message handlers
@@ -23,6 +25,7 @@ type HandlerFactory struct {
 	stateService   *services.UserStateService
 	qrService      *services.QRService
 	storageService *services.StorageService
+	chartService   *services.ChartService
 	config         *config.Config
 	logger         *logrus.Logger
 }
@@ -33,6 +36,7 @@ func NewHandlerFactory(
 	stateService *services.UserStateService,
 	qrService *services.QRService,
 	storageService *services.StorageService,
+	chartService *services.ChartService,
 	config *config.Config,
 	logger *logrus.Logger,
 ) *HandlerFactory {
@@ -41,6 +45,7 @@ func NewHandlerFactory(
 		stateService:   stateService,
 		qrService:      qrService,
 		storageService: storageService,
+		chartService:   chartService,
 		config:         config,
 		logger:         logger,
 	}
@@ -50,7 +55,7 @@ func NewHandlerFactory(
 func (f *HandlerFactory) CreateHandler(accessType permissions.AccessType) MessageHandler {
 	switch accessType {
 	case permissions.Admin:
-		return NewAdminHandler(f.xrayService, f.stateService, f.qrService, f.storageService, f.config, f.logger)
+		return NewAdminHandler(f.xrayService, f.stateService, f.qrService, f.storageService, f.chartService, f.config, f.logger)
 	case permissions.Trusted:
 		baseHandler := NewBaseHandler(f.xrayService, f.stateService, f.qrService, f.config, f.logger)
 		return NewTrustedHandler(&baseHandler, f.storageService)
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/config"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/services"
+)
+
+// testAdminID is the Telegram ID the test updates come from
+const testAdminID int64 = 42
+
+// mockTelegram is a Telegram Bot API that accepts every request and records the methods called
+type mockTelegram struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+func (m *mockTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.methods = append(m.methods, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+	m.mu.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":     true,
+		"result": map[string]interface{}{"message_id": 1, "date": 0, "chat": map[string]interface{}{"id": testAdminID}},
+	})
+}
+
+// calls returns how many times the Bot API method was called
+func (m *mockTelegram) calls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	for _, called := range m.methods {
+		if called == method {
+			n++
+		}
+	}
+	return n
+}
+
+// newTestBot returns a bot talking to a mock Telegram API
+func newTestBot(t *testing.T) (*telebot.Bot, *mockTelegram) {
+	t.Helper()
+	api := &mockTelegram{}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	bot, err := telebot.NewBot(telebot.Settings{Token: "test", URL: server.URL, Offline: true})
+	if err != nil {
+		t.Fatalf("failed to create bot: %v", err)
+	}
+	return bot, api
+}
+
+// newInboundsPanel returns an X-UI panel that lists inbounds and serves no other endpoint
+func newInboundsPanel(t *testing.T, inbounds []models.Inbound) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "test"})
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+		case "/xui/API/inbounds":
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "obj": inbounds})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// updateClientPanel is an X-UI panel that lists inbounds, records the clients sent to
+// updateClient and rejects updates in some inbounds
+type updateClientPanel struct {
+	inbounds     []models.Inbound
+	failInbounds map[int]bool
+
+	mu      sync.Mutex
+	updated []map[string]interface{}
+}
+
+func (p *updateClientPanel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/login":
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "test"})
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	case r.URL.Path == "/xui/API/inbounds":
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "obj": p.inbounds})
+	case strings.HasPrefix(r.URL.Path, "/xui/API/inbounds/updateClient/"):
+		var body struct {
+			ID       int    `json:"id"`
+			Settings string `json:"settings"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var settings struct {
+			Clients []map[string]interface{} `json:"clients"`
+		}
+		json.Unmarshal([]byte(body.Settings), &settings)
+
+		if p.failInbounds[body.ID] {
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "msg": "record not found"})
+			return
+		}
+
+		p.mu.Lock()
+		p.updated = append(p.updated, settings.Clients...)
+		p.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// newTestConfig returns a config for a panel at apiURL
+func newTestConfig(apiURL string) *config.Config {
+	return &config.Config{
+		Language: i18n.English,
+		Server:   config.ServerConfig{APIURL: apiURL, User: "admin", Password: "admin"},
+		Telegram: config.TelegramConfig{AdminIDs: []int64{testAdminID}},
+	}
+}
+
+// newTestAdminHandler returns an admin handler for a panel at apiURL, with storage in a
+// temporary directory
+func newTestAdminHandler(t *testing.T, apiURL string) *AdminHandler {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cfg := newTestConfig(apiURL)
+	storage, err := services.NewStorageService(filepath.Join(t.TempDir(), "data.json"), "", nil, logger)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+
+	return NewAdminHandler(
+		services.NewXrayService(cfg, logger),
+		services.NewUserStateService(logger),
+		services.NewQRService(cfg, logger),
+		storage,
+		services.NewChartService(logger),
+		cfg,
+		logger,
+	)
+}
+
+// textUpdate returns the context of a text message sent by the test admin
+func textUpdate(bot *telebot.Bot, text string) telebot.Context {
+	sender := &telebot.User{ID: testAdminID}
+	return bot.NewContext(telebot.Update{Message: &telebot.Message{
+		ID:     1,
+		Text:   text,
+		Sender: sender,
+		Chat:   &telebot.Chat{ID: testAdminID},
+	}})
+}
@@ -10,6 +10,8 @@ import (
 
 	"xui-tg-admin/internal/commands"
 	"xui-tg-admin/internal/config"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
 	"xui-tg-admin/internal/models"
 	"xui-tg-admin/internal/permissions"
 	"xui-tg-admin/internal/services"
@@ -80,26 +82,26 @@ func (h *MemberHandler) initializeCommands() {
 func (h *MemberHandler) getButtonCommand(text string) string {
 	// Check for specific button patterns
 	switch text {
-	case "↩️ " + commands.ReturnToMainMenu:
+	case "↩️ " + commands.Label(commands.ReturnToMainMenu):
 		return commands.ReturnToMainMenu
-	case "∞ " + commands.Infinite:
+	case "∞ " + commands.Label(commands.Infinite):
 		return commands.Infinite
-	case "✅ " + commands.Confirm:
+	case "✅ " + commands.Label(commands.Confirm):
 		return commands.Confirm
-	case "❌ " + commands.Cancel:
+	case "❌ " + commands.Label(commands.Cancel):
 		return commands.Cancel
-	case "🔗 " + commands.ViewConfig:
+	case "🔗 " + commands.Label(commands.ViewConfig):
 		return commands.ViewConfig
-	case "🔄 " + commands.ResetTraffic:
+	case "🔄 " + commands.Label(commands.ResetTraffic):
 		return commands.ResetTraffic
-	case "🗑️ " + commands.Delete:
+	case "🗑️ " + commands.Label(commands.Delete):
 		return commands.Delete
 	}
 
 	// For other buttons, try to extract command after emoji
 	if len(text) > 2 && text[0] != '/' {
 		if spaceIndex := strings.Index(text, " "); spaceIndex > 0 {
-			return text[spaceIndex+1:]
+			return commands.Resolve(text[spaceIndex+1:])
 		}
 	}
 
@@ -130,7 +132,7 @@ func (h *MemberHandler) handleStart(c telebot.Context) error {
 	}
 
 	// Member permission no longer exists
-	return c.Send("You don't have permission to use this bot.")
+	return c.Send(h.t(i18n.NoPermission))
 }
 
 // handleSelectServer handles server selection
@@ -145,22 +147,35 @@ func (h *MemberHandler) handleCreateNewConfig(c telebot.Context) error {
 		return h.handleSelectServer(c)
 	}
 
-	// Get subscription URL for the user's Telegram ID
-	username := fmt.Sprintf("tg_%d", c.Sender().ID)
-	subURL, err := h.xrayService.GetSubscriptionURL(context.Background(), username)
+	// Find the clients carrying the user's Telegram ID as their TgId
+	clients, err := h.xrayService.GetClientsByCreator(context.Background(), c.Sender().ID)
 	if err != nil {
-		h.logger.Errorf("Failed to get subscription URL: %v", err)
-		return h.sendTextMessage(c, fmt.Sprintf("Failed to get subscription URL: %v", err), nil)
+		h.logger.Errorf("Failed to get clients: %v", err)
+		return h.sendTextMessage(c, h.t(i18n.MemberSubscriptionFailed, h.panelErrorText(err)), nil)
+	}
+
+	var subID, username string
+	for _, ref := range clients {
+		if ref.Client.SubID != "" {
+			subID = ref.Client.SubID
+			username = helpers.ExtractBaseUsername(ref.Client.Email)
+			break
+		}
+	}
+
+	if subID == "" {
+		return h.sendTextMessage(c, h.t(i18n.MemberNoAccount), h.createReturnKeyboard())
 	}
 
 	// Send subscription URL
-	err = h.sendTextMessage(c, fmt.Sprintf("Your subscription URL:\n\n%s", subURL), h.createReturnKeyboard())
+	subURL := h.subscriptionURL(subID)
+	err = h.sendTextMessage(c, h.t(i18n.MemberSubscriptionURL, subURL), h.createReturnKeyboard())
 	if err != nil {
 		return err
 	}
 
 	// Send QR code
-	return h.sendQRCode(c, subURL)
+	return h.sendSubscriptionQR(c, subID, h.qrCaption(username))
 }
 
 // handleViewConfigsInfo handles the View Configs Info command
@@ -174,48 +189,55 @@ func (h *MemberHandler) handleViewConfigsInfo(c telebot.Context) error {
 	inbounds, err := h.xrayService.GetInbounds(context.Background())
 	if err != nil {
 		h.logger.Errorf("Failed to get inbounds: %v", err)
-		return h.sendTextMessage(c, fmt.Sprintf("Failed to get inbounds: %v", err), nil)
+		return h.sendTextMessage(c, h.t(i18n.MemberInboundsFailed, h.panelErrorText(err)), nil)
 	}
 
-	// Find client with matching Telegram ID
-	tgID := fmt.Sprintf("%d", c.Sender().ID)
-	var found bool
-	var message string
+	// Emails are named after the base username, so match on the TgId stored in the client
+	// settings. It only carries the member's ID when it was set to it in the panel.
+	emails := make(map[int]map[string]bool)
+	for _, ref := range helpers.GroupClientsByCreator(inbounds)[fmt.Sprintf("%d", c.Sender().ID)] {
+		if emails[ref.InboundID] == nil {
+			emails[ref.InboundID] = make(map[string]bool)
+		}
+		emails[ref.InboundID][ref.Client.Email] = true
+	}
+
+	var sb strings.Builder
 
 	for _, inbound := range inbounds {
 		for _, clientStat := range inbound.ClientStats {
-			// This is a simplified check; in a real implementation, you would need to
-			// extract the client details from the inbound settings to check the TgId field
-			if clientStat.Email == fmt.Sprintf("tg_%s", tgID) {
-				found = true
-
-				// Format traffic usage
-				upGB := float64(clientStat.Up) / (1024 * 1024 * 1024)
-				downGB := float64(clientStat.Down) / (1024 * 1024 * 1024)
-				totalGB := float64(clientStat.Total) / (1024 * 1024 * 1024)
-
-				message = fmt.Sprintf("Your configuration:\n\n"+
-					"Email: %s\n"+
-					"Upload: %.2f GB\n"+
-					"Download: %.2f GB\n"+
-					"Total: %.2f GB\n"+
-					"Status: %s",
-					clientStat.Email,
-					upGB,
-					downGB,
-					totalGB,
-					getStatusText(clientStat.Enable))
-
-				break
+			if !emails[inbound.ID][clientStat.Email] {
+				continue
 			}
-		}
-		if found {
-			break
+
+			// Format traffic usage
+			upGB := float64(clientStat.Up) / (1024 * 1024 * 1024)
+			downGB := float64(clientStat.Down) / (1024 * 1024 * 1024)
+			totalGB := float64(clientStat.Total) / (1024 * 1024 * 1024)
+
+			sb.WriteString(fmt.Sprintf("\n"+
+				"Inbound: %s\n"+
+				"Protocol: %s\n"+
+				"Port: %d\n"+
+				"Email: %s\n"+
+				"Upload: %.2f GB\n"+
+				"Download: %.2f GB\n"+
+				"Total: %.2f GB\n"+
+				"Status: %s\n",
+				inbound.Remark,
+				inbound.Protocol,
+				inbound.Port,
+				clientStat.Email,
+				upGB,
+				downGB,
+				totalGB,
+				getStatusText(clientStat.Enable)))
 		}
 	}
 
-	if !found {
-		message = "You don't have any active configurations. Please use 'Create New Config' to create one."
+	message := "Your configuration:\n" + sb.String()
+	if sb.Len() == 0 {
+		message = h.t(i18n.MemberNoAccount)
 	}
 
 	return h.sendTextMessage(c, message, h.createReturnKeyboard())
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/i18n"
+)
+
+// loadingEmoji starts every loading message and is replaced by the animation frames
+const loadingEmoji = "⏳ "
+
+// loadingFrames are cycled in front of a loading message while LOADING_ANIMATION is on
+var loadingFrames = []string{"🕐 ", "🕒 ", "🕕 ", "🕘 "}
+
+// startOperation bounds a long operation with a watchdog. Its context is cancelled after
+// OperationTimeout, so loops can stop and report what they got done. While it runs, the
+// loading message sent with text is animated if LOADING_ANIMATION is on, and after
+// OperationNoticeDelay it says the bot is still working. Operations that report progress
+// on the loading message pass nil instead. The returned stop function must be called when
+// the operation ends; the loading message is no longer edited once it returns.
+func (h *BaseHandler) startOperation(c telebot.Context, loadingMsg *telebot.Message, text string) (context.Context, func()) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.OperationTimeout*time.Second)
+	if loadingMsg == nil {
+		return ctx, cancel
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		h.animateLoading(ctx, done, c.Bot(), loadingMsg, text)
+	}()
+
+	return ctx, func() {
+		close(done)
+		wg.Wait()
+		cancel()
+	}
+}
+
+// animateLoading edits the loading message until done is closed or ctx ends. Without the
+// animation the only edit is the still-working notice.
+func (h *BaseHandler) animateLoading(ctx context.Context, done <-chan struct{}, bot *telebot.Bot, msg *telebot.Message, text string) {
+	animate := h.config.Telegram.LoadingAnimation
+	interval := constants.OperationNoticeDelay * time.Second
+	if animate {
+		interval = constants.LoadingFrameInterval * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	last := text
+	for frame := 1; ; frame++ {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := text
+		if time.Since(start) >= constants.OperationNoticeDelay*time.Second {
+			current = h.t(i18n.OperationStillWorking)
+		}
+		if animate {
+			current = loadingFrames[frame%len(loadingFrames)] + strings.TrimPrefix(current, loadingEmoji)
+		}
+		if current == last {
+			continue
+		}
+		last = current
+
+		if _, err := bot.Edit(msg, current, &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+			h.logger.Warnf("Failed to update loading message: %v", err)
+		}
+	}
+}
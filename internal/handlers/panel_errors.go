@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/pkg/xrayclient"
+)
+
+// panelErrorHints maps lowercase substrings of known X-UI and connection errors to the
+// message shown instead of the raw error
+var panelErrorHints = []struct {
+	substring string
+	key       i18n.Key
+}{
+	{"duplicate email", i18n.PanelErrDuplicateEmail},
+	{"client not found", i18n.PanelErrClientNotFound},
+	{"record not found", i18n.PanelErrInboundNotFound},
+	{"port already exists", i18n.PanelErrPortInUse},
+	{"connection refused", i18n.PanelErrUnreachable},
+	{"no such host", i18n.PanelErrUnreachable},
+	{"deadline exceeded", i18n.PanelErrUnreachable},
+	{"timeout", i18n.PanelErrUnreachable},
+}
+
+// panelErrorText describes a failed panel request for the user. Known X-UI errors get an
+// actionable explanation; anything else gets a generic message, and the raw error, which
+// may hold panel internals, only goes to the log.
+func (h *BaseHandler) panelErrorText(err error) string {
+	if errors.Is(err, xrayclient.ErrClientNotFound) {
+		return h.t(i18n.PanelErrClientNotFound)
+	}
+
+	var apiErr *xrayclient.APIError
+	isAPIErr := errors.As(err, &apiErr)
+	if isAPIErr && apiErr.IsAuth() {
+		return h.t(i18n.PanelErrAuth)
+	}
+
+	text := strings.ToLower(err.Error())
+	for _, hint := range panelErrorHints {
+		if strings.Contains(text, hint.substring) {
+			return h.t(hint.key)
+		}
+	}
+
+	if isAPIErr && apiErr.IsServer() {
+		return h.t(i18n.PanelErrServer, apiErr.Status)
+	}
+
+	h.logger.WithError(err).Warn("Unrecognized panel error")
+	return h.t(i18n.PanelErrUnknown)
+}
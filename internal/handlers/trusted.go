@@ -2,18 +2,23 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	telebot "gopkg.in/telebot.v3"
 
 	"xui-tg-admin/internal/commands"
+	"xui-tg-admin/internal/constants"
 	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
 	"xui-tg-admin/internal/models"
 	"xui-tg-admin/internal/permissions"
 	"xui-tg-admin/internal/services"
+	"xui-tg-admin/pkg/xrayclient"
 )
 
 // TrustedHandler handles trusted user operations
@@ -51,8 +56,8 @@ func (h *TrustedHandler) Handle(ctx context.Context, c telebot.Context) error {
 
 	// Check account limit before any operation
 	accountCount := h.storageService.GetUserAccountCount(userID)
-	if accountCount >= 3 && c.Text() == "➕ "+commands.AddMember {
-		return c.Send("You can create maximum 3 accounts.")
+	if accountCount >= constants.MaxTrustedAccounts && c.Text() == "➕ "+commands.Label(commands.AddMember) {
+		return c.Send(h.t(i18n.AccountLimitReached, constants.MaxTrustedAccounts))
 	}
 
 	// Get user state
@@ -62,6 +67,17 @@ func (h *TrustedHandler) Handle(ctx context.Context, c telebot.Context) error {
 		return err
 	}
 
+	// Cancel aborts whatever flow is in progress and returns to the main menu
+	if isCancelRequest(c.Text()) {
+		return h.handleStart(c)
+	}
+
+	// A /start deep link abandons the current flow and jumps into the linked one
+	if handler, ok := h.deepLinkHandler(c, h.commandHandlers); ok {
+		h.stateService.WithConversationState(userID, models.Default)
+		return handler(c)
+	}
+
 	// Handle based on state
 	switch userState.State {
 	case models.Default:
@@ -80,6 +96,9 @@ func (h *TrustedHandler) initializeCommands() {
 		commands.Start:            h.handleStart,
 		commands.AddMember:        h.handleAddMember,
 		commands.DeleteMember:     h.handleDeleteMember,
+		commands.MyConfigs:        h.handleMyConfigs,
+		commands.MyUsage:          h.handleMyUsage,
+		commands.Reminders:        h.handleReminders,
 		commands.ReturnToMainMenu: h.handleStart,
 		commands.Cancel:           h.handleStart,
 	}
@@ -89,22 +108,26 @@ func (h *TrustedHandler) initializeCommands() {
 func (h *TrustedHandler) getButtonCommand(text string) string {
 	// Check for specific button patterns
 	switch text {
-	case "↩️ " + commands.ReturnToMainMenu:
+	case "↩️ " + commands.Label(commands.ReturnToMainMenu):
 		return commands.ReturnToMainMenu
-	case "❌ " + commands.Cancel:
+	case "❌ " + commands.Label(commands.Cancel):
 		return commands.Cancel
-	case "✅ " + commands.Confirm:
+	case "✅ " + commands.Label(commands.Confirm):
 		return commands.Confirm
-	case "➕ " + commands.AddMember:
+	case "➕ " + commands.Label(commands.AddMember):
 		return commands.AddMember
-	case "🗑 " + commands.DeleteMember:
+	case "🗑 " + commands.Label(commands.DeleteMember):
 		return commands.DeleteMember
+	case "📋 " + commands.Label(commands.MyConfigs):
+		return commands.MyConfigs
+	case "📊 " + commands.Label(commands.MyUsage):
+		return commands.MyUsage
 	}
 
 	// For other buttons, try to extract command after emoji
 	if len(text) > 2 && text[0] != '/' {
 		if spaceIndex := strings.Index(text, " "); spaceIndex > 0 {
-			return text[spaceIndex+1:]
+			return commands.Resolve(text[spaceIndex+1:])
 		}
 	}
 
@@ -125,17 +148,30 @@ func (h *TrustedHandler) handleDefaultState(c telebot.Context) error {
 	return h.handleStart(c)
 }
 
+// ShowMenu re-sends the main menu without the welcome text, keeping the payload
+func (h *TrustedHandler) ShowMenu(c telebot.Context) error {
+	if err := h.stateService.WithConversationState(c.Sender().ID, models.Default); err != nil {
+		h.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+
+	return h.sendTextMessage(c, h.t(i18n.TrustedMainMenu), h.createMainKeyboard(permissions.Trusted))
+}
+
 // handleStart handles the start command
 func (h *TrustedHandler) handleStart(c telebot.Context) error {
-	// Clear state
-	h.stateService.WithConversationState(c.Sender().ID, models.Default)
+	// Clear state, so a cancelled flow leaves nothing behind
+	if err := h.stateService.ClearState(c.Sender().ID); err != nil {
+		h.logger.Errorf("Failed to clear user state: %v", err)
+		return err
+	}
 
 	// Determine the message based on command
 	var message string
 	if c.Text() == commands.Start {
-		message = "Welcome! You are a trusted user."
+		message = h.welcomeMessage(permissions.Trusted)
 	} else {
-		message = "Main Menu"
+		message = h.t(i18n.TrustedMainMenu)
 	}
 
 	// Create and send keyboard
@@ -149,36 +185,55 @@ func (h *TrustedHandler) handleAddMember(c telebot.Context) error {
 
 	// Check account limit
 	accountCount := h.storageService.GetUserAccountCount(userID)
-	if accountCount >= 3 {
-		return c.Send("You can create maximum 3 accounts.")
+	if accountCount >= constants.MaxTrustedAccounts {
+		return c.Send(h.t(i18n.AccountLimitReached, constants.MaxTrustedAccounts))
 	}
 
 	// Get user's Telegram username
 	username := c.Sender().Username
 	if username == "" {
-		return c.Send("Error: You need to set a Telegram username first. Go to Telegram Settings -> Edit Profile -> Username")
+		return c.Send(h.t(i18n.TelegramUsernameRequired))
+	}
+
+	// Check there is somewhere to create the clients before promising an account
+	enabledInbounds, err := h.getEnabledInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get enabled inbounds: %v", err)
+		return h.sendTextMessage(c, h.enabledInboundsErrorMessage(err, permissions.Trusted), h.createMainKeyboard(permissions.Trusted))
 	}
 
 	// Generate auto username based on Telegram username and account count
 	autoUsername := fmt.Sprintf("%s-add%d", username, accountCount+1)
 
 	// Send loading message
-	loadingMsg := fmt.Sprintf("Creating account '%s'...", autoUsername)
+	loadingMsg := h.t(i18n.AccountCreating, autoUsername)
 	c.Send(loadingMsg)
 
-	// Create clients for all inbounds with infinite duration
+	// Accounts last TRUSTED_DEFAULT_DURATION_DAYS, or forever when it isn't set
+	durationStr := commands.Infinite
+	if days := h.config.TrustedDefaultDurationDays; days > 0 {
+		durationStr = strconv.Itoa(days)
+	}
+	expiryTime, err := calculateExpiryTime(durationStr, h.config.MaxDurationDays)
+	if err != nil {
+		h.logger.Errorf("Invalid trusted account duration: %v", err)
+		return c.Send(h.t(i18n.AccountCreateFailed) + err.Error())
+	}
+
+	// Create clients for all inbounds
 	params := TrustedClientCreationParams{
 		Username:    autoUsername,
-		ExpiryTime:  0, // Infinite duration
+		DurationStr: durationStr,
+		ExpiryTime:  expiryTime,
 		SenderID:    userID,
 		CommonSubId: generateSubID(autoUsername),
 	}
 
-	success, errors := h.createClientsForAllInbounds(params)
+	success, errors := h.createClientsForAllInbounds(&params, enabledInbounds)
 
 	// Store VPN account in our storage
 	if success {
-		if err := h.storageService.AddVpnAccount(autoUsername, "auto-generated", userID); err != nil {
+		if err := h.storageService.AddVpnAccount(autoUsername, params.Password, userID); err != nil {
 			h.logger.Errorf("Failed to store VPN account: %v", err)
 		}
 	}
@@ -187,7 +242,7 @@ func (h *TrustedHandler) handleAddMember(c telebot.Context) error {
 	if success {
 		h.sendSubscriptionInfo(c, params)
 	} else {
-		errorMsg := "Failed to create account:\n" + strings.Join(errors, "\n")
+		errorMsg := h.t(i18n.AccountCreateFailed) + strings.Join(errors, "\n")
 		c.Send(errorMsg)
 	}
 
@@ -201,11 +256,11 @@ func (h *TrustedHandler) handleDeleteMember(c telebot.Context) error {
 	accounts := h.storageService.GetUserAccounts(userID)
 
 	if len(accounts) == 0 {
-		return c.Send("You have no accounts to remove.")
+		return c.Send(h.t(i18n.AccountNoneToRemove))
 	}
 
 	keyboard := h.createRemoveAccountKeyboard(accounts)
-	return c.Send("Select account to remove:", &telebot.ReplyMarkup{InlineKeyboard: keyboard})
+	return c.Send(h.t(i18n.AccountSelectRemove), &telebot.ReplyMarkup{InlineKeyboard: keyboard})
 }
 
 // handleCallback handles callback queries
@@ -216,7 +271,19 @@ func (h *TrustedHandler) handleCallback(ctx context.Context, c telebot.Context)
 		return h.handleConfirmRemoveVpnAccount(ctx, c, data)
 	}
 
-	return c.Send("Unknown action.")
+	if strings.HasPrefix(data, myConfigPrefix) {
+		return h.handleMyConfigCallback(c, data)
+	}
+
+	if strings.HasPrefix(data, copyLinkPrefix) {
+		return h.handleCopyLinkCallback(c, data)
+	}
+
+	if data == RemindersOffData {
+		return h.handleRemindersOffCallback(c)
+	}
+
+	return c.Send(h.t(i18n.UnknownAction))
 }
 
 // handleConfirmRemoveVpnAccount handles showing confirmation for VPN account removal
@@ -225,7 +292,7 @@ func (h *TrustedHandler) handleConfirmRemoveVpnAccount(ctx context.Context, c te
 
 	accountID, err := parseRemoveVpnCallback(data)
 	if err != nil {
-		return c.Send("Invalid account selection.")
+		return c.Send(h.t(i18n.AccountInvalidSelection))
 	}
 
 	// Get the account details
@@ -239,7 +306,7 @@ func (h *TrustedHandler) handleConfirmRemoveVpnAccount(ctx context.Context, c te
 	}
 
 	if accountToDelete == nil {
-		return c.Send("Account not found.")
+		return c.Send(h.t(i18n.AccountNotFoundShort))
 	}
 
 	// Store account ID in state for confirmation
@@ -249,7 +316,7 @@ func (h *TrustedHandler) handleConfirmRemoveVpnAccount(ctx context.Context, c te
 
 	// Show confirmation keyboard
 	markup := h.createConfirmKeyboard()
-	return c.Send(fmt.Sprintf("🗑️ **Confirm Account Deletion**\n\n⚠️ You are about to permanently delete account **%s**\n\n**This action will:**\n• Remove account from all server configurations\n• Delete all associated data\n• Cannot be undone\n\nAre you absolutely sure?", accountToDelete.Username), &telebot.SendOptions{
+	return c.Send(h.t(i18n.AccountDeleteConfirm, accountToDelete.Username), &telebot.SendOptions{
 		ParseMode:   telebot.ModeMarkdown,
 		ReplyMarkup: markup,
 	})
@@ -267,19 +334,19 @@ func (h *TrustedHandler) processConfirmDeletion(c telebot.Context) error {
 
 	// Check if user confirmed
 	if h.getButtonCommand(confirmation) != commands.Confirm {
-		return c.Send("❌ **Invalid Selection**\n\nPlease click Confirm to proceed with deletion or use the Return button to cancel.")
+		return c.Send(h.t(i18n.AccountDeleteInvalidSelection))
 	}
 
 	// Get account ID from state
 	userState, err := h.stateService.GetState(userID)
 	if err != nil || userState.Payload == nil {
-		return c.Send("❌ **Session Error**\n\nAccount data was lost. Please start the deletion process again.")
+		return c.Send(h.t(i18n.SessionAccountLost))
 	}
 
 	accountIDStr := *userState.Payload
 	accountID, err := strconv.Atoi(accountIDStr)
 	if err != nil {
-		return c.Send("❌ **Invalid Account ID**\n\nPlease start the deletion process again.")
+		return c.Send(h.t(i18n.AccountInvalidID))
 	}
 
 	// Get the account details before deletion
@@ -293,34 +360,54 @@ func (h *TrustedHandler) processConfirmDeletion(c telebot.Context) error {
 	}
 
 	if accountToDelete == nil {
-		return c.Send("❌ **Account Not Found**\n\nThe account may have already been deleted.")
+		return c.Send(h.t(i18n.AccountNotFound))
 	}
 
 	// Send loading message
-	loadingMsg := fmt.Sprintf("⏳ **Deleting Account...**\n\nRemoving account '%s' from all server configurations. Please wait...", accountToDelete.Username)
+	loadingMsg := h.t(i18n.AccountDeleteInProgress, accountToDelete.Username)
 	c.Send(loadingMsg)
 
 	// First, remove clients from X-Ray server (like admin does)
+	log := h.logger.WithFields(logrus.Fields{
+		"operation":  "delete_account",
+		"user_id":    userID,
+		"account_id": accountID,
+		"username":   accountToDelete.Username,
+	})
+
 	ctx := context.Background()
 	err = h.xrayService.RemoveClients(ctx, []string{accountToDelete.Username})
-	if err != nil {
-		h.logger.Errorf("Failed to remove clients from X-Ray server: %v", err)
+
+	// An account deleted in X-UI directly only has to be dropped from storage
+	alreadyGone := errors.Is(err, xrayclient.ErrClientNotFound)
+	if alreadyGone {
+		log.Warn("Account no longer exists on the X-Ray server")
+	} else if err != nil {
+		log.WithError(err).Error("Failed to remove clients from X-Ray server")
 		// Clear state and return to main menu
 		h.stateService.WithConversationState(userID, models.Default)
-		return c.Send(fmt.Sprintf("❌ **Deletion Failed**\n\nCouldn't delete account '%s' from server configurations.\n\n**Error:** %v\n\nPlease try again or contact administrator.", accountToDelete.Username, err))
+		return c.Send(h.t(i18n.AccountDeleteFailed, accountToDelete.Username, h.panelErrorText(err)))
 	}
 
 	// Then remove from our database
 	if err := h.storageService.RemoveVpnAccount(accountID, userID); err != nil {
-		h.logger.Errorf("Failed to remove VPN account from storage: %v", err)
+		log.WithError(err).Error("Failed to remove VPN account from storage")
 		// Clear state and return to main menu
 		h.stateService.WithConversationState(userID, models.Default)
-		return c.Send(fmt.Sprintf("⚠️ **Partial Success**\n\nAccount deleted from server but failed to update database:\n%v", err))
+		return c.Send(h.t(i18n.AccountDeletePartial, err))
+	}
+
+	log.Info("Deleted VPN account")
+	if err := h.storageService.RemoveMemberData(accountToDelete.Username); err != nil {
+		log.WithError(err).Error("Failed to remove notes and tags from storage")
 	}
 
 	// Clear state and return to main menu
 	h.stateService.WithConversationState(userID, models.Default)
-	return c.Send(fmt.Sprintf("✅ **Account Deleted Successfully**\n\n🗑️ Account '%s' has been permanently removed from all server configurations.", accountToDelete.Username))
+	if alreadyGone {
+		return c.Send(h.t(i18n.AccountDeleteGone, accountToDelete.Username))
+	}
+	return c.Send(h.t(i18n.AccountDeleteDone, accountToDelete.Username))
 }
 
 // createRemoveAccountKeyboard creates keyboard for removing accounts
@@ -353,9 +440,11 @@ func parseRemoveVpnCallback(data string) (int, error) {
 // TrustedClientCreationParams holds parameters for client creation
 type TrustedClientCreationParams struct {
 	Username    string
+	DurationStr string // days, or commands.Infinite
 	ExpiryTime  int64
 	SenderID    int64
 	CommonSubId string
+	Password    string // set by createClientsForAllInbounds when an inbound needs one
 }
 
 // generateSubID generates a subscription ID for the user
@@ -363,41 +452,35 @@ func generateSubID(username string) string {
 	return models.GenerateSubID()
 }
 
-// createClientsForAllInbounds creates clients for all enabled inbounds (simplified version)
-func (h *TrustedHandler) createClientsForAllInbounds(params TrustedClientCreationParams) (bool, []string) {
+// createClientsForAllInbounds creates clients for all enabled inbounds (simplified version),
+// generating params.Password if any of them authenticates with a password
+func (h *TrustedHandler) createClientsForAllInbounds(params *TrustedClientCreationParams, enabledInbounds []models.Inbound) (bool, []string) {
 	ctx := context.Background()
 
-	// Get enabled inbounds
-	inbounds, err := h.xrayService.GetInbounds(ctx)
+	password, err := models.NewMemberPassword(enabledInbounds)
 	if err != nil {
-		h.logger.Errorf("Failed to get inbounds: %v", err)
-		return false, []string{"Failed to get server configuration"}
-	}
-
-	// Filter enabled inbounds
-	var enabledInbounds []models.Inbound
-	for _, inbound := range inbounds {
-		if inbound.Enable {
-			enabledInbounds = append(enabledInbounds, inbound)
-		}
-	}
-
-	if len(enabledInbounds) == 0 {
-		return false, []string{"No enabled inbounds found"}
+		h.logger.Errorf("Failed to generate password: %v", err)
+		return false, []string{err.Error()}
 	}
+	params.Password = password
 
 	// Create client creation params using admin-compatible format
 	adminParams := ClientCreationParams{
 		BaseUsername:    params.Username,
-		DurationStr:     "∞",
+		DurationStr:     params.DurationStr,
 		ExpiryTime:      params.ExpiryTime,
 		CommonSubId:     params.CommonSubId,
 		BaseFingerprint: fmt.Sprintf("%x", time.Now().UnixNano()),
 		SenderID:        params.SenderID,
+		Password:        params.Password,
 	}
 
 	// Create clients using admin logic
-	createdEmails, addErrors, success := h.createClientsForAllInboundsAdmin(ctx, adminParams, enabledInbounds)
+	createdEmails, addErrors, _ := h.createClientsForAllInboundsAdmin(ctx, adminParams, enabledInbounds)
+
+	createdEmails, verifyErrors := h.verifyCreatedClients(ctx, createdEmails)
+	addErrors = append(addErrors, verifyErrors...)
+	success := len(createdEmails) > 0
 
 	h.logger.Infof("Created %d clients for user %s", len(createdEmails), params.Username)
 	return success, addErrors
@@ -413,8 +496,22 @@ func (h *TrustedHandler) createClientsForAllInboundsAdmin(ctx context.Context, p
 		email := helpers.FormatEmailWithInboundNumber(params.BaseUsername, i+1)
 		fingerprint := fmt.Sprintf("%s-%d", params.BaseFingerprint, i+1)
 
+		log := h.logger.WithFields(logrus.Fields{
+			"operation":  "create_client",
+			"user_id":    params.SenderID,
+			"inbound_id": inbound.ID,
+			"email":      email,
+		})
+
+		clientID, err := models.GenerateUUID()
+		if err != nil {
+			log.WithError(err).Error("Failed to generate client ID")
+			addErrors = append(addErrors, h.t(i18n.PanelErrInbound, inbound.ID, h.panelErrorText(err)))
+			continue
+		}
+
 		client := models.Client{
-			ID:          email,
+			ID:          clientID,
 			Enable:      true,
 			Email:       email,
 			TotalGB:     0, // Unlimited traffic
@@ -423,13 +520,14 @@ func (h *TrustedHandler) createClientsForAllInboundsAdmin(ctx context.Context, p
 			TgID:        fmt.Sprintf("%d", params.SenderID),
 			SubID:       params.CommonSubId,
 			Fingerprint: fingerprint,
+			Password:    inbound.ClientPassword(params.Password),
 		}
 
 		if err := h.xrayService.AddClient(ctx, inbound.ID, client); err != nil {
-			h.logger.Errorf("Failed to add client to inbound %d: %v", inbound.ID, err)
-			addErrors = append(addErrors, fmt.Sprintf("Inbound %d: %v", inbound.ID, err))
+			log.WithError(err).Error("Failed to add client to inbound")
+			addErrors = append(addErrors, h.t(i18n.PanelErrInbound, inbound.ID, h.panelErrorText(err)))
 		} else {
-			h.logger.Infof("Successfully added client %s to inbound %d", email, inbound.ID)
+			log.Info("Successfully added client to inbound")
 			createdEmails = append(createdEmails, email)
 			addedToAny = true
 		}
@@ -443,7 +541,7 @@ func (h *TrustedHandler) sendSubscriptionInfo(c telebot.Context, params TrustedC
 	// Create admin-compatible params
 	adminParams := ClientCreationParams{
 		BaseUsername:    params.Username,
-		DurationStr:     "∞",
+		DurationStr:     params.DurationStr,
 		ExpiryTime:      params.ExpiryTime,
 		CommonSubId:     params.CommonSubId,
 		BaseFingerprint: fmt.Sprintf("%x", time.Now().UnixNano()),
@@ -452,28 +550,30 @@ func (h *TrustedHandler) sendSubscriptionInfo(c telebot.Context, params TrustedC
 
 	// Get created emails (we need this for the helper function)
 	ctx := context.Background()
-	inbounds, err := h.xrayService.GetInbounds(ctx)
+	enabledInbounds, err := h.getEnabledInbounds(ctx)
 	if err != nil {
 		return err
 	}
 
 	var createdEmails []string
-	var enabledCount int
-	for _, inbound := range inbounds {
-		if inbound.Enable {
-			enabledCount++
-			email := helpers.FormatEmailWithInboundNumber(params.Username, enabledCount)
-			createdEmails = append(createdEmails, email)
-		}
+	for i := range enabledInbounds {
+		createdEmails = append(createdEmails, helpers.FormatEmailWithInboundNumber(params.Username, i+1))
 	}
 
+	return h.sendAccountInfo(c, adminParams, createdEmails, params.Password)
+}
+
+// sendAccountInfo sends the subscription info and QR code of an account, as shown after
+// creating it
+func (h *TrustedHandler) sendAccountInfo(c telebot.Context, params ClientCreationParams, emails []string, password string) error {
 	// Use admin helper to format subscription info
 	subscriptionInfo := helpers.FormatSubscriptionInfo(
-		adminParams.BaseUsername,
-		adminParams.DurationStr,
-		adminParams.ExpiryTime,
-		createdEmails,
-		adminParams.CommonSubId,
+		params.BaseUsername,
+		params.DurationStr,
+		params.ExpiryTime,
+		emails,
+		params.CommonSubId,
+		password,
 		[]string{}, // No errors for successful creation
 		h.config.Server.SubURLPrefix,
 	)
@@ -483,11 +583,10 @@ func (h *TrustedHandler) sendSubscriptionInfo(c telebot.Context, params TrustedC
 	}
 
 	// Send QR code with correct URL format (same as admin)
-	if len(createdEmails) > 0 {
-		subURL := fmt.Sprintf("%s%s?name=%s", h.config.Server.SubURLPrefix, params.CommonSubId, params.CommonSubId)
-		if err := h.sendTextMessage(c, "QR code for subscription:", nil); err != nil {
+	if len(emails) > 0 {
+		if err := h.sendTextMessage(c, h.t(i18n.SubscriptionQRCaption), nil); err != nil {
 			h.logger.Errorf("Failed to send QR code message: %v", err)
-		} else if err := h.sendQRCode(c, subURL); err != nil {
+		} else if err := h.sendSubscriptionQR(c, params.CommonSubId, h.qrCaption(params.BaseUsername)); err != nil {
 			h.logger.Errorf("Failed to send QR code: %v", err)
 		}
 	}
@@ -503,10 +602,10 @@ func (h *TrustedHandler) createConfirmKeyboard() *telebot.ReplyMarkup {
 
 	markup.Reply(
 		telebot.Row{
-			telebot.Btn{Text: "✅ " + commands.Confirm},
+			telebot.Btn{Text: "✅ " + commands.Label(commands.Confirm)},
 		},
 		telebot.Row{
-			telebot.Btn{Text: "↩️ " + commands.ReturnToMainMenu},
+			telebot.Btn{Text: "↩️ " + commands.Label(commands.ReturnToMainMenu)},
 		},
 	)
 
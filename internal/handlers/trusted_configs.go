@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// myConfigPrefix is the callback data prefix for re-sending one of the user's accounts
+const myConfigPrefix = "my_config_"
+
+// handleMyConfigs lists the user's accounts so a lost subscription link can be sent again
+func (h *TrustedHandler) handleMyConfigs(c telebot.Context) error {
+	accounts := h.storageService.GetUserAccounts(c.Sender().ID)
+	if len(accounts) == 0 {
+		return c.Send(h.t(i18n.AccountNoneToShow))
+	}
+
+	var keyboard [][]telebot.InlineButton
+	for _, account := range accounts {
+		keyboard = append(keyboard, []telebot.InlineButton{{
+			Text: fmt.Sprintf("📋 %s", account.Username),
+			Data: myConfigPrefix + strconv.Itoa(account.ID),
+		}})
+	}
+
+	return c.Send(h.t(i18n.AccountSelectShow), &telebot.ReplyMarkup{InlineKeyboard: keyboard})
+}
+
+// handleMyConfigCallback re-sends the subscription info and QR code of the picked account,
+// read back from the panel
+func (h *TrustedHandler) handleMyConfigCallback(c telebot.Context, data string) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	accountID, err := strconv.Atoi(strings.TrimPrefix(data, myConfigPrefix))
+	if err != nil {
+		return c.Send(h.t(i18n.AccountInvalidSelection))
+	}
+
+	// Only the user's own accounts can be picked
+	var account *models.VpnAccount
+	for _, candidate := range h.storageService.GetUserAccounts(c.Sender().ID) {
+		if candidate.ID == accountID {
+			account = &candidate
+			break
+		}
+	}
+	if account == nil {
+		return c.Send(h.t(i18n.AccountNotFoundShort))
+	}
+
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.panelErrorMessage(err), h.createMainKeyboard(permissions.Trusted))
+	}
+
+	subID := primarySubID(h.collectSubIDs(inbounds, account.Username))
+	emails := h.accountEmails(inbounds, account.Username)
+	usage := helpers.AccountUsage(inbounds, account.Username)
+	// No client left at all means the account was deleted in X-UI directly
+	if len(emails) == 0 && usage == nil {
+		return h.sendTextMessage(c, h.t(i18n.AccountGone, html.EscapeString(account.Username)), h.createMainKeyboard(permissions.Trusted))
+	}
+	if subID == "" || len(emails) == 0 || usage == nil {
+		return h.sendTextMessage(c, h.t(i18n.AccountNotOnServer, html.EscapeString(account.Username)), h.createMainKeyboard(permissions.Trusted))
+	}
+
+	limit, _ := memberTrafficLimit(inbounds, account.Username)
+	if err := h.sendTextMessage(c, h.formatAccountResend(account, usage, limit, emails, subID), nil); err != nil {
+		return err
+	}
+
+	if err := h.sendTextMessage(c, h.t(i18n.SubscriptionQRCaption), nil); err != nil {
+		h.logger.Errorf("Failed to send QR code message: %v", err)
+	} else if err := h.sendSubscriptionQR(c, subID, h.qrCaption(account.Username)); err != nil {
+		h.logger.Errorf("Failed to send QR code: %v", err)
+	}
+	return nil
+}
+
+// formatAccountResend describes an existing account as the panel has it now: its expiry,
+// traffic against its limit, password and clients, with the link to connect
+func (h *TrustedHandler) formatAccountResend(account *models.VpnAccount, usage *models.MemberInfo, limit int64, emails []string, subID string) string {
+	expiry := usage.GetExpiryStatus(h.localizer)
+	if usage.ExpiryTime != 0 {
+		expiry = fmt.Sprintf("%s (%s)", time.UnixMilli(usage.ExpiryTime).Format(constants.DateFormat), expiry)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(h.t(i18n.AccountResendHeader,
+		html.EscapeString(account.Username),
+		expiry,
+		float64(usage.TotalTraffic)/constants.BytesInGB,
+		h.formatTrafficLimit(limit)))
+	if account.Password != "" {
+		sb.WriteString(h.t(i18n.AccountResendPassword, html.EscapeString(account.Password)))
+	}
+
+	clients := make([]string, 0, len(emails))
+	for _, email := range emails {
+		clients = append(clients, "- "+html.EscapeString(email))
+	}
+	sb.WriteString(h.t(i18n.AccountResendClients, strings.Join(clients, "\n"), html.EscapeString(h.subscriptionURL(subID))))
+	return sb.String()
+}
+
+// accountEmails returns the emails of the account's clients across the inbounds
+func (h *TrustedHandler) accountEmails(inbounds []models.Inbound, username string) []string {
+	var emails []string
+	for _, inbound := range inbounds {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			h.logger.Warnf("Failed to parse inbound settings: %v", err)
+			continue
+		}
+		for _, client := range clients {
+			if helpers.IsEmailMatchingBaseUsername(client.Email, username) {
+				emails = append(emails, client.Email)
+			}
+		}
+	}
+	return emails
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/models"
+)
+
+func TestFormatAccountResend(t *testing.T) {
+	admin := newTestAdminHandler(t, "http://127.0.0.1:1")
+	admin.config.Server.SubURLPrefix = "https://sub.example.com/"
+	h := NewTrustedHandler(&admin.BaseHandler, admin.storageService)
+
+	expiry := time.Now().Add(10 * 24 * time.Hour)
+	account := &models.VpnAccount{Username: "alice", Password: "secret"}
+	usage := &models.MemberInfo{BaseUsername: "alice", ExpiryTime: expiry.UnixMilli(), TotalTraffic: 2 * constants.BytesInGB}
+
+	got := h.formatAccountResend(account, usage, 5*constants.BytesInGB, []string{"alice-1", "alice-2"}, "sub123")
+
+	for _, want := range []string{
+		"alice",
+		expiry.Format(constants.DateFormat),
+		"2.00 GB used, limit 5.00 GB",
+		"<code>secret</code>",
+		"- alice-1\n- alice-2",
+		"https://sub.example.com/sub123?name=sub123",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message doesn't contain %q:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"added successfully", "Unlimited"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("message contains %q:\n%s", unwanted, got)
+		}
+	}
+
+	// Without a limit, expiry or password the message says so instead
+	got = h.formatAccountResend(&models.VpnAccount{Username: "bob"}, &models.MemberInfo{BaseUsername: "bob"}, 0, []string{"bob-1"}, "sub456")
+	for _, want := range []string{"limit unlimited", "∞"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message doesn't contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Password") {
+		t.Errorf("message shows a password for an account without one:\n%s", got)
+	}
+}
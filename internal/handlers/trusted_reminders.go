@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/i18n"
+)
+
+// RemindersOffData is the callback data of the button on expiry reminders that opts the
+// user out of further ones
+const RemindersOffData = "reminders_off"
+
+// handleReminders turns the user's expiry reminders on or off
+func (h *TrustedHandler) handleReminders(c telebot.Context) error {
+	if h.config.ExpiryReminderDays == 0 {
+		return c.Send(h.t(i18n.RemindersUnavailable))
+	}
+
+	enabled := !h.storageService.RemindersEnabled(c.Sender().ID)
+	return h.setReminders(c, enabled)
+}
+
+// handleRemindersOffCallback opts the user out from the button on a reminder
+func (h *TrustedHandler) handleRemindersOffCallback(c telebot.Context) error {
+	if err := c.Respond(); err != nil {
+		h.logger.Errorf("Failed to answer callback: %v", err)
+	}
+
+	// Drop the button so the reminder can't be tapped again
+	if c.Message() != nil {
+		if _, err := c.Bot().EditReplyMarkup(c.Message(), nil); err != nil {
+			h.logger.Errorf("Failed to remove reminder keyboard: %v", err)
+		}
+	}
+
+	return h.setReminders(c, false)
+}
+
+// setReminders saves the user's reminder choice and confirms it
+func (h *TrustedHandler) setReminders(c telebot.Context, enabled bool) error {
+	if err := h.storageService.SetRemindersEnabled(c.Sender().ID, enabled); err != nil {
+		h.logger.Errorf("Failed to save reminder setting: %v", err)
+		return c.Send(h.t(i18n.RemindersSaveFailed))
+	}
+
+	if enabled {
+		return c.Send(h.t(i18n.RemindersOn, h.config.ExpiryReminderDays))
+	}
+	return c.Send(h.t(i18n.RemindersOff))
+}
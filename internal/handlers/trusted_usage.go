@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
+)
+
+// handleMyUsage shows the traffic and expiry of the accounts the user created, and no others
+func (h *TrustedHandler) handleMyUsage(c telebot.Context) error {
+	accounts := h.storageService.GetUserAccounts(c.Sender().ID)
+	if len(accounts) == 0 {
+		return c.Send(h.t(i18n.AccountNoneToShow))
+	}
+
+	inbounds, err := h.xrayService.GetInbounds(context.Background())
+	if err != nil {
+		h.logger.Errorf("Failed to get inbounds: %v", err)
+		return h.sendTextMessage(c, h.panelErrorMessage(err), h.createMainKeyboard(permissions.Trusted))
+	}
+
+	usages := make([]*models.MemberInfo, len(accounts))
+	var largest int64
+	for i, account := range accounts {
+		usages[i] = helpers.AccountUsage(inbounds, account.Username)
+		if usages[i] != nil {
+			largest = max(largest, usages[i].TotalDown, usages[i].TotalUp)
+		}
+	}
+	unit := helpers.ResolveTrafficUnit(helpers.ParseTrafficUnit(h.config.TrafficUnit), largest)
+
+	var sb strings.Builder
+	sb.WriteString(h.t(i18n.MyUsageHeader))
+	for i, account := range accounts {
+		username := html.EscapeString(account.Username)
+		usage := usages[i]
+		if usage == nil {
+			sb.WriteString(h.t(i18n.MyUsageMissing, username))
+			continue
+		}
+		sb.WriteString(h.t(i18n.MyUsageLine,
+			username,
+			unit.Value(usage.TotalDown), unit.Name,
+			unit.Value(usage.TotalUp), unit.Name,
+			usage.GetExpiryStatus(h.localizer)))
+	}
+
+	return h.sendLongMessage(c, sb.String(), h.createMainKeyboard(permissions.Trusted))
+}
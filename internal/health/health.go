@@ -0,0 +1,59 @@
+// Package health serves liveness and readiness probes for container orchestrators.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"xui-tg-admin/internal/constants"
+)
+
+// Checker answers readiness probes from a cached panel check so frequent probes
+// don't hammer the panel
+type Checker struct {
+	check     func(ctx context.Context) error
+	mu        sync.Mutex
+	checkedAt time.Time
+	lastErr   error
+}
+
+// NewChecker creates a checker that calls check to see whether the panel is reachable
+func NewChecker(check func(ctx context.Context) error) *Checker {
+	return &Checker{check: check}
+}
+
+// Ready returns the result of the last panel check, running a new one once it's older
+// than ReadinessCacheTTL. Concurrent probes wait for the same check.
+func (h *Checker) Ready(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.checkedAt.IsZero() && time.Since(h.checkedAt) < constants.ReadinessCacheTTL*time.Second {
+		return h.lastErr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, constants.ReadinessTimeout*time.Second)
+	defer cancel()
+
+	h.lastErr = h.check(ctx)
+	h.checkedAt = time.Now()
+	return h.lastErr
+}
+
+// Register adds /healthz, which reports the process is alive, and /readyz, which reports
+// whether the panel is reachable, to mux
+func (h *Checker) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := h.Ready(r.Context()); err != nil {
+			http.Error(w, "panel unreachable: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+}
@@ -0,0 +1,115 @@
+package helpers
+
+import "xui-tg-admin/internal/models"
+
+// FilterInboundsByUsers returns copies of the inbounds keeping only the client stats of the given base usernames
+func FilterInboundsByUsers(inbounds []models.Inbound, usernames map[string]bool) []models.Inbound {
+	filtered := make([]models.Inbound, 0, len(inbounds))
+	for _, inbound := range inbounds {
+		var stats []models.ClientStat
+		for _, clientStat := range inbound.ClientStats {
+			if usernames[ExtractBaseUsername(clientStat.Email)] {
+				stats = append(stats, clientStat)
+			}
+		}
+		inbound.ClientStats = stats
+		filtered = append(filtered, inbound)
+	}
+	return filtered
+}
+
+// FilterActiveInbounds returns copies of the inbounds keeping only enabled, unexpired client stats
+func FilterActiveInbounds(inbounds []models.Inbound) []models.Inbound {
+	filtered := make([]models.Inbound, 0, len(inbounds))
+	for _, inbound := range inbounds {
+		var stats []models.ClientStat
+		for _, clientStat := range inbound.ClientStats {
+			if clientStat.IsActive() {
+				stats = append(stats, clientStat)
+			}
+		}
+		inbound.ClientStats = stats
+		filtered = append(filtered, inbound)
+	}
+	return filtered
+}
+
+// GroupClientsByCreator maps the TgId stored on each client to the clients carrying it
+// across all inbounds. The bot fills TgId with the Telegram ID of whoever created the
+// client, the admin or trusted user, not the member it is for; only clients whose TgId was
+// set to the member's ID in the panel group under the member. Only the parsed inbound
+// settings store the TgId, client stats don't.
+func GroupClientsByCreator(inbounds []models.Inbound) map[string][]models.InboundClientRef {
+	clients := make(map[string][]models.InboundClientRef)
+
+	for _, inbound := range inbounds {
+		inboundClients, err := ParseInboundClients(inbound)
+		if err != nil {
+			continue
+		}
+
+		for _, client := range inboundClients {
+			if client.TgID == "" {
+				continue
+			}
+			clients[client.TgID] = append(clients[client.TgID], models.InboundClientRef{
+				InboundID: inbound.ID,
+				Client:    client,
+			})
+		}
+	}
+
+	return clients
+}
+
+// MissingClients returns the emails that appear neither in the inbounds' settings nor in
+// their client stats
+func MissingClients(inbounds []models.Inbound, emails []string) []string {
+	present := make(map[string]bool)
+	for _, inbound := range inbounds {
+		for _, clientStat := range inbound.ClientStats {
+			present[clientStat.Email] = true
+		}
+
+		clients, err := ParseInboundClients(inbound)
+		if err != nil {
+			continue
+		}
+		for _, client := range clients {
+			present[client.Email] = true
+		}
+	}
+
+	var missing []string
+	for _, email := range emails {
+		if !present[email] {
+			missing = append(missing, email)
+		}
+	}
+	return missing
+}
+
+// AccountUsage sums the traffic of the account's clients across the inbounds, or returns nil
+// if the panel has none. The account expires with its last expiring client.
+func AccountUsage(inbounds []models.Inbound, username string) *models.MemberInfo {
+	var usage *models.MemberInfo
+	for _, inbound := range inbounds {
+		for _, stat := range inbound.ClientStats {
+			if !IsEmailMatchingBaseUsername(stat.Email, username) {
+				continue
+			}
+			if usage == nil {
+				usage = &models.MemberInfo{BaseUsername: username, ExpiryTime: stat.ExpiryTime}
+			}
+			usage.TotalUp += stat.Up
+			usage.TotalDown += stat.Down
+			if stat.ExpiryTime == 0 || (usage.ExpiryTime != 0 && stat.ExpiryTime > usage.ExpiryTime) {
+				usage.ExpiryTime = stat.ExpiryTime
+			}
+		}
+	}
+	if usage != nil {
+		usage.TotalTraffic = usage.TotalUp + usage.TotalDown
+	}
+	return usage
+}
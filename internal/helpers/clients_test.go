@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"testing"
+
+	"xui-tg-admin/internal/models"
+)
+
+func TestGroupClientsByCreator(t *testing.T) {
+	inbounds := []models.Inbound{
+		{ID: 1, Settings: `{"clients":[{"email":"alice-1","tgId":"42"},{"email":"bob-1","tgId":"42"},{"email":"carol-1"}]}`},
+		{ID: 2, Settings: `{"clients":[{"email":"alice-2","tgId":"42"},{"email":"dave-2","tgId":"7"}]}`},
+		{ID: 3, Settings: `{"clients":[{"email":`},
+	}
+
+	grouped := GroupClientsByCreator(inbounds)
+
+	if len(grouped) != 2 {
+		t.Fatalf("got %d creators, want 2: %v", len(grouped), grouped)
+	}
+
+	want := []models.InboundClientRef{
+		{InboundID: 1, Client: models.InboundClient{Email: "alice-1"}},
+		{InboundID: 1, Client: models.InboundClient{Email: "bob-1"}},
+		{InboundID: 2, Client: models.InboundClient{Email: "alice-2"}},
+	}
+	got := grouped["42"]
+	if len(got) != len(want) {
+		t.Fatalf("creator 42 has %d clients, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].InboundID != want[i].InboundID || got[i].Client.Email != want[i].Client.Email {
+			t.Errorf("client %d = %d/%s, want %d/%s", i, got[i].InboundID, got[i].Client.Email, want[i].InboundID, want[i].Client.Email)
+		}
+	}
+
+	if refs := grouped["7"]; len(refs) != 1 || refs[0].Client.Email != "dave-2" {
+		t.Errorf("creator 7 = %v, want dave-2", refs)
+	}
+	if _, ok := grouped[""]; ok {
+		t.Error("clients without a TgId were grouped")
+	}
+}
@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"xui-tg-admin/internal/models"
+)
+
+// ErrUnsupportedProtocol is returned when no connection URI can be built for an inbound's protocol
+var ErrUnsupportedProtocol = errors.New("unsupported protocol")
+
+// BuildConnectionURI builds a client import URI (e.g. vless://) for a client of an inbound.
+// host is used when the inbound listens on all interfaces.
+func BuildConnectionURI(inbound models.Inbound, client models.InboundClient, host string) (string, error) {
+	switch inbound.Protocol {
+	case "vless":
+		return BuildVlessURI(inbound, client, host)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedProtocol, inbound.Protocol)
+	}
+}
+
+// BuildVlessURI builds a vless:// URI in the format generated by the X-UI panel
+func BuildVlessURI(inbound models.Inbound, client models.InboundClient, host string) (string, error) {
+	var stream models.StreamSettings
+	if inbound.StreamSettings != "" {
+		if err := json.Unmarshal([]byte(inbound.StreamSettings), &stream); err != nil {
+			return "", fmt.Errorf("failed to parse stream settings: %w", err)
+		}
+	}
+
+	network := stream.Network
+	if network == "" {
+		network = "tcp"
+	}
+
+	params := url.Values{}
+	params.Set("type", network)
+	params.Set("encryption", "none")
+
+	switch network {
+	case "ws":
+		params.Set("path", stream.WSSettings.Path)
+		if wsHost := stream.WSSettings.Host; wsHost != "" {
+			params.Set("host", wsHost)
+		} else if wsHost := stream.WSSettings.Headers["Host"]; wsHost != "" {
+			params.Set("host", wsHost)
+		}
+	case "grpc":
+		params.Set("serviceName", stream.GRPCSettings.ServiceName)
+	}
+
+	switch stream.Security {
+	case "tls":
+		params.Set("security", "tls")
+		setIfNotEmpty(params, "sni", stream.TLSSettings.ServerName)
+		setIfNotEmpty(params, "fp", stream.TLSSettings.Settings.Fingerprint)
+	case "reality":
+		reality := stream.RealitySettings
+		params.Set("security", "reality")
+		setIfNotEmpty(params, "pbk", reality.Settings.PublicKey)
+		setIfNotEmpty(params, "fp", reality.Settings.Fingerprint)
+		setIfNotEmpty(params, "spx", reality.Settings.SpiderX)
+		if len(reality.ServerNames) > 0 {
+			params.Set("sni", reality.ServerNames[0])
+		}
+		if len(reality.ShortIDs) > 0 {
+			params.Set("sid", reality.ShortIDs[0])
+		}
+	default:
+		params.Set("security", "none")
+	}
+
+	setIfNotEmpty(params, "flow", client.Flow)
+
+	uri := url.URL{
+		Scheme:   "vless",
+		User:     url.User(client.ID),
+		Host:     net.JoinHostPort(connectionHost(inbound.Listen, host), strconv.Itoa(inbound.Port)),
+		RawQuery: params.Encode(),
+		Fragment: fmt.Sprintf("%s-%s", inbound.Remark, client.Email),
+	}
+
+	return uri.String(), nil
+}
+
+// connectionHost returns the inbound listen address unless it listens on all interfaces
+func connectionHost(listen, fallback string) string {
+	switch listen {
+	case "", "0.0.0.0", "::", "[::]":
+		return fallback
+	default:
+		return listen
+	}
+}
+
+// setIfNotEmpty sets a query parameter only when the value is not empty
+func setIfNotEmpty(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
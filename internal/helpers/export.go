@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/models"
+)
+
+// FormatMembersCSV formats member usage information as CSV
+func FormatMembersCSV(members []models.MemberInfo) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := []string{"base_username", "emails", "up_gb", "down_gb", "total_gb", "enabled", "expiry_date"}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		expiry := "never"
+		if member.ExpiryTime > 0 {
+			expiry = time.Unix(member.ExpiryTime/1000, 0).Format(constants.DateFormat)
+		}
+
+		record := []string{
+			member.BaseUsername,
+			strings.Join(member.FullEmails, ";"),
+			fmt.Sprintf("%.2f", float64(member.TotalUp)/constants.BytesInGB),
+			fmt.Sprintf("%.2f", float64(member.TotalDown)/constants.BytesInGB),
+			fmt.Sprintf("%.2f", float64(member.TotalTraffic)/constants.BytesInGB),
+			fmt.Sprintf("%t", member.Enable),
+			expiry,
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ExportFile is a named file to put into an archive
+type ExportFile struct {
+	Name string
+	Data []byte
+}
+
+// ZipFiles packs the files into a zip archive in the given order
+func ZipFiles(files []ExportFile) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+
+	for _, file := range files {
+		w, err := writer.Create(file.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(file.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
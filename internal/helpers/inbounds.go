@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	"xui-tg-admin/internal/models"
+)
+
+// maxRemarkDisplayLength keeps the inbound table narrow enough for phone screens
+const maxRemarkDisplayLength = 14
+
+// FormatInboundsTable formats the inbounds' setup as a table: ID, remark, protocol, port,
+// listen address, whether the inbound is enabled and its number of clients
+func FormatInboundsTable(inbounds []models.Inbound) string {
+	var sb strings.Builder
+	sb.WriteString("<pre>\n")
+	sb.WriteString("ID  | Remark         | Protocol    | Port  | Listen    | On  | Clients\n")
+	sb.WriteString("----|----------------|-------------|-------|-----------|-----|--------\n")
+
+	for _, inbound := range inbounds {
+		listen := inbound.Listen
+		if listen == "" {
+			listen = "*"
+		}
+		enabled := "off"
+		if inbound.Enable {
+			enabled = "on"
+		}
+
+		sb.WriteString(html.EscapeString(fmt.Sprintf("%-3d | %-14s | %-11s | %-5d | %-9s | %-3s | %d\n",
+			inbound.ID, truncateRunes(inbound.Remark, maxRemarkDisplayLength), inbound.Protocol,
+			inbound.Port, listen, enabled, CountInboundClients(inbound))))
+	}
+
+	sb.WriteString("</pre>")
+	return sb.String()
+}
+
+// ParseInboundClients returns the clients configured in the inbound's settings. An inbound
+// without settings has no clients; settings that can't be parsed are reported as an error
+// naming the inbound, so callers can log it instead of taking the inbound for empty.
+func ParseInboundClients(inbound models.Inbound) ([]models.InboundClient, error) {
+	if inbound.Settings == "" {
+		return nil, nil
+	}
+
+	var settings models.InboundSettings
+	if err := json.Unmarshal([]byte(inbound.Settings), &settings); err != nil {
+		return nil, fmt.Errorf("inbound %d: %w", inbound.ID, err)
+	}
+	return settings.Clients, nil
+}
+
+// MalformedInbounds returns the inbounds whose settings can't be parsed. Their clients
+// are only known from the traffic stats.
+func MalformedInbounds(inbounds []models.Inbound) []models.Inbound {
+	var malformed []models.Inbound
+	for _, inbound := range inbounds {
+		if _, err := ParseInboundClients(inbound); err != nil {
+			malformed = append(malformed, inbound)
+		}
+	}
+	return malformed
+}
+
+// CountInboundClients returns the number of clients configured on an inbound, falling back
+// to the traffic stats when the settings can't be parsed
+func CountInboundClients(inbound models.Inbound) int {
+	clients, err := ParseInboundClients(inbound)
+	if err != nil {
+		return len(inbound.ClientStats)
+	}
+	return len(clients)
+}
+
+// truncateRunes shortens s to at most limit characters, marking the cut with an ellipsis
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"xui-tg-admin/internal/models"
+)
+
+func TestParseInboundClients(t *testing.T) {
+	tests := []struct {
+		name      string
+		inbound   models.Inbound
+		want      []string
+		wantErrID string
+	}{
+		{
+			name:    "empty settings",
+			inbound: models.Inbound{ID: 1},
+		},
+		{
+			name:    "no clients",
+			inbound: models.Inbound{ID: 2, Settings: `{"decryption":"none"}`},
+		},
+		{
+			name:    "valid",
+			inbound: models.Inbound{ID: 3, Settings: `{"clients":[{"id":"a","email":"alice-1"},{"id":"b","email":"bob-1"}]}`},
+			want:    []string{"alice-1", "bob-1"},
+		},
+		{
+			name:      "malformed",
+			inbound:   models.Inbound{ID: 42, Settings: `{"clients":[{"email":`},
+			wantErrID: "inbound 42",
+		},
+		{
+			name:      "wrong type",
+			inbound:   models.Inbound{ID: 7, Settings: `{"clients":"none"}`},
+			wantErrID: "inbound 7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients, err := ParseInboundClients(tt.inbound)
+			if tt.wantErrID != "" {
+				if err == nil {
+					t.Fatalf("ParseInboundClients returned %v, want an error", clients)
+				}
+				if !strings.Contains(err.Error(), tt.wantErrID) {
+					t.Errorf("error %q doesn't name %q", err, tt.wantErrID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseInboundClients returned error: %v", err)
+			}
+
+			var emails []string
+			for _, client := range clients {
+				emails = append(emails, client.Email)
+			}
+			if strings.Join(emails, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("clients = %v, want %v", emails, tt.want)
+			}
+		})
+	}
+}
+
+func TestMalformedInboundsAndCount(t *testing.T) {
+	valid := models.Inbound{ID: 1, Settings: `{"clients":[{"email":"alice-1"}]}`}
+	malformed := models.Inbound{
+		ID:          2,
+		Settings:    `not json`,
+		ClientStats: []models.ClientStat{{Email: "alice-2"}, {Email: "bob-2"}},
+	}
+
+	got := MalformedInbounds([]models.Inbound{valid, malformed})
+	if len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("MalformedInbounds = %v, want only inbound 2", got)
+	}
+
+	if n := CountInboundClients(valid); n != 1 {
+		t.Errorf("CountInboundClients(valid) = %d, want 1", n)
+	}
+	// Malformed settings fall back to the traffic stats
+	if n := CountInboundClients(malformed); n != 2 {
+		t.Errorf("CountInboundClients(malformed) = %d, want 2", n)
+	}
+}
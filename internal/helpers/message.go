@@ -0,0 +1,136 @@
+package helpers
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	preOpenTag  = "<pre>"
+	preCloseTag = "</pre>"
+)
+
+// SplitMessage splits an HTML message into chunks of at most limit bytes, breaking only
+// between lines. A <pre> block cut by a chunk boundary is closed and reopened so every
+// chunk stays valid HTML. Bytes overcount what Telegram measures, so chunks always fit.
+func SplitMessage(text string, limit int) []string {
+	if len(text) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	inPre := false
+
+	flush := func() {
+		if current.Len() == 0 || (inPre && current.String() == preOpenTag) {
+			return
+		}
+		if inPre {
+			current.WriteString(preCloseTag)
+		}
+		chunks = append(chunks, current.String())
+		current.Reset()
+		if inPre {
+			current.WriteString(preOpenTag)
+		}
+	}
+
+	// Leave room to reopen and close a <pre> block around any line
+	maxLine := limit - len(preOpenTag) - len(preCloseTag)
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for _, piece := range splitLongLine(line, maxLine) {
+			if current.Len()+len(piece)+len(preCloseTag) > limit {
+				flush()
+			}
+			current.WriteString(piece)
+
+			// The last tag on the line decides whether a <pre> block is still open
+			if open, closed := strings.LastIndex(piece, preOpenTag), strings.LastIndex(piece, preCloseTag); open > closed {
+				inPre = true
+			} else if closed > open {
+				inPre = false
+			}
+		}
+	}
+
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+
+	return chunks
+}
+
+// splitLongLine cuts a line longer than limit bytes into pieces on rune boundaries, never
+// inside an HTML tag or entity
+func splitLongLine(line string, limit int) []string {
+	if len(line) <= limit || limit <= 0 {
+		return []string{line}
+	}
+
+	var pieces []string
+	for len(line) > limit {
+		cut := safeCut(line, limit)
+		pieces = append(pieces, line[:cut])
+		line = line[cut:]
+	}
+	return append(pieces, line)
+}
+
+// safeCut returns the last position within limit bytes where line can be cut outside any
+// tag or entity. A line with no such position, such as one long tag, is cut on a rune
+// boundary instead.
+func safeCut(line string, limit int) int {
+	best := 0
+	inTag := false
+	entityEnd := 0
+
+	for i := 0; i <= limit && i < len(line); i++ {
+		if i > 0 && !inTag && i >= entityEnd && utf8.RuneStart(line[i]) {
+			best = i
+		}
+		switch line[i] {
+		case '<':
+			inTag = true
+		case '>':
+			inTag = false
+		case '&':
+			if !inTag {
+				entityEnd = i + entityLength(line[i:])
+			}
+		}
+	}
+	if best > 0 {
+		return best
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(line[cut]) {
+		cut--
+	}
+	return cut
+}
+
+// maxEntityLength is the longest entity looked for, e.g. "&#x1F600;"
+const maxEntityLength = 10
+
+// entityLength returns the length of the entity s starts with, such as "&amp;" or "&#39;",
+// or 1 if the ampersand doesn't start one
+func entityLength(s string) int {
+	for i := 1; i < len(s) && i < maxEntityLength; i++ {
+		c := s[i]
+		switch {
+		case c == ';':
+			if i > 1 {
+				return i + 1
+			}
+			return 1
+		case c == '#' && i == 1,
+			c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		default:
+			return 1
+		}
+	}
+	return 1
+}
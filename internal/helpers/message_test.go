@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitMessageShort(t *testing.T) {
+	chunks := SplitMessage("hello", 4096)
+	if len(chunks) != 1 || chunks[0] != "hello" {
+		t.Errorf("SplitMessage = %q, want the text unchanged", chunks)
+	}
+}
+
+func TestSplitMessageManyUsers(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("📊 <b>Users Report</b>\n\n<pre>\n")
+	for i := 0; i < 400; i++ {
+		sb.WriteString(fmt.Sprintf("user_%03d &amp; friends | %6.2f GB | active\n", i, float64(i)/3))
+	}
+	sb.WriteString("</pre>\n<b>Total:</b> 400 users")
+	text := sb.String()
+
+	const limit = 4096
+	chunks := SplitMessage(text, limit)
+	if len(chunks) < 2 {
+		t.Fatalf("SplitMessage returned %d chunk(s) for %d bytes, want several", len(chunks), len(text))
+	}
+
+	var joined strings.Builder
+	for i, chunk := range chunks {
+		if len(chunk) > limit {
+			t.Errorf("chunk %d is %d bytes, over the %d limit", i, len(chunk), limit)
+		}
+		if strings.Count(chunk, preOpenTag) != strings.Count(chunk, preCloseTag) {
+			t.Errorf("chunk %d has unbalanced <pre> tags", i)
+		}
+		joined.WriteString(chunk)
+	}
+
+	// Apart from the <pre> tags added at the boundaries, nothing is lost or changed
+	strip := func(s string) string {
+		return strings.NewReplacer(preOpenTag, "", preCloseTag, "").Replace(s)
+	}
+	if strip(joined.String()) != strip(text) {
+		t.Errorf("joined chunks differ from the original text")
+	}
+}
+
+func TestSplitLongLineKeepsTagsAndEntities(t *testing.T) {
+	line := strings.Repeat("<b>bold</b> &amp; &#39;x&#39; ", 20)
+
+	for limit := 12; limit <= 40; limit++ {
+		pieces := splitLongLine(line, limit)
+		if strings.Join(pieces, "") != line {
+			t.Fatalf("limit %d: pieces don't add up to the line", limit)
+		}
+
+		for _, piece := range pieces {
+			if len(piece) > limit {
+				t.Errorf("limit %d: piece %q is longer than the limit", limit, piece)
+			}
+			if strings.Count(piece, "<") != strings.Count(piece, ">") {
+				t.Errorf("limit %d: piece %q cuts a tag", limit, piece)
+			}
+			if amp := strings.LastIndex(piece, "&"); amp >= 0 && !strings.Contains(piece[amp:], ";") {
+				t.Errorf("limit %d: piece %q cuts an entity", limit, piece)
+			}
+		}
+	}
+}
+
+func TestSplitLongLineRunes(t *testing.T) {
+	line := strings.Repeat("привет ", 50)
+
+	for _, piece := range splitLongLine(line, 25) {
+		if len(piece) > 25 {
+			t.Errorf("piece %q is longer than the limit", piece)
+		}
+		if !utf8.ValidString(piece) {
+			t.Errorf("piece %q cuts a rune", piece)
+		}
+	}
+}
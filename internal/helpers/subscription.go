@@ -10,7 +10,8 @@ import (
 )
 
 // FormatSubscriptionInfo formats subscription information for a single user
-func FormatSubscriptionInfo(baseUsername string, durationStr string, expiryTime int64, createdEmails []string, commonSubId string, addErrors []string, subURLPrefix string) string {
+// password is shown only when non-empty, i.e. when some inbound authenticates with it.
+func FormatSubscriptionInfo(baseUsername string, durationStr string, expiryTime int64, createdEmails []string, commonSubId string, password string, addErrors []string, subURLPrefix string) string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Client added successfully!\n\nBase username: %s\n", baseUsername))
 
@@ -23,6 +24,9 @@ func FormatSubscriptionInfo(baseUsername string, durationStr string, expiryTime
 	}
 
 	sb.WriteString("Traffic limit: Unlimited\n")
+	if password != "" {
+		sb.WriteString(fmt.Sprintf("Password: %s\n", password))
+	}
 	sb.WriteString("\nCreated accounts:\n")
 	for _, email := range createdEmails {
 		sb.WriteString(fmt.Sprintf("\n- %s", email))
@@ -40,8 +44,9 @@ func FormatSubscriptionInfo(baseUsername string, durationStr string, expiryTime
 	return sb.String()
 }
 
-// FormatCompactTrafficReport formats a compact and beautiful traffic report for X-Ray users
-func FormatCompactTrafficReport(inbounds []models.Inbound, onlineUsers []string) string {
+// FormatCompactTrafficReport formats a compact and beautiful traffic report for X-Ray users.
+// A zero unit picks one automatically from the grand total.
+func FormatCompactTrafficReport(inbounds []models.Inbound, onlineUsers []string, unit TrafficUnit, sortType models.SortType) string {
 	if len(inbounds) == 0 {
 		return "📭 <b>No Users Found</b>\n\nThere are no users in the system yet."
 	}
@@ -54,85 +59,27 @@ func FormatCompactTrafficReport(inbounds []models.Inbound, onlineUsers []string)
 		onlineSet[baseUser] = true
 	}
 
-	// Aggregate user data by base username
-	userSummary := make(map[string]*UserTrafficSummary)
-
-	for _, inbound := range inbounds {
-		for _, clientStat := range inbound.ClientStats {
-			baseUsername := ExtractBaseUsername(clientStat.Email)
-
-			if userSummary[baseUsername] == nil {
-				userSummary[baseUsername] = &UserTrafficSummary{
-					BaseUsername: baseUsername,
-					TotalUp:      0,
-					TotalDown:    0,
-					Enable:       clientStat.Enable,
-					ExpiryTime:   clientStat.ExpiryTime,
-					InboundStats: make(map[string]*InboundTrafficStats),
-				}
-			}
-
-			summary := userSummary[baseUsername]
-			summary.TotalUp += clientStat.Up
-			summary.TotalDown += clientStat.Down
-
-			// Keep enabled status if any client is enabled
-			if clientStat.Enable {
-				summary.Enable = true
-			}
-
-			// Use the latest expiry time
-			if clientStat.ExpiryTime > summary.ExpiryTime {
-				summary.ExpiryTime = clientStat.ExpiryTime
-			}
-
-			// Track stats per inbound
-			if summary.InboundStats[inbound.Remark] == nil {
-				summary.InboundStats[inbound.Remark] = &InboundTrafficStats{
-					Down: 0,
-					Up:   0,
-				}
-			}
-			summary.InboundStats[inbound.Remark].Down += clientStat.Down
-			summary.InboundStats[inbound.Remark].Up += clientStat.Up
-		}
-	}
+	users := AggregateUserTraffic(inbounds)
 
-	if len(userSummary) == 0 {
+	if len(users) == 0 {
 		return "📭 <b>No Active Users</b>\n\nNo user traffic data available."
 	}
 
-	// Convert to slice for sorting
-	var users []*UserTrafficSummary
-	for _, summary := range userSummary {
-		users = append(users, summary)
-	}
-
-	// Sort users by total traffic (descending), then by name (ascending) for ties
-	sort.Slice(users, func(i, j int) bool {
-		totalI := users[i].TotalUp + users[i].TotalDown
-		totalJ := users[j].TotalUp + users[j].TotalDown
-
-		if totalI == totalJ {
-			// If traffic is equal, sort by username alphabetically
-			return users[i].BaseUsername < users[j].BaseUsername
-		}
+	SortUserTraffic(users, sortType)
 
-		// Sort by total traffic (descending)
-		return totalI > totalJ
-	})
-
-	// Calculate totals
+	// Calculate totals first so every line uses the same unit
 	var grandTotalUp, grandTotalDown int64
+	for _, summary := range users {
+		grandTotalUp += summary.TotalUp
+		grandTotalDown += summary.TotalDown
+	}
+	unit = ResolveTrafficUnit(unit, max(grandTotalDown, grandTotalUp))
 
 	// Prepare all report lines
 	var reportLines []TrafficReportLine
 
 	// Add user lines
 	for _, summary := range users {
-		grandTotalUp += summary.TotalUp
-		grandTotalDown += summary.TotalDown
-
 		// Determine online status
 		statusIcon := "🔴"
 		if onlineSet[summary.BaseUsername] {
@@ -142,23 +89,21 @@ func FormatCompactTrafficReport(inbounds []models.Inbound, onlineUsers []string)
 		// Extract clean username (remove everything after @ or _)
 		displayName := extractCleanUsername(summary.BaseUsername)
 
-		// Convert traffic to GB with 2 decimal places
-		downGB := float64(summary.TotalDown) / constants.BytesInGB
-		upGB := float64(summary.TotalUp) / constants.BytesInGB
+		// Add traffic limit usage if set
+		extraInfo := formatTrafficLimit(summary.TotalUp+summary.TotalDown, summary.TrafficLimit, unit)
 
 		// Add expiry info if set
-		expiryInfo := ""
 		if summary.ExpiryTime > 0 {
 			expiryDate := time.Unix(summary.ExpiryTime/1000, 0)
-			expiryInfo = fmt.Sprintf(" (until %s)", expiryDate.Format("02.01.06"))
+			extraInfo += fmt.Sprintf(" (until %s)", expiryDate.Format("02.01.06"))
 		}
 
 		reportLines = append(reportLines, TrafficReportLine{
 			StatusIcon:  statusIcon,
 			DisplayName: displayName,
-			DownGB:      downGB,
-			UpGB:        upGB,
-			ExtraInfo:   expiryInfo,
+			Down:        unit.Value(summary.TotalDown),
+			Up:          unit.Value(summary.TotalUp),
+			ExtraInfo:   extraInfo,
 			IsTotal:     false,
 		})
 	}
@@ -167,21 +112,18 @@ func FormatCompactTrafficReport(inbounds []models.Inbound, onlineUsers []string)
 	reportLines = append(reportLines, TrafficReportLine{
 		StatusIcon:  "",
 		DisplayName: "────────────────",
-		DownGB:      0,
-		UpGB:        0,
+		Down:        0,
+		Up:          0,
 		ExtraInfo:   "",
 		IsSeparator: true,
 	})
 
 	// Add grand total line
-	grandTotalDownGB := float64(grandTotalDown) / constants.BytesInGB
-	grandTotalUpGB := float64(grandTotalUp) / constants.BytesInGB
-
 	reportLines = append(reportLines, TrafficReportLine{
 		StatusIcon:  "📊",
 		DisplayName: "Total",
-		DownGB:      grandTotalDownGB,
-		UpGB:        grandTotalUpGB,
+		Down:        unit.Value(grandTotalDown),
+		Up:          unit.Value(grandTotalUp),
 		ExtraInfo:   "",
 		IsTotal:     true,
 	})
@@ -208,14 +150,12 @@ func FormatCompactTrafficReport(inbounds []models.Inbound, onlineUsers []string)
 	// Add inbound breakdown lines
 	for _, inboundName := range inboundNames {
 		stats := inboundTotals[inboundName]
-		downGB := float64(stats.Down) / constants.BytesInGB
-		upGB := float64(stats.Up) / constants.BytesInGB
 
 		reportLines = append(reportLines, TrafficReportLine{
 			StatusIcon:  "📡",
 			DisplayName: inboundName,
-			DownGB:      downGB,
-			UpGB:        upGB,
+			Down:        unit.Value(stats.Down),
+			Up:          unit.Value(stats.Up),
 			ExtraInfo:   "",
 			IsInbound:   true,
 		})
@@ -227,7 +167,7 @@ func FormatCompactTrafficReport(inbounds []models.Inbound, onlineUsers []string)
 	sb.WriteString("<pre>")
 
 	for _, line := range reportLines {
-		sb.WriteString(formatTrafficReportLine(line) + "\n")
+		sb.WriteString(formatTrafficReportLine(line, unit) + "\n")
 	}
 
 	sb.WriteString("</pre>")
@@ -235,12 +175,131 @@ func FormatCompactTrafficReport(inbounds []models.Inbound, onlineUsers []string)
 	return sb.String()
 }
 
+// AggregateUserTraffic aggregates client traffic by base username, sorted by total traffic (descending)
+func AggregateUserTraffic(inbounds []models.Inbound) []*UserTrafficSummary {
+	userSummary := make(map[string]*UserTrafficSummary)
+	unlimited := make(map[string]bool)
+
+	for _, inbound := range inbounds {
+		for _, clientStat := range inbound.ClientStats {
+			baseUsername := ExtractBaseUsername(clientStat.Email)
+
+			if userSummary[baseUsername] == nil {
+				userSummary[baseUsername] = &UserTrafficSummary{
+					BaseUsername: baseUsername,
+					ID:           clientStat.ID,
+					TotalUp:      0,
+					TotalDown:    0,
+					Enable:       clientStat.Enable,
+					ExpiryTime:   clientStat.ExpiryTime,
+					InboundStats: make(map[string]*InboundTrafficStats),
+				}
+			}
+
+			summary := userSummary[baseUsername]
+			summary.TotalUp += clientStat.Up
+			summary.TotalDown += clientStat.Down
+
+			// A single unlimited client makes the whole user unlimited
+			if clientStat.Total == 0 {
+				unlimited[baseUsername] = true
+			}
+			summary.TrafficLimit += clientStat.Total
+
+			if clientStat.ID < summary.ID {
+				summary.ID = clientStat.ID
+			}
+
+			// Keep enabled status if any client is enabled
+			if clientStat.Enable {
+				summary.Enable = true
+			}
+
+			// Use the latest expiry time
+			if clientStat.ExpiryTime > summary.ExpiryTime {
+				summary.ExpiryTime = clientStat.ExpiryTime
+			}
+
+			// Track stats per inbound
+			if summary.InboundStats[inbound.Remark] == nil {
+				summary.InboundStats[inbound.Remark] = &InboundTrafficStats{
+					Down: 0,
+					Up:   0,
+				}
+			}
+			summary.InboundStats[inbound.Remark].Down += clientStat.Down
+			summary.InboundStats[inbound.Remark].Up += clientStat.Up
+		}
+	}
+
+	// Convert to slice for sorting
+	var users []*UserTrafficSummary
+	for _, summary := range userSummary {
+		if unlimited[summary.BaseUsername] {
+			summary.TrafficLimit = 0
+		}
+		users = append(users, summary)
+	}
+
+	// Sort users by total traffic (descending), then by name (ascending) for ties
+	sort.Slice(users, func(i, j int) bool {
+		totalI := users[i].TotalUp + users[i].TotalDown
+		totalJ := users[j].TotalUp + users[j].TotalDown
+
+		if totalI == totalJ {
+			// If traffic is equal, sort by username alphabetically
+			return users[i].BaseUsername < users[j].BaseUsername
+		}
+
+		// Sort by total traffic (descending)
+		return totalI > totalJ
+	})
+
+	return users
+}
+
+// SortUserTraffic sorts aggregated users by the given sort type, breaking ties by name
+// so the same data always produces the same report
+func SortUserTraffic(users []*UserTrafficSummary, sortType models.SortType) {
+	sort.SliceStable(users, func(i, j int) bool {
+		a, b := users[i], users[j]
+
+		switch sortType {
+		case models.SortByCreationOrder:
+			if a.ID != b.ID {
+				return a.ID < b.ID
+			}
+		case models.SortByExpiryDate:
+			// Unlimited users go last
+			if a.ExpiryTime != b.ExpiryTime {
+				if a.ExpiryTime == 0 || b.ExpiryTime == 0 {
+					return b.ExpiryTime == 0
+				}
+				return a.ExpiryTime < b.ExpiryTime
+			}
+		case models.SortByStatus:
+			if a.Enable != b.Enable {
+				return a.Enable
+			}
+		case models.SortByName:
+			// Name is the tie-breaker below
+		default:
+			totalA, totalB := a.TotalUp+a.TotalDown, b.TotalUp+b.TotalDown
+			if totalA != totalB {
+				return totalA > totalB
+			}
+		}
+
+		return a.BaseUsername < b.BaseUsername
+	})
+}
+
 // TrafficReportLine represents a single line in the traffic report
 type TrafficReportLine struct {
 	StatusIcon  string  // Status icon (🟢, 🔴, 📊, 📡, etc.)
 	DisplayName string  // Name to display
-	DownGB      float64 // Download traffic in GB
-	UpGB        float64 // Upload traffic in GB
+	Down        float64 // Download traffic in the report unit
+	Up          float64 // Upload traffic in the report unit
 	ExtraInfo   string  // Additional info (expiry, etc.)
 	IsTotal     bool    // Whether this is a total line
 	IsInbound   bool    // Whether this is an inbound line
@@ -248,7 +307,7 @@ type TrafficReportLine struct {
 }
 
 // formatTrafficReportLine formats a single line of the traffic report with consistent alignment
-func formatTrafficReportLine(line TrafficReportLine) string {
+func formatTrafficReportLine(line TrafficReportLine, unit TrafficUnit) string {
 	const nameWidth = 16
 	const trafficWidth = 8
 
@@ -267,12 +326,12 @@ func formatTrafficReportLine(line TrafficReportLine) string {
 	var trafficStr string
 	if line.IsTotal || line.IsInbound {
 		// For totals and inbounds, show traffic in bold-like format
-		trafficStr = fmt.Sprintf("%*.2f GB ⬇ %*.2f GB ⬆",
-			trafficWidth, line.DownGB, trafficWidth-1, line.UpGB)
+		trafficStr = fmt.Sprintf("%*.2f %s ⬇ %*.2f %s ⬆",
+			trafficWidth, line.Down, unit.Name, trafficWidth-1, line.Up, unit.Name)
 	} else {
 		// For regular users, standard format
-		trafficStr = fmt.Sprintf("%*.2f GB ⬇ %*.2f GB ⬆",
-			trafficWidth, line.DownGB, trafficWidth-1, line.UpGB)
+		trafficStr = fmt.Sprintf("%*.2f %s ⬇ %*.2f %s ⬆",
+			trafficWidth, line.Down, unit.Name, trafficWidth-1, line.Up, unit.Name)
 	}
 
 	// Combine all parts
@@ -285,11 +344,33 @@ func formatTrafficReportLine(line TrafficReportLine) string {
 	}
 }
 
+// formatTrafficLimit formats used traffic against a limit, flagging users near or over it
+func formatTrafficLimit(usedBytes, limitBytes int64, unit TrafficUnit) string {
+	if limitBytes <= 0 {
+		return ""
+	}
+
+	percent := float64(usedBytes) / float64(limitBytes) * 100
+	info := fmt.Sprintf(" (%.1f/%.1f %s, %.0f%%)",
+		unit.Value(usedBytes), unit.Value(limitBytes), unit.Name, percent)
+
+	switch {
+	case percent >= 100:
+		return info + " ⛔"
+	case percent >= constants.TrafficWarnPercent:
+		return info + " ⚠️"
+	default:
+		return info
+	}
+}
+
 // UserTrafficSummary represents aggregated traffic data for a user
 type UserTrafficSummary struct {
 	BaseUsername string
+	ID           int // lowest client ID, for sorting by creation order
 	TotalUp      int64
 	TotalDown    int64
+	TrafficLimit int64 // bytes across all clients, 0 if any client is unlimited
 	Enable       bool
 	ExpiryTime   int64
 	InboundStats map[string]*InboundTrafficStats
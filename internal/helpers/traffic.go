@@ -2,21 +2,33 @@ package helpers
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"xui-tg-admin/internal/constants"
 	"xui-tg-admin/internal/models"
 )
 
-// FormatNetworkUsageReport formats a beautiful network usage report
-func FormatNetworkUsageReport(inbounds []models.Inbound) string {
+// FormatNetworkUsageReport formats a beautiful network usage report.
+// A zero unit picks one automatically from the largest total.
+func FormatNetworkUsageReport(inbounds []models.Inbound, unit TrafficUnit) string {
+	// The grand total is the largest figure in the report
+	var grandDown, grandUp int64
+	for _, inbound := range inbounds {
+		for _, client := range inbound.ClientStats {
+			grandDown += client.Down
+			grandUp += client.Up
+		}
+	}
+	unit = ResolveTrafficUnit(unit, max(grandDown, grandUp))
+
 	var sb strings.Builder
 	sb.WriteString("<b>Network Usage Report:</b>\n")
 	sb.WriteString("<pre>\n")
-	sb.WriteString("Email             | ↓ (GB) | ↑ (GB)\n")
+	sb.WriteString(fmt.Sprintf("Email             | ↓ (%s) | ↑ (%s)\n", unit.Name, unit.Name))
 	sb.WriteString("------------------|--------|--------\n")
 
-	var totalUploadGB int64 = 0
-	var totalDownloadGB int64 = 0
+	var totalUpload int64 = 0
+	var totalDownload int64 = 0
 
 	for _, inbound := range inbounds {
 		if len(inbound.ClientStats) == 0 {
@@ -27,42 +39,60 @@ func FormatNetworkUsageReport(inbounds []models.Inbound) string {
 		sb.WriteString(fmt.Sprintf("Inbound: %s\n", inbound.Remark))
 
 		inboundDownloadTotal, inboundUploadTotal := CalculateInboundTraffic(inbound.ClientStats)
-		totalDownloadGB += inboundDownloadTotal
-		totalUploadGB += inboundUploadTotal
+		totalDownload += inboundDownloadTotal
+		totalUpload += inboundUploadTotal
 
-		for _, client := range inbound.ClientStats {
-			sb.WriteString(FormatTableLine(client.Email, client.Down, client.Up))
+		for _, client := range sortClientStatsByTraffic(inbound.ClientStats) {
+			sb.WriteString(FormatTableLine(client.Email, client.Down, client.Up, unit))
 		}
 
 		sb.WriteString("-----------\n")
-		sb.WriteString(FormatTableLine("Total:", inboundDownloadTotal*constants.BytesInGB, inboundUploadTotal*constants.BytesInGB))
+		sb.WriteString(FormatTableLine("Total:", inboundDownloadTotal, inboundUploadTotal, unit))
 	}
 
 	sb.WriteString("\n")
-	sb.WriteString(FormatTableLine("Grand Total:", totalDownloadGB*constants.BytesInGB, totalUploadGB*constants.BytesInGB))
+	sb.WriteString(FormatTableLine("Grand Total:", totalDownload, totalUpload, unit))
 	sb.WriteString("</pre>")
 
 	return sb.String()
 }
 
-// CalculateInboundTraffic calculates total traffic for an inbound (in GB)
-func CalculateInboundTraffic(clientStats []models.ClientStat) (downloadGB int64, uploadGB int64) {
+// CalculateInboundTraffic calculates total traffic for an inbound in bytes.
+// Summing raw bytes keeps sub-GB usage that per-client rounding would drop.
+func CalculateInboundTraffic(clientStats []models.ClientStat) (downloadBytes int64, uploadBytes int64) {
 	for _, client := range clientStats {
-		downloadGB += client.Down / constants.BytesInGB
-		uploadGB += client.Up / constants.BytesInGB
+		downloadBytes += client.Down
+		uploadBytes += client.Up
 	}
 	return
 }
 
+// sortClientStatsByTraffic returns a copy of the client stats ordered by total traffic, heaviest first
+func sortClientStatsByTraffic(clientStats []models.ClientStat) []models.ClientStat {
+	sorted := make([]models.ClientStat, len(clientStats))
+	copy(sorted, clientStats)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		totalI := sorted[i].Up + sorted[i].Down
+		totalJ := sorted[j].Up + sorted[j].Down
+		if totalI != totalJ {
+			return totalI > totalJ
+		}
+		return sorted[i].Email < sorted[j].Email
+	})
+
+	return sorted
+}
+
 // FormatTableLine formats a single line of the traffic table
-func FormatTableLine(email string, downBytes int64, upBytes int64) string {
-	downGB := float64(downBytes) / constants.BytesInGB
-	upGB := float64(upBytes) / constants.BytesInGB
+func FormatTableLine(email string, downBytes int64, upBytes int64, unit TrafficUnit) string {
+	down := unit.Value(downBytes)
+	up := unit.Value(upBytes)
 
 	displayEmail := email
 	if len(email) > constants.MaxEmailDisplayLength {
 		displayEmail = email[:constants.MaxEmailSuffixLength] + "..."
 	}
 
-	return fmt.Sprintf("%-17s | %6.2f | %6.2f\n", displayEmail, downGB, upGB)
+	return fmt.Sprintf("%-17s | %6.2f | %6.2f\n", displayEmail, down, up)
 }
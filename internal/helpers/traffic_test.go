@@ -0,0 +1,64 @@
+package helpers
+
+import (
+	"testing"
+
+	"xui-tg-admin/internal/models"
+)
+
+func TestCalculateInboundTrafficSubGB(t *testing.T) {
+	const mb = 1024 * 1024
+
+	// Each client is well under a GB; rounding per client would report nothing
+	stats := []models.ClientStat{
+		{Email: "alice-1", Down: 300 * mb, Up: 20 * mb},
+		{Email: "bob-1", Down: 400 * mb, Up: 30 * mb},
+		{Email: "carol-1", Down: 500 * mb, Up: 50 * mb},
+	}
+
+	down, up := CalculateInboundTraffic(stats)
+	if down != 1200*mb {
+		t.Errorf("download = %d bytes, want %d", down, 1200*mb)
+	}
+	if up != 100*mb {
+		t.Errorf("upload = %d bytes, want %d", up, 100*mb)
+	}
+
+	if got := UnitGB.Value(down); got < 1.17 || got > 1.18 {
+		t.Errorf("download = %.3f GB, want about 1.17", got)
+	}
+	if got := UnitGB.Value(up); got <= 0 {
+		t.Errorf("upload = %.3f GB, want the sub-GB usage kept", got)
+	}
+}
+
+func TestCalculateInboundTrafficEmpty(t *testing.T) {
+	down, up := CalculateInboundTraffic(nil)
+	if down != 0 || up != 0 {
+		t.Errorf("CalculateInboundTraffic(nil) = %d, %d, want 0, 0", down, up)
+	}
+}
+
+func TestFormatTrafficLimit(t *testing.T) {
+	const mb = 1024 * 1024
+
+	tests := []struct {
+		name  string
+		used  int64
+		limit int64
+		want  string
+	}{
+		{"no limit", 100 * mb, 0, ""},
+		{"fractional limit", 256 * mb, 1536 * mb, " (0.2/1.5 GB, 17%)"},
+		{"near the limit", 1400 * mb, 1536 * mb, " (1.4/1.5 GB, 91%) ⚠️"},
+		{"over the limit", 600 * mb, 512 * mb, " (0.6/0.5 GB, 117%) ⛔"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTrafficLimit(tt.used, tt.limit, UnitGB); got != tt.want {
+				t.Errorf("formatTrafficLimit = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package helpers
+
+import "strings"
+
+// TrafficUnit is a unit traffic figures are shown in
+type TrafficUnit struct {
+	Name  string
+	Bytes int64
+}
+
+var (
+	UnitMB = TrafficUnit{Name: "MB", Bytes: 1024 * 1024}
+	UnitGB = TrafficUnit{Name: "GB", Bytes: 1024 * 1024 * 1024}
+	UnitTB = TrafficUnit{Name: "TB", Bytes: 1024 * 1024 * 1024 * 1024}
+)
+
+// ParseTrafficUnit parses a configured unit name. "auto" and unknown names return
+// the zero unit, which lets ResolveTrafficUnit pick one per report.
+func ParseTrafficUnit(name string) TrafficUnit {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case UnitMB.Name:
+		return UnitMB
+	case UnitGB.Name:
+		return UnitGB
+	case UnitTB.Name:
+		return UnitTB
+	default:
+		return TrafficUnit{}
+	}
+}
+
+// ResolveTrafficUnit returns the preferred unit, or picks the largest unit that keeps
+// maxBytes at one or more so a whole report reads naturally
+func ResolveTrafficUnit(preferred TrafficUnit, maxBytes int64) TrafficUnit {
+	if preferred.Bytes > 0 {
+		return preferred
+	}
+
+	switch {
+	case maxBytes >= UnitTB.Bytes:
+		return UnitTB
+	case maxBytes >= UnitGB.Bytes:
+		return UnitGB
+	default:
+		return UnitMB
+	}
+}
+
+// Value converts bytes to the unit
+func (u TrafficUnit) Value(bytes int64) float64 {
+	return float64(bytes) / float64(u.Bytes)
+}
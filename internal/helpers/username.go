@@ -2,28 +2,34 @@ package helpers
 
 import (
 	"fmt"
+	"hash/fnv"
+	"strings"
 	"xui-tg-admin/internal/constants"
 )
 
 // ExtractBaseUsername извлекает базовое имя пользователя без постфикса номера инбаунда
 // Например: "qwe-qwe-qwe-1" -> "qwe-qwe-qwe", "user123-2" -> "user123", "user123" -> "user123"
+//
+// Отбрасывается только последний сегмент и только если он выглядит так, как его формирует
+// FormatEmailWithInboundNumber: "agent-007" и "user-2-vip" остаются без изменений
 func ExtractBaseUsername(email string) string {
-	// Ищем с конца строки последний дефис, за которым идут только цифры
-	for i := len(email) - 1; i >= 0; i-- {
-		if email[i] == constants.UsernameSeparator[0] {
-			// Проверяем, что после дефиса идут только цифры
-			suffix := email[i+1:]
-			if len(suffix) > 0 && IsNumeric(suffix) {
-				return email[:i]
-			}
-			// Если после дефиса не только цифры, продолжаем поиск
-		}
+	i := strings.LastIndex(email, constants.UsernameSeparator)
+	if i <= 0 {
+		return email
+	}
+
+	if !isInboundNumber(email[i+1:]) {
+		return email
 	}
-	// Если не нашли дефис с цифрами, возвращаем всю строку
-	return email
+	return email[:i]
+}
+
+// isInboundNumber проверяет, что строка является номером инбаунда: положительное число без ведущих нулей
+func isInboundNumber(s string) bool {
+	return IsNumeric(s) && s[0] != '0'
 }
 
-// IsEmailMatchingBaseUsername проверяет, соответствует ли email базовому имени пользователя
+// IsEmailMatchingBaseUsername проверяет, соответствует ли email базовому имени пользователя (без учета регистра)
 // Например: IsEmailMatchingBaseUsername("qwe-qwe-qwe-1", "qwe-qwe-qwe") -> true
 //
 //	IsEmailMatchingBaseUsername("user123-2", "user123") -> true
@@ -32,7 +38,14 @@ func ExtractBaseUsername(email string) string {
 func IsEmailMatchingBaseUsername(email, baseUsername string) bool {
 	// Сначала извлекаем базовое имя из email
 	extractedBase := ExtractBaseUsername(email)
-	return extractedBase == baseUsername
+	return strings.EqualFold(extractedBase, baseUsername)
+}
+
+// ReplaceBaseUsername заменяет базовое имя в email, сохраняя номер инбаунда. Базовое имя
+// берётся из самого email, поэтому регистр букв в нём не важен
+// Например: ReplaceBaseUsername("Alice-2", "bob") -> "bob-2"
+func ReplaceBaseUsername(email, newBaseUsername string) string {
+	return newBaseUsername + email[len(ExtractBaseUsername(email)):]
 }
 
 // FormatEmailWithInboundNumber форматирует email с номером инбаунда
@@ -40,3 +53,18 @@ func IsEmailMatchingBaseUsername(email, baseUsername string) bool {
 func FormatEmailWithInboundNumber(baseUsername string, inboundNumber int) string {
 	return fmt.Sprintf("%s%s%d", baseUsername, constants.UsernameSeparator, inboundNumber)
 }
+
+// PseudoTelegramID формирует постоянный временный Telegram ID из имени пользователя. Его
+// получают пользователи, добавленные по @username, пока они не напишут боту
+func PseudoTelegramID(username string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(username))
+	hash := h.Sum64()
+	// Convert to int64 and ensure it's positive (Telegram IDs are positive)
+	id := int64(hash & 0x7FFFFFFFFFFFFFFF)
+	// Ensure it's not 0 (which we used as placeholder)
+	if id == 0 {
+		id = 1
+	}
+	return id
+}
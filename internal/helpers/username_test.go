@@ -0,0 +1,65 @@
+package helpers
+
+import "testing"
+
+func TestExtractBaseUsername(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{email: "alice", want: "alice"},
+		{email: "alice-1", want: "alice"},
+		{email: "alice-12", want: "alice"},
+		{email: "qwe-qwe-qwe-1", want: "qwe-qwe-qwe"},
+		{email: "agent-007", want: "agent-007"},
+		{email: "user-2-vip", want: "user-2-vip"},
+		{email: "name-0", want: "name-0"},
+		{email: "name-01", want: "name-01"},
+		{email: "-1", want: "-1"},
+		{email: "name-", want: "name-"},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractBaseUsername(tt.email); got != tt.want {
+			t.Errorf("ExtractBaseUsername(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestIsEmailMatchingBaseUsername(t *testing.T) {
+	tests := []struct {
+		email, base string
+		want        bool
+	}{
+		{email: "alice-1", base: "alice", want: true},
+		{email: "Alice-2", base: "alice", want: true},
+		{email: "alice", base: "ALICE", want: true},
+		{email: "agent-007", base: "agent", want: false},
+		{email: "alice-bob-1", base: "alice", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsEmailMatchingBaseUsername(tt.email, tt.base); got != tt.want {
+			t.Errorf("IsEmailMatchingBaseUsername(%q, %q) = %v, want %v", tt.email, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestReplaceBaseUsername(t *testing.T) {
+	tests := []struct {
+		email, newBase string
+		want           string
+	}{
+		{email: "alice-1", newBase: "bob", want: "bob-1"},
+		{email: "Alice-2", newBase: "bob", want: "bob-2"},
+		{email: "ALICE", newBase: "bob", want: "bob"},
+		{email: "agent-007", newBase: "bond", want: "bond"},
+		{email: "qwe-qwe-3", newBase: "asd", want: "asd-3"},
+	}
+
+	for _, tt := range tests {
+		if got := ReplaceBaseUsername(tt.email, tt.newBase); got != tt.want {
+			t.Errorf("ReplaceBaseUsername(%q, %q) = %q, want %q", tt.email, tt.newBase, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package i18n
+
+// english is the English message catalog
+var english = map[Key]string{
+	AdminWelcome:                  "🚀 <b>Welcome to X-UI Admin Panel!</b>\n\nYou have administrator privileges. Use the menu below to manage your VPN users, monitor connections, and configure settings.",
+	MainMenu:                      "🏠 <b>Main Menu</b>\n\nSelect an action:",
+	AddMemberPrompt:               "👤 <b>Add New User</b>\n\n📝 Please enter a username for the new user:\n\n<i>• Use only letters, numbers, and underscores\n• 3-20 characters long\n• Example: john_doe, user123</i>",
+	OnlineConnectionError:         "❌ <b>Connection Error</b>\n\nCouldn't retrieve online users. Please check your server connection and try again.",
+	OnlineNone:                    "💤 <b>No Active Connections</b>\n\nNo users are currently connected to the VPN server.",
+	OnlineHeader:                  "🟢 <b>Active Connections (%d)</b>\n\n",
+	OnlineMemberLine:              "👤 <b>%s</b> · %d conn · ↓ %.2f %s ↑ %.2f %s\n",
+	OnlineRecentHeader:            "\n🕓 <b>Seen in the Last %d Hours</b>\n\n",
+	OnlineRecentLine:              "⚪ %s · %s\n",
+	AgoJustNow:                    "just now",
+	AgoMinutes:                    "%d min ago",
+	AgoHours:                      "%d h ago",
+	UsageConnectionError:          "❌ <b>Connection Error</b>\n\nCouldn't retrieve network usage data. Please check your server connection and try again.",
+	ResetAllConfirm:               "⚠️ <b>Reset All Network Usage</b>\n\nThis will reset traffic statistics for:\n\n👥 <b>%d users</b> (%d clients)\n📡 <b>%d inbounds</b>\n📊 <b>%.2f %s</b> of recorded traffic\n\n<b>⚠️ This action cannot be undone!</b>\n\nAre you sure you want to proceed?",
+	AddMemberInvalidUsername:      "❌ <b>Invalid Username</b>\n\n%s\n\n💡 <b>Requirements:</b>\n• 3-20 characters\n• Letters, numbers, underscores only\n• Example: john_doe, user123\n\nPlease try again:",
+	AddMemberDurationPrompt:       "⏰ <b>Set Duration for %s</b>\n\n📅 Enter subscription duration in days:\n\n<i>• Example: 30 (for 30 days)\n• Maximum: %d days\n• Or pick a preset below, or Infinite for unlimited time</i>",
+	SessionUsernameLost:           "❌ <b>Session Error</b>\n\nUsername data was lost. Please start over.",
+	NoEnabledInbounds:             "❌ <b>No Enabled Inbounds</b>\n\nThe server has no enabled inbound connections, so new configs can't be created right now. The administrators have been notified.",
+	MalformedInboundsAlert:        "⚠️ <b>Unreadable Inbound Settings</b>\n\nThe settings of inbound %s can't be parsed. Their members still show up from traffic stats, but their subscription links and limits are unknown to the bot. Check the inbound in the X-UI panel.",
+	NoEnabledInboundsHint:         "\n\n💡 Enable at least one inbound with %s or in the X-UI panel, then try again. <code>/inbounds</code> shows which ones are off.",
+	NoEnabledInboundsAlert:        "⚠️ <b>No Enabled Inbounds</b>\n\nThe panel has %d inbounds and none of them is both enabled and allowed by INBOUNDS_INCLUDE/INBOUNDS_EXCLUDE, so new members can't be created.",
+	AddMemberInvalidDuration:      "❌ <b>Invalid Duration</b>\n\n%s\n\n💡 <b>Valid formats:</b>\n• Number: 30 (for 30 days)\n• Range: 1-%d days\n• Or use the Infinite button\n\nPlease try again:",
+	AddMemberCreating:             "⏳ <b>Creating User...</b>\n\nPlease wait while we set up the new user configuration across all servers.",
+	AddMemberFailed:               "❌ <b>User Creation Failed</b>\n\nCouldn't create user '%s' in any server configuration.\n\n<b>Errors:</b>\n%s\n\nPlease check server configuration or try again later.",
+	AddMemberVerifyFailed:         "Couldn't verify the created clients: %s",
+	AddMemberVerifyMissing:        "%s: the panel reported success but the client doesn't exist",
+	AddMemberRetryButton:          "🔁 Retry",
+	AddMemberRetryExpired:         "⌛ <b>Retry Expired</b>\n\nThis retry is no longer available. Please add the user again.",
+	ManageMember:                  "👤 <b>Managing User: %s</b>\n\n🎛️ Choose an action:",
+	MemberTags:                    "\n\n🏷 <b>Tags:</b> %s",
+	TagsPrompt:                    "🏷 <b>Tags for %s</b>\n\n<b>Current:</b> %s\n\nSend <code>+tag</code> to add and <code>-tag</code> to remove, several separated by spaces.\n\n<i>• Example: +vip -trial</i>",
+	TagsNone:                      "none",
+	TagsInvalidInput:              "❌ <b>Invalid Input</b> %s\n\nStart each tag with <code>+</code> to add it or <code>-</code> to remove it:",
+	TagsInvalidTag:                "❌ <b>Invalid Tag</b> %s\n\n%s\n\nPlease try again:",
+	TagsSaveFailed:                "❌ <b>Failed to Save Tags</b>\n\nCouldn't write the tags to storage. Please try again.",
+	TagsSaved:                     "✅ Tags updated for <b>%s</b>",
+	TagFilterActive:               "\n\n🏷 Showing users tagged <b>#%s</b>",
+	UsageTagHeader:                "🏷 <b>#%s</b>\n\n",
+	MemberPassword:                "\n\n🔑 <b>Password:</b> <code>%s</code>",
+	MemberNote:                    "\n\n📝 <b>Note:</b> %s",
+	NotePrompt:                    "📝 <b>Note for %s</b>\n\nSend the note text, or <code>-</code> to remove the note.",
+	NoteCurrent:                   "\n\n<b>Current note:</b> %s",
+	NoteEmpty:                     "❌ <b>Empty Note</b>\n\nSend the note text, or <code>-</code> to remove the note:",
+	NoteSaveFailed:                "❌ <b>Failed to Save Note</b>\n\nCouldn't write the note to storage. Please try again.",
+	NoteSaved:                     "✅ Note saved for <b>%s</b>",
+	TrafficLimitPrompt:            "📶 <b>Traffic Limit for %s</b>\n\n<b>Current limit:</b> %s\n\nSend the new limit in GB, e.g. <code>50</code> or <code>2.5</code>, or <code>0</code> for unlimited. Traffic used so far is kept.",
+	TrafficLimitInvalid:           "❌ <b>Invalid Traffic Limit</b>\n\n%s\n\nSend the limit in GB, or <code>0</code> for unlimited:",
+	TrafficLimitSet:               "✅ Traffic limit of <b>%s</b> set to <b>%s</b> on %d clients",
+	TrafficLimitFailed:            "❌ <b>Update Failed</b>\n\nCouldn't change the traffic limit of '%s'.",
+	TrafficLimitUnlimited:         "unlimited",
+	NoteCleared:                   "✅ Note removed for <b>%s</b>",
+	SessionUserLost:               "❌ <b>Session Error</b>\n\nUser data was lost. Please start over.",
+	InvalidAction:                 "❌ <b>Invalid Action</b>\n\nPlease select one of the available options from the menu.",
+	ViewConfigInboundsFailed:      "Failed to get inbounds: %v",
+	MemberSettingsUnreadable:      "⚠️ <b>Settings Unreadable</b>\n\nUser '%s' has traffic on inbound %s, but the bot can't parse that inbound's settings, so the subscription link is unknown. Check the inbound in the X-UI panel.",
+	MemberNotFound:                "❌ <b>User Not Found</b>\n\nNo configuration found for user '%s'. The user may have been deleted or never existed.",
+	MemberConfig:                  "🔗 <b>Configuration for %s</b>\n\n📋 <b>Subscription URL:</b>\n<code>%s</code>\n\n<i>Copy this link to your VPN client or scan the QR code below</i>",
+	SubscriptionDead:              "⚠️ <b>Subscription Link Is Dead</b>\n\nThe panel doesn't serve any configs for <b>%s</b> at:\n<code>%s</code>\n\nCheck that the subscription service is enabled and the client is active before sharing the link.",
+	SubscriptionConfigCount:       "\n\n📦 Configs in subscription: <b>%d</b>",
+	ResetTrafficChooseInbound:     "🔄 <b>Reset Traffic for %s</b>\n\nReset every inbound or just one?",
+	ResetNotifyButton:             "📨 Notify User (%d)",
+	ResetNotifyMessage:            "🔄 <b>Traffic Reset</b>\n\nThe traffic of your account <b>%s</b> has been reset for the new period.",
+	ResetNotifyDone:               "📨 Told <b>%s</b> about the reset (%d of %d recipients)",
+	ResetNotifyFailed:             "❌ <b>Notice Not Sent</b>\n\nCouldn't message the owner of '%s'. They may have blocked the bot or never started it.",
+	ResetNotifyNoRecipients:       "ℹ️ No Telegram ID is known for '%s', so they can't be notified.",
+	ResetTrafficAllButton:         "🔄 All Inbounds",
+	ResetTrafficInProgress:        "⏳ <b>Resetting Traffic...</b>\n\nResetting traffic statistics for user '%s'. Please wait...",
+	PanelAuthError:                "❌ <b>Panel Login Failed</b>\n\nThe X-UI panel rejected the bot's credentials. The administrators need to check the panel username and password.",
+	PanelServerError:              "❌ <b>Panel Error</b>\n\nThe X-UI panel failed with status %d. Please try again later.",
+	PanelErrDuplicateEmail:        "a client with this name already exists on the panel. Pick another name or delete the old client first.",
+	PanelErrClientNotFound:        "the client no longer exists on the panel. It may have been deleted in X-UI directly.",
+	PanelErrInboundNotFound:       "the inbound no longer exists on the panel. Check the inbounds with /inbounds.",
+	PanelErrPortInUse:             "the port is already used by another inbound.",
+	PanelErrUnreachable:           "the panel couldn't be reached. Check that X-UI is running and try again.",
+	PanelErrAuth:                  "the panel rejected the bot's credentials. Check the panel username and password.",
+	PanelErrServer:                "the panel failed with status %d. Please try again later.",
+	PanelErrUnknown:               "the panel request failed. The details are in the bot's log.",
+	PanelErrInbound:               "Inbound %d: %s",
+	OperationStillWorking:         "⏳ <b>Still Working...</b>\n\nThis is taking longer than usual, but the bot is still on it. The result will follow here.",
+	OperationTimedOut:             "\n\n⌛ <b>Timed out</b> after %d of %d. The rest was left untouched; run the operation again to finish it.",
+	ServerDataConnectionError:     "❌ <b>Connection Error</b>\n\nCouldn't retrieve server data. Please check your connection and try again.",
+	ResetTrafficDone:              "✅ <b>Traffic Reset Complete</b>\n\n🔄 Successfully reset traffic for user <b>%s</b> (%d configurations)",
+	SomeErrorsOccurred:            "\n\n⚠️ <b>Some errors occurred:</b>\n%s",
+	ResetTrafficFailed:            "❌ <b>Reset Failed</b>\n\nNo active configurations found for user '%s'.",
+	ErrorsList:                    "\n\n<b>Errors:</b>\n%s",
+	RenamePrompt:                  "✏️ <b>Rename User %s</b>\n\n📝 Please enter a new username:\n\n<i>• Use only letters, numbers, and underscores\n• Subscription, expiry and traffic are preserved</i>",
+	RenameInvalidUsername:         "❌ <b>Invalid Username</b>\n\n%s\n\nPlease try again:",
+	RenameSameUsername:            "❌ <b>Same Username</b>\n\nThe new username matches the current one. Please enter a different name:",
+	UserListConnectionError:       "❌ <b>Connection Error</b>\n\nCouldn't retrieve user list. Please check your server connection and try again.",
+	UsernameTaken:                 "❌ <b>Username Taken</b>\n\nUser '%s' already exists. Please choose another name:",
+	MemberAlreadyExists:           "⚠️ <b>User Already Exists</b>\n\nUser <b>%s</b> is already on the server, so no new clients were created.\n\nChoose an action to manage the existing user instead:",
+	RenameInProgress:              "⏳ <b>Renaming User...</b>\n\nRenaming '%s' to '%s' across all server configurations. Please wait...",
+	RenameFailed:                  "❌ <b>Rename Failed</b>\n\nCouldn't rename user '%s'.",
+	RenameDone:                    "✅ <b>User Renamed</b>\n\n✏️ <b>%s</b> → <b>%s</b> (%d configurations)",
+	DeleteConfirm:                 "🗑️ <b>Confirm User Deletion</b>\n\n⚠️ You are about to permanently delete user <b>%s</b>\n\n<b>This action will:</b>\n• Remove user from all server configurations\n• Delete all associated data\n• Cannot be undone\n\nAre you absolutely sure?",
+	DeleteInvalidSelection:        "❌ <b>Invalid Selection</b>\n\nPlease use the Confirm button above to proceed with deletion or the Return button to cancel.",
+	SessionDeleteLost:             "❌ <b>Session Error</b>\n\nUser data was lost. Please start the deletion process again.",
+	DeleteInProgress:              "⏳ <b>Deleting User...</b>\n\nRemoving user '%s' from all server configurations. Please wait...",
+	DeleteFailed:                  "❌ <b>Deletion Failed</b>\n\nCouldn't delete user '%s'. Please try again or contact administrator.\n\n<b>Error:</b> %v",
+	DeleteDone:                    "✅ <b>User Deleted Successfully</b>\n\n🗑️ User '%s' has been permanently removed from all server configurations.",
+	ExpiredNone:                   "✅ <b>No Expired Users</b>\n\nEvery user's subscription is still active.",
+	ExpiredMemberLine:             "• %s\n",
+	ExpiredConfirm:                "🧹 <b>Delete Expired Users</b>\n\n⚠️ The following <b>%d users</b> have expired and will be removed from all server configurations:\n\n%s\n<b>This action cannot be undone!</b>",
+	ExpiredInvalidSelection:       "❌ <b>Invalid Selection</b>\n\nPlease use the Confirm button above to delete the expired users or the Return button to cancel.",
+	ExpiredDeleteInProgress:       "⏳ <b>Deleting %d Expired Users...</b>\n\nPlease wait...",
+	ExpiredDeleteFailed:           "❌ <b>Deletion Failed</b>\n\nCouldn't delete the expired users.\n\n<b>Error:</b> %v",
+	ExpiredDeleteDone:             "✅ <b>Expired Users Deleted</b>\n\n🧹 Removed <b>%d users</b> from all server configurations.",
+	ReconcileClean:                "✅ <b>Accounts In Sync</b>\n\nEvery stored trusted user account matches a user in the panel.",
+	ReconcileHeader:               "🔍 <b>Account Reconciliation</b>\n",
+	ReconcileOrphanedHeader:       "\n🗑 <b>Stored but missing in the panel (%d):</b>\n<i>These still count against the trusted user's limit.</i>\n",
+	ReconcileUntrackedHeader:      "\n❓ <b>In the panel but not stored (%d):</b>\n<i>These don't count against the limit. Check them in the panel.</i>\n",
+	ReconcileAccountLine:          "• %s (by %s)\n",
+	ReconcilePruneQuestion:        "\nRemove the <b>%d</b> missing accounts from storage?",
+	ReconcileInvalidSelection:     "❌ <b>Invalid Selection</b>\n\nPlease use the Confirm button above to prune the accounts or the Return button to cancel.",
+	ReconcilePruneFailed:          "❌ <b>Prune Failed</b>\n\nCouldn't update storage.\n\n<b>Error:</b> %v",
+	ReconcilePruneDone:            "✅ <b>Accounts Pruned</b>\n\n🗑 Removed <b>%d</b> orphaned accounts from storage.",
+	BulkSelectPrompt:              "☑️ <b>Bulk Enable/Disable</b>\n\nTap users to select them, then choose <b>Enable</b> or <b>Disable</b>.\n\n🟢 enabled · 🔴 disabled",
+	BulkReturnHint:                "Use the buttons below the next message to select users.",
+	BulkUseButtons:                "❌ <b>Invalid Selection</b>\n\nPlease use the buttons in the selection message or the Return button to cancel.",
+	BulkEnableButton:              "🟢 Enable (%d)",
+	BulkDisableButton:             "🔴 Disable (%d)",
+	BulkNothingSelected:           "Select at least one user first",
+	BulkActionEnabled:             "Enabled",
+	BulkActionDisabled:            "Disabled",
+	BulkResultOK:                  "✅ %s\n",
+	BulkResultFailed:              "❌ %s: %s\n",
+	BulkNoClients:                 "no clients found",
+	BulkInProgress:                "⏳ <b>Updating %d Users...</b>\n\nPlease wait...",
+	BulkResultHeader:              "☑️ <b>%s: %d of %d users</b>\n\n%s",
+	BulkPageIndicator:             "📄 %d/%d",
+	DetailedUsageConnectionError:  "❌ <b>Connection Error</b>\n\nCouldn't retrieve detailed usage data. Please check your server connection and try again.",
+	UsageInboundHeader:            "📡 <b>%s</b> · %s :%d\n\n",
+	UsageInboundNotFound:          "❌ <b>Inbound Not Found</b>\n\nThe inbound no longer exists on the server.",
+	UsageAllInboundsButton:        "📊 All Inbounds",
+	UsageActiveOnlyHeader:         "✅ <b>Active subscriptions only</b>\n\n",
+	UsageActiveOnlyButton:         "✅ Active Only",
+	UsageShowAllButton:            "👥 Show All",
+	FindInboundsUsage:             "\n\n🔎 Send <code>/inbounds username</code> to list the inbounds holding that user's clients.",
+	FindInboundsHeader:            "🔎 <b>Inbounds for %s</b>\n\nFound in <b>%d</b> of %d inbounds:\n\n",
+	FindInboundsLine:              "📡 <b>#%d %s</b> · %s :%d\n<code>%s</code>\n\n",
+	FindInboundsMissingHeader:     "⚠️ <b>Missing from:</b>\n",
+	FindInboundsMissingLine:       "• #%d %s\n",
+	FindInboundsExcludedHeader:    "\n🚫 <b>Not in, excluded from new users by config:</b>\n",
+	InboundsOverviewHeader:        "📡 <b>Inbounds</b>\n\n<b>%d</b> of %d enabled. New members only get clients on enabled inbounds.\n\n",
+	InboundsOverviewEmpty:         "📡 The panel has no inbounds yet.",
+	InboundsOverviewMalformed:     "⚠️ Settings can't be read, clients are counted from traffic stats: %s\n\n",
+	InboundsOverviewExcluded:      "🚫 Excluded from new members by config: %s\n\n",
+	InboundTogglePrompt:           "\n\n🔌 Tap an inbound to enable or disable it.",
+	InboundToggleNotFound:         "❌ Inbound #%d no longer exists.",
+	InboundToggleConfirmDisable:   "🔌 <b>Disable Inbound</b>\n\n⚠️ Disable <b>#%d %s</b> (%s :%d)?\n\nAll <b>%d</b> of its clients lose access until it's enabled again.",
+	InboundToggleConfirmEnable:    "🔌 <b>Enable Inbound</b>\n\nEnable <b>#%d %s</b> (%s :%d)?\n\nIts <b>%d</b> clients can connect again, except those disabled or expired themselves.",
+	InboundToggleInvalidSelection: "❌ <b>Invalid Selection</b>\n\nPlease use the Confirm button above to change the inbound or the Return button to cancel.",
+	InboundToggleFailed:           "❌ <b>Update Failed</b>\n\nCouldn't change inbound #%d.\n\n<b>Error:</b> %v",
+	InboundToggleDisabled:         "🔴 Inbound <b>#%d %s</b> is now disabled.",
+	InboundToggleEnabled:          "🟢 Inbound <b>#%d %s</b> is now enabled.",
+	TransferUsage:                 "🔁 Send <code>/transfer account owner</code> to hand a trusted user's account to another trusted user.\n\nThe account is its ID or username, the owner a @username or Telegram ID.",
+	TransferAccountNotFound:       "❌ No stored account matches <b>%s</b>.",
+	TransferAccountAmbiguous:      "❌ Several accounts are named <b>%s</b>. Use one of their IDs instead: %s",
+	TransferTargetNotTrusted:      "❌ %s is not a trusted user.",
+	TransferSameOwner:             "ℹ️ <b>%s</b> already belongs to %s.",
+	TransferLimitReached:          "❌ %s already has %d accounts, the most a trusted user can own.",
+	TransferFailed:                "❌ Failed to transfer the account: %v",
+	TransferDone:                  "✅ Account <b>#%d %s</b> moved from %s to %s.",
+	TransferPanelFailed:           "\n\n⚠️ Couldn't hand some panel clients over, so their reminders still go to the previous owner:\n%s",
+	BroadcastPrompt:               "📣 <b>Broadcast</b>\n\nSend the announcement to deliver to every admin and trusted user. It is sent as plain text.",
+	BroadcastEmpty:                "❌ The announcement can't be empty. Send the text or return to the main menu.",
+	BroadcastNoRecipients:         "ℹ️ There is nobody to send the announcement to yet.",
+	BroadcastConfirm:              "📣 <b>Broadcast</b>\n\n<blockquote>%s</blockquote>\n\nSend this to <b>%d</b> admins and trusted users?",
+	BroadcastOwnersHint:           "\n\n%d former trusted users still own VPN accounts. Include them with the second button.",
+	BroadcastSendButton:           "✅ Send to %d",
+	BroadcastOwnersButton:         "👥 Also account owners (%d)",
+	BroadcastInvalidSelection:     "❌ <b>Invalid Selection</b>\n\nPlease use the buttons above to send the announcement or the Return button to cancel.",
+	BroadcastInProgress:           "⏳ Sending the announcement to %d recipients...",
+	BroadcastDone:                 "📣 Delivered to <b>%d</b> of %d recipients.",
+	BroadcastFailedLine:           "\n❌ %s: %s",
+	TopUsersHeader:                "🏆 <b>Top %d Users by Traffic</b>\n\n",
+	TopUsersLine:                  "%d. <b>%s</b> — %.2f %s · %s\n",
+	TopUsersInvalidCount:          "❌ <b>Invalid Count</b>\n\nUse a positive number, e.g. <code>/top 20</code>.",
+	ExportConnectionError:         "❌ <b>Connection Error</b>\n\nCouldn't retrieve usage data for export. Please check your server connection and try again.",
+	ExportNoUsers:                 "📭 <b>No Users Found</b>\n\nThere are no users in the system to export.",
+	ExportBuildFailed:             "❌ <b>Export Failed</b>\n\nCouldn't build the usage report. Please try again later.",
+	ExportCaption:                 "📄 Usage report (%d users)",
+	ExportSendFailed:              "❌ <b>Export Failed</b>\n\nCouldn't send the usage report. Please try again later.",
+	ExportDone:                    "✅ <b>Export Complete</b>",
+	ChartConnectionError:          "❌ <b>Connection Error</b>\n\nCouldn't retrieve traffic data. Please check your server connection and try again.",
+	ChartNoUsers:                  "📭 <b>No Active Users</b>\n\nNo user traffic data available.",
+	ChartRenderFailed:             "❌ <b>Chart Failed</b>\n\nCouldn't render the traffic chart. Please try again later.",
+	ChartCaptionHeader:            "<b>📊 Top %d users by traffic</b>\n🟦 download  🟧 upload\n\n",
+	ChartLegendLine:               "%d. %s — %.2f %s\n",
+	ChartSendFailed:               "❌ <b>Chart Failed</b>\n\nCouldn't send the traffic chart. Please try again later.",
+	ConfirmationExpired:           "⌛ <b>Confirmation Expired</b>\n\nThis confirmation is no longer valid. Please start the action again.",
+	ResetAllInvalidSelection:      "❌ <b>Invalid Selection</b>\n\nPlease use the Confirm button above to proceed with reset or the Return button to cancel.",
+	ResetAllInProgress:            "⏳ <b>Resetting All Traffic...</b>\n\nThis may take a few moments. Resetting traffic statistics for all users across all servers...",
+	ResetAllProgress:              "⏳ <b>Resetting All Traffic...</b>\n\nReset %d/%d clients...",
+	ResetAllConnectionError:       "❌ <b>Connection Error</b>\n\nCouldn't retrieve server data for reset operation. Please check your connection and try again.",
+	ResetAllNoUsers:               "📭 <b>No Users Found</b>\n\nThere are no users in the system to reset traffic for.",
+	ResetAllCountChanged:          "⚠️ <b>Reset Cancelled</b>\n\nYou confirmed a reset of %d clients, but the server now has %d. Nothing was reset. Please start over to review the new numbers.",
+	ResetAllDone:                  "✅ <b>Mass Traffic Reset Complete</b>\n\n🔄 Successfully reset traffic for <b>%d users</b>\n\n<i>All user traffic counters have been set to zero</i>",
+	ResetAllFailed:                "❌ <b>Mass Reset Failed</b>\n\nCouldn't reset traffic for any users.\n\n<b>Errors:</b>\n%s",
+	NoUsersYet:                    "📭 <b>No Users Found</b>\n\nThere are no users in the system yet.",
+	NoUsersYetHint:                " Use <b>Add Member</b> to create your first user.",
+	SelectMemberToEdit:            "✏️ <b>Edit User</b>\n\n👥 Select a user to manage:",
+	SelectMemberToDelete:          "🗑️ <b>Delete User</b>\n\n⚠️ Select a user to permanently delete:",
+	InvalidSelectionShort:         "Invalid selection.",
+	UnknownAction:                 "Unknown action.",
+	BackupConnectionError:         "❌ <b>Connection Error</b>\n\nCouldn't retrieve server data for backup. Please check your server connection and try again.",
+	BackupBuildFailed:             "❌ <b>Backup Failed</b>\n\nCouldn't build the backup file. Please try again later.",
+	BackupCaption:                 "💾 Backup: %d trusted users, %d admins, %d VPN accounts, %d inbounds",
+	BackupSendFailed:              "❌ <b>Backup Failed</b>\n\nCouldn't send the backup file. Please try again later.",
+	BackupDone:                    "✅ <b>Backup Complete</b>\n\nKeep this file somewhere safe. Use <b>Restore</b> to load it back.",
+	RestorePrompt:                 "♻️ <b>Restore Backup</b>\n\n📎 Please upload a backup file created with the <b>Backup</b> command.\n\n<i>Trusted users, admins, VPN accounts and member notes, tags and passwords will be restored. Panel clients are not modified.</i>",
+	RestoreUploadDocument:         "📎 Please upload the backup file as a document:",
+	RestoreDownloadFailed:         "❌ <b>Download Failed</b>\n\nCouldn't download the backup file. Please try again:",
+	RestoreReadFailed:             "❌ <b>Download Failed</b>\n\nCouldn't read the backup file. Please try again:",
+	RestoreInvalidBackup:          "❌ <b>Invalid Backup</b>\n\nThe file is not a valid backup: %v\n\nPlease upload another file:",
+	RestoreConfirm:                "♻️ <b>Confirm Restore</b>\n\nBackup from <b>%s</b> contains:\n• %d trusted users\n• %d admins\n• %d VPN accounts\n• %d member notes, tag sets and passwords\n\nExisting entries are kept, missing ones are added.\n\nAre you sure?",
+	RestoreInvalidSelection:       "❌ <b>Invalid Selection</b>\n\nPlease use the Confirm button above to proceed with restore or the Return button to cancel.",
+	SessionBackupLost:             "❌ <b>Session Error</b>\n\nBackup data was lost. Please start the restore again.",
+	SessionBackupCorrupted:        "❌ <b>Session Error</b>\n\nBackup data is corrupted. Please start the restore again.",
+	RestoreDone:                   "✅ <b>Restore Complete</b>\n\n• %d trusted users restored\n• %d admins restored\n• %d VPN accounts restored\n• %d member notes, tag sets and passwords restored",
+	RestorePartialErrors:          "\n\n⚠️ <b>Some errors occurred:</b>\n%d entries failed, see logs for details",
+	SubscriptionQRCaption:         "QR code for subscription:",
+	CopyLinkButton:                "📋 Copy Link",
+	CopyLinkText:                  "🔗 <b>Subscription Link</b>\n\n<code>%s</code>",
+	QRCaption:                     "📱 <b>%s</b> · %s",
+	AddMemberDone:                 "🎉 <b>User Created Successfully!</b>\n\nThe new user is ready to connect to the VPN.",
+	HealthFailed:                  "🩺 <b>Health Check Failed</b>\n\n🌐 <b>Panel:</b> <code>%s</code>\n🔑 <b>Login:</b> %s\n",
+	HealthInbounds:                "📡 <b>Inbounds:</b> HTTP %d, %s\n",
+	HealthError:                   "\n<b>Error:</b> %v",
+	HealthPassed:                  "🩺 <b>Health Check Passed</b>\n\n🌐 <b>Panel:</b> <code>%s</code>\n🔑 <b>Login:</b> OK, %s\n📡 <b>Inbounds:</b> HTTP %d, %s (%d inbounds)",
+	TrustedAddPrompt:              "Send @username to add to trusted list:",
+	TrustedNone:                   "No trusted users found.",
+	TrustedSelectRevoke:           "Select user to revoke:",
+	TrustedRevokeFailed:           "Failed to revoke user.",
+	TrustedRevoked:                "User revoked from trusted list.",
+	TrustedInvalidUsername:        "Please send a valid @username:",
+	TrustedAddFailed:              "Failed to add user to trusted list.",
+	TrustedAdded:                  "@%s added to trusted list.",
+	AccountLimitReached:           "You can create maximum %d accounts.",
+	TrustedWelcome:                "Welcome! You are a trusted user.",
+	TrustedMainMenu:               "Main Menu",
+	TelegramUsernameRequired:      "Error: You need to set a Telegram username first. Go to Telegram Settings -> Edit Profile -> Username",
+	AccountCreating:               "Creating account '%s'...",
+	AccountCreateFailed:           "Failed to create account:\n",
+	AccountNoneToRemove:           "You have no accounts to remove.",
+	AccountSelectRemove:           "Select account to remove:",
+	AccountInvalidSelection:       "Invalid account selection.",
+	AccountNotFoundShort:          "Account not found.",
+	AccountNoneToShow:             "You have no accounts yet. Use ➕ to create one.",
+	AccountSelectShow:             "Select an account to get its subscription link and QR code again:",
+	AccountNotOnServer:            "❌ Account <b>%s</b> is incomplete on the server. Please contact the administrator.",
+	AccountGone:                   "❌ Your account <b>%s</b> no longer exists on the server, it was probably deleted by an admin. Contact an admin if you still need it.",
+	AccountResendHeader:           "📋 Account <b>%s</b>\n\n⏳ Expiry: %s\n📦 Traffic: %.2f GB used, limit %s\n",
+	AccountResendPassword:         "🔑 Password: <code>%s</code>\n",
+	AccountResendClients:          "\nClients:\n%s\n\n🔗 Link to connect: %s",
+	MyUsageHeader:                 "📊 <b>Your Usage</b>\n\n",
+	MyUsageLine:                   "👤 <b>%s</b>\n↓ %.2f %s · ↑ %.2f %s\n⏳ %s\n\n",
+	MyUsageMissing:                "👤 <b>%s</b>\n❌ No longer exists on the server, contact an admin\n\n",
+	ExpiryReminder:                "⏰ <b>Expiry Reminder</b>\n\nYour account <b>%s</b> expires on <b>%s</b> (%s). Ask an admin to renew it so it keeps working.",
+	ExpiryReminderOffButton:       "🔕 Stop reminders",
+	RemindersOff:                  "🔕 You won't get expiry reminders anymore. Send /reminders to turn them back on.",
+	RemindersSaveFailed:           "❌ Failed to save your reminder setting. Please try again.",
+	RemindersUnavailable:          "ℹ️ This bot doesn't send expiry reminders.",
+	RemindersOn:                   "🔔 Expiry reminders are on: you get one %d days before an account expires. Send /reminders again to turn them off.",
+	AccountDeleteConfirm:          "🗑️ **Confirm Account Deletion**\n\n⚠️ You are about to permanently delete account **%s**\n\n**This action will:**\n• Remove account from all server configurations\n• Delete all associated data\n• Cannot be undone\n\nAre you absolutely sure?",
+	AccountDeleteInvalidSelection: "❌ **Invalid Selection**\n\nPlease click Confirm to proceed with deletion or use the Return button to cancel.",
+	SessionAccountLost:            "❌ **Session Error**\n\nAccount data was lost. Please start the deletion process again.",
+	AccountInvalidID:              "❌ **Invalid Account ID**\n\nPlease start the deletion process again.",
+	AccountNotFound:               "❌ **Account Not Found**\n\nThe account may have already been deleted.",
+	AccountDeleteInProgress:       "⏳ **Deleting Account...**\n\nRemoving account '%s' from all server configurations. Please wait...",
+	AccountDeleteFailed:           "❌ **Deletion Failed**\n\nCouldn't delete account '%s' from server configurations.\n\n**Error:** %v\n\nPlease try again or contact administrator.",
+	AccountDeletePartial:          "⚠️ **Partial Success**\n\nAccount deleted from server but failed to update database:\n%v",
+	AccountDeleteDone:             "✅ **Account Deleted Successfully**\n\n🗑️ Account '%s' has been permanently removed from all server configurations.",
+	AccountDeleteGone:             "✅ **Account Removed**\n\n🗑️ Account '%s' no longer existed on the server, so it was only removed from your list.",
+	MemberNoAccount:               "❌ No account linked to your Telegram ID exists on the server. If you had one, it was probably deleted by an admin. Contact an admin if you still need it.",
+	MemberSubscriptionURL:         "Your subscription URL:\n\n%s",
+	MemberSubscriptionFailed:      "Failed to get subscription URL: %s",
+	MemberInboundsFailed:          "Failed to get inbounds: %s",
+	ServerSelectionAutomatic:      "Server configuration is handled automatically.",
+	WhoAmI:                        "🪪 <b>Who Am I</b>\n\n👤 <b>Username:</b> %s\n🆔 <b>Telegram ID:</b> <code>%d</code>\n🔐 <b>Access:</b> %s",
+	WhoAmIRequestAccess:           "\n\nSend your Telegram ID to an administrator to request access.",
+	NoAccessYourID:                "\n\n🆔 Your Telegram ID: <code>%d</code>\nSend it to an administrator to request access.",
+	SupportContactLine:            "\n\n💬 <b>Support:</b> %s",
+	SupportHeader:                 "💬 <b>Support</b>\n\nContact: %s",
+	SupportNotConfigured:          "💬 <b>Support</b>\n\nNo support contact is configured. Please reach out to the administrator of this bot.",
+	SupportYourID:                 "\n\n🆔 Your Telegram ID: <code>%d</code>",
+	VersionInfo:                   "🏷 <b>Version</b>\n\n<b>Version:</b> <code>%s</code>\n<b>Commit:</b> <code>%s</code>\n<b>Built:</b> %s",
+	ActingAsUsage:                 "🎭 <b>Act As</b>\n\nSee the bot as another access level:\n<code>/as trusted</code> — the trusted user menus\n<code>/as none</code> — what users without access see\n<code>/as admin</code> — back to your admin view\n\n<b>Current:</b> %s",
+	ActingAsStarted:               "🎭 <b>Acting as %s</b>\n\nThe bot now treats you as %s until you send <code>/as admin</code> or your session expires. What you do is real: accounts created here end up on the panel.",
+	ActingAsStopped:               "🎭 Back to your admin view.",
+	ActingAsBanner:                "\n\n🎭 <i>Acting as %s. Send /as admin to return.</i>",
+	ActingAsAdminOnly:             "❌ /as is only available to admins.",
+	NoPermission:                  "You don't have permission to use this bot.",
+	TextExpected:                  "✍️ Please send text. Photos, stickers and other media can't be used here.",
+	PanelUnhealthyAlert:           "🚨 <b>Panel Seems Unhealthy</b>\n\nOperation <code>%s</code> failed <b>%d times</b> in the last %d min.\n\n<b>Last error:</b> %s",
+	InputTooLong:                  "⚠️ <b>Message Too Long</b>\n\nPlease keep messages under %d characters.",
+	GenericError:                  "An error occurred. Please try again later.",
+	RateLimited:                   "⏳ Please slow down",
+
+	// Connection links
+	ConnectionHeader:          "🔌 <b>Connection Links for %s</b>\n\n",
+	ConnectionLine:            "📡 <b>%s</b> · %s :%d\n<code>%s</code>\n\n",
+	ConnectionUnsupported:     "📡 <b>%s</b> · %s\n<i>Not supported yet, use the subscription link.</i>\n\n",
+	ConnectionFailed:          "📡 <b>%s</b> · %s\n<i>Couldn't read the inbound settings.</i>\n\n",
+	ConfigExportTitle:         "%s — %s\n\n",
+	ConfigExportSubscription:  "Subscription link (import it or scan the QR code):\n%s\n\n",
+	ConfigExportInbound:       "[%s] %s, port %d\n%s\n\n",
+	ConfigExportUnsupported:   "Not supported yet, use the subscription link.",
+	ConfigExportInboundFailed: "Couldn't read the inbound settings.",
+	ConfigExportCaption:       "📦 Config for %s: subscription link, connection details and QR code",
+	ConfigExportFailed:        "❌ <b>Export Failed</b>\n\nCouldn't build the config archive for <b>%s</b>. Please try again.",
+
+	// Subscription reconciliation
+	SubIDMismatch:       "⚠️ <b>Subscription Mismatch</b>\n\nUser <b>%s</b> has clients with %d different subscription IDs, so the link above doesn't cover every inbound:\n%s\nUnify them under <code>%s</code>?",
+	SubIDMismatchLine:   "• <code>%s</code> — %d\n",
+	SubIDUnifyButton:    "🔗 Unify Subscription",
+	SubIDUnified:        "✅ <b>Subscription Unified</b>\n\n🔗 %d clients of <b>%s</b> now use <code>%s</code>",
+	SubIDAlreadyUnified: "✅ <b>Nothing to Unify</b>\n\nAll clients of <b>%s</b> already share one subscription ID.",
+	SubIDUnifyFailed:    "❌ <b>Unify Failed</b>\n\nCouldn't update the clients of '%s'.",
+
+	// Runtime admins
+	AdminAddPrompt:         "👑 <b>Add Admin</b>\n\nSend the Telegram ID (e.g. <code>123456789</code>) or @username of the new admin:\n\n<i>Users can find their ID with /whoami</i>",
+	AdminInvalidIdentifier: "❌ <b>Invalid Input</b>\n\nPlease send a numeric Telegram ID or an @username:",
+	AdminAlreadyAdmin:      "ℹ️ <b>Already an Admin</b>\n\n%s already has admin rights.",
+	AdminAddFailed:         "❌ <b>Failed to Add Admin</b>\n\nCouldn't save the new admin. Please try again later.",
+	AdminAdded:             "✅ <b>Admin Added</b>\n\n👑 %s now has admin rights.",
+	AdminNoneToRevoke:      "📭 <b>No Runtime Admins</b>\n\nThere are no admins added through the bot. Admins from TG_ADMIN_IDS can only be removed by changing the configuration.",
+	AdminSelectRevoke:      "❎ <b>Revoke Admin</b>\n\nSelect an admin to revoke:",
+	AdminLastAdmin:         "❌ <b>Cannot Revoke</b>\n\nAt least one admin must remain.",
+	AdminRevokeFailed:      "❌ <b>Failed to Revoke Admin</b>\n\nCouldn't update the admin list. Please try again later.",
+	AdminRevoked:           "✅ <b>Admin Revoked</b>\n\n%s no longer has admin rights.",
+	AdminNotFound:          "❌ <b>Admin Not Found</b>\n\nThe admin may have already been revoked.",
+
+	// Sort and expiry labels
+	SortByCreationOrder: "📅 By date added",
+	SortByExpiryDate:    "⏰ By expiry date",
+	SortByTrafficTotal:  "📊 By total traffic",
+	SortByStatus:        "🔄 By status",
+	SortByName:          "🔤 By name",
+	ExpiryNever:         "∞ Unlimited",
+	ExpiryExpired:       "❌ Expired",
+	ExpiryToday:         "⚠️ Expires today",
+	ExpiryDaysLeftSoon:  "⚠️ %d d.",
+	ExpiryDaysLeft:      "✅ %d d.",
+}
@@ -0,0 +1,74 @@
+// Package i18n provides the message catalog for user-facing bot text
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language identifies a supported message catalog
+type Language string
+
+const (
+	// English is the default language
+	English Language = "en"
+	// Russian language
+	Russian Language = "ru"
+)
+
+// Key identifies a message in the catalog
+type Key string
+
+var catalogs = map[Language]map[Key]string{
+	English: english,
+	Russian: russian,
+}
+
+// ParseLanguage parses a language code such as "ru" or "ru_RU.UTF-8".
+// It returns false if the language has no catalog.
+func ParseLanguage(raw string) (Language, bool) {
+	code := strings.ToLower(strings.TrimSpace(raw))
+	if i := strings.IndexAny(code, "_-."); i >= 0 {
+		code = code[:i]
+	}
+
+	lang := Language(code)
+	if _, ok := catalogs[lang]; !ok {
+		return English, false
+	}
+	return lang, true
+}
+
+// Localizer renders catalog messages in a single language
+type Localizer struct {
+	lang Language
+}
+
+// NewLocalizer creates a localizer for the given language, falling back to English
+func NewLocalizer(lang Language) *Localizer {
+	if _, ok := catalogs[lang]; !ok {
+		lang = English
+	}
+	return &Localizer{lang: lang}
+}
+
+// Language returns the localizer's language
+func (l *Localizer) Language() Language {
+	return l.lang
+}
+
+// T returns the message for key, formatted with args if any.
+// Messages missing from the catalog fall back to English, then to the key itself.
+func (l *Localizer) T(key Key, args ...interface{}) string {
+	msg, ok := catalogs[l.lang][key]
+	if !ok {
+		if msg, ok = english[key]; !ok {
+			msg = string(key)
+		}
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
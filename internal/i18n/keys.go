@@ -0,0 +1,349 @@
+package i18n
+
+// Message keys
+const (
+	AdminWelcome                  Key = "admin.welcome"
+	MainMenu                      Key = "common.main_menu"
+	AddMemberPrompt               Key = "member.add.prompt"
+	OnlineConnectionError         Key = "online.connection_error"
+	OnlineNone                    Key = "online.none"
+	OnlineHeader                  Key = "online.header"
+	OnlineMemberLine              Key = "online.member_line"
+	OnlineRecentHeader            Key = "online.recent_header"
+	OnlineRecentLine              Key = "online.recent_line"
+	AgoJustNow                    Key = "ago.just_now"
+	AgoMinutes                    Key = "ago.minutes"
+	AgoHours                      Key = "ago.hours"
+	UsageConnectionError          Key = "usage.connection_error"
+	ResetAllConfirm               Key = "reset_all.confirm"
+	AddMemberInvalidUsername      Key = "member.add.invalid_username"
+	AddMemberDurationPrompt       Key = "member.add.duration_prompt"
+	SessionUsernameLost           Key = "session.username_lost"
+	NoEnabledInbounds             Key = "server.no_enabled_inbounds"
+	NoEnabledInboundsHint         Key = "server.no_enabled_inbounds_hint"
+	NoEnabledInboundsAlert        Key = "server.no_enabled_inbounds_alert"
+	MalformedInboundsAlert        Key = "server.malformed_inbounds_alert"
+	AddMemberInvalidDuration      Key = "member.add.invalid_duration"
+	AddMemberCreating             Key = "member.add.creating"
+	AddMemberFailed               Key = "member.add.failed"
+	AddMemberVerifyFailed         Key = "member.add.verify_failed"
+	AddMemberVerifyMissing        Key = "member.add.verify_missing"
+	AddMemberRetryButton          Key = "member.add.retry_button"
+	AddMemberRetryExpired         Key = "member.add.retry_expired"
+	ManageMember                  Key = "member.manage"
+	MemberTags                    Key = "member.tags"
+	TagsPrompt                    Key = "tags.prompt"
+	TagsNone                      Key = "tags.none"
+	TagsInvalidInput              Key = "tags.invalid_input"
+	TagsInvalidTag                Key = "tags.invalid_tag"
+	TagsSaveFailed                Key = "tags.save_failed"
+	TagsSaved                     Key = "tags.saved"
+	TagFilterActive               Key = "tags.filter_active"
+	UsageTagHeader                Key = "usage.tag_header"
+	MemberPassword                Key = "member.password"
+	MemberNote                    Key = "member.note"
+	NotePrompt                    Key = "note.prompt"
+	NoteCurrent                   Key = "note.current"
+	NoteEmpty                     Key = "note.empty"
+	NoteSaveFailed                Key = "note.save_failed"
+	NoteSaved                     Key = "note.saved"
+	NoteCleared                   Key = "note.cleared"
+	TrafficLimitPrompt            Key = "traffic_limit.prompt"
+	TrafficLimitInvalid           Key = "traffic_limit.invalid"
+	TrafficLimitSet               Key = "traffic_limit.set"
+	TrafficLimitFailed            Key = "traffic_limit.failed"
+	TrafficLimitUnlimited         Key = "traffic_limit.unlimited"
+	SessionUserLost               Key = "session.user_lost"
+	InvalidAction                 Key = "member.invalid_action"
+	ViewConfigInboundsFailed      Key = "member.config.inbounds_failed"
+	MemberNotFound                Key = "member.not_found"
+	MemberSettingsUnreadable      Key = "member.settings_unreadable"
+	MemberConfig                  Key = "member.config"
+	SubscriptionDead              Key = "config.subscription_dead"
+	SubscriptionConfigCount       Key = "config.subscription_config_count"
+	ResetTrafficChooseInbound     Key = "reset.choose_inbound"
+	ResetNotifyButton             Key = "reset_notify.button"
+	ResetNotifyMessage            Key = "reset_notify.message"
+	ResetNotifyDone               Key = "reset_notify.done"
+	ResetNotifyFailed             Key = "reset_notify.failed"
+	ResetNotifyNoRecipients       Key = "reset_notify.no_recipients"
+	ResetTrafficAllButton         Key = "reset.all_button"
+	ResetTrafficInProgress        Key = "member.reset.in_progress"
+	PanelAuthError                Key = "server.panel_auth_error"
+	PanelServerError              Key = "server.panel_server_error"
+	PanelErrDuplicateEmail        Key = "server.panel_err_duplicate_email"
+	PanelErrClientNotFound        Key = "server.panel_err_client_not_found"
+	PanelErrInboundNotFound       Key = "server.panel_err_inbound_not_found"
+	PanelErrPortInUse             Key = "server.panel_err_port_in_use"
+	PanelErrUnreachable           Key = "server.panel_err_unreachable"
+	PanelErrAuth                  Key = "server.panel_err_auth"
+	PanelErrServer                Key = "server.panel_err_server"
+	PanelErrUnknown               Key = "server.panel_err_unknown"
+	PanelErrInbound               Key = "server.panel_err_inbound"
+	OperationStillWorking         Key = "server.operation_still_working"
+	OperationTimedOut             Key = "server.operation_timed_out"
+	ServerDataConnectionError     Key = "server.connection_error"
+	ResetTrafficDone              Key = "member.reset.done"
+	SomeErrorsOccurred            Key = "common.some_errors"
+	ResetTrafficFailed            Key = "member.reset.failed"
+	ErrorsList                    Key = "common.errors"
+	RenamePrompt                  Key = "member.rename.prompt"
+	RenameInvalidUsername         Key = "member.rename.invalid_username"
+	RenameSameUsername            Key = "member.rename.same_username"
+	UserListConnectionError       Key = "users.connection_error"
+	UsernameTaken                 Key = "member.username_taken"
+	MemberAlreadyExists           Key = "member.already_exists"
+	RenameInProgress              Key = "member.rename.in_progress"
+	RenameFailed                  Key = "member.rename.failed"
+	RenameDone                    Key = "member.rename.done"
+	DeleteConfirm                 Key = "member.delete.confirm"
+	DeleteInvalidSelection        Key = "member.delete.invalid_selection"
+	SessionDeleteLost             Key = "session.delete_lost"
+	DeleteInProgress              Key = "member.delete.in_progress"
+	DeleteFailed                  Key = "member.delete.failed"
+	DeleteDone                    Key = "member.delete.done"
+	ExpiredNone                   Key = "expired.none"
+	ExpiredMemberLine             Key = "expired.member_line"
+	ExpiredConfirm                Key = "expired.confirm"
+	ExpiredInvalidSelection       Key = "expired.invalid_selection"
+	ExpiredDeleteInProgress       Key = "expired.delete_in_progress"
+	ExpiredDeleteFailed           Key = "expired.delete_failed"
+	ExpiredDeleteDone             Key = "expired.delete_done"
+	ReconcileClean                Key = "reconcile.clean"
+	ReconcileHeader               Key = "reconcile.header"
+	ReconcileOrphanedHeader       Key = "reconcile.orphaned_header"
+	ReconcileUntrackedHeader      Key = "reconcile.untracked_header"
+	ReconcileAccountLine          Key = "reconcile.account_line"
+	ReconcilePruneQuestion        Key = "reconcile.prune_question"
+	ReconcileInvalidSelection     Key = "reconcile.invalid_selection"
+	ReconcilePruneFailed          Key = "reconcile.prune_failed"
+	ReconcilePruneDone            Key = "reconcile.prune_done"
+	BulkSelectPrompt              Key = "bulk.select_prompt"
+	BulkReturnHint                Key = "bulk.return_hint"
+	BulkUseButtons                Key = "bulk.use_buttons"
+	BulkEnableButton              Key = "bulk.enable_button"
+	BulkDisableButton             Key = "bulk.disable_button"
+	BulkNothingSelected           Key = "bulk.nothing_selected"
+	BulkActionEnabled             Key = "bulk.action_enabled"
+	BulkActionDisabled            Key = "bulk.action_disabled"
+	BulkResultOK                  Key = "bulk.result_ok"
+	BulkResultFailed              Key = "bulk.result_failed"
+	BulkNoClients                 Key = "bulk.no_clients"
+	BulkInProgress                Key = "bulk.in_progress"
+	BulkResultHeader              Key = "bulk.result_header"
+	BulkPageIndicator             Key = "bulk.page_indicator"
+	DetailedUsageConnectionError  Key = "usage.detailed_connection_error"
+	UsageInboundHeader            Key = "usage.inbound_header"
+	UsageInboundNotFound          Key = "usage.inbound_not_found"
+	UsageAllInboundsButton        Key = "usage.all_inbounds_button"
+	UsageActiveOnlyHeader         Key = "usage.active_only_header"
+	UsageActiveOnlyButton         Key = "usage.active_only_button"
+	UsageShowAllButton            Key = "usage.show_all_button"
+	FindInboundsUsage             Key = "find_inbounds.usage"
+	FindInboundsHeader            Key = "find_inbounds.header"
+	FindInboundsLine              Key = "find_inbounds.line"
+	FindInboundsMissingHeader     Key = "find_inbounds.missing_header"
+	FindInboundsMissingLine       Key = "find_inbounds.missing_line"
+	FindInboundsExcludedHeader    Key = "find_inbounds.excluded_header"
+	InboundsOverviewHeader        Key = "inbounds_overview.header"
+	InboundsOverviewEmpty         Key = "inbounds_overview.empty"
+	InboundsOverviewExcluded      Key = "inbounds_overview.excluded"
+	InboundsOverviewMalformed     Key = "inbounds_overview.malformed"
+	InboundTogglePrompt           Key = "inbound_toggle.prompt"
+	InboundToggleNotFound         Key = "inbound_toggle.not_found"
+	InboundToggleConfirmDisable   Key = "inbound_toggle.confirm_disable"
+	InboundToggleConfirmEnable    Key = "inbound_toggle.confirm_enable"
+	InboundToggleInvalidSelection Key = "inbound_toggle.invalid_selection"
+	InboundToggleFailed           Key = "inbound_toggle.failed"
+	InboundToggleDisabled         Key = "inbound_toggle.disabled"
+	InboundToggleEnabled          Key = "inbound_toggle.enabled"
+	TransferUsage                 Key = "transfer.usage"
+	TransferAccountNotFound       Key = "transfer.account_not_found"
+	TransferAccountAmbiguous      Key = "transfer.account_ambiguous"
+	TransferTargetNotTrusted      Key = "transfer.target_not_trusted"
+	TransferSameOwner             Key = "transfer.same_owner"
+	TransferLimitReached          Key = "transfer.limit_reached"
+	TransferFailed                Key = "transfer.failed"
+	TransferDone                  Key = "transfer.done"
+	TransferPanelFailed           Key = "transfer.panel_failed"
+	BroadcastPrompt               Key = "broadcast.prompt"
+	BroadcastEmpty                Key = "broadcast.empty"
+	BroadcastNoRecipients         Key = "broadcast.no_recipients"
+	BroadcastConfirm              Key = "broadcast.confirm"
+	BroadcastOwnersHint           Key = "broadcast.owners_hint"
+	BroadcastSendButton           Key = "broadcast.send_button"
+	BroadcastOwnersButton         Key = "broadcast.owners_button"
+	BroadcastInvalidSelection     Key = "broadcast.invalid_selection"
+	BroadcastInProgress           Key = "broadcast.in_progress"
+	BroadcastDone                 Key = "broadcast.done"
+	BroadcastFailedLine           Key = "broadcast.failed_line"
+	TopUsersHeader                Key = "top.header"
+	TopUsersLine                  Key = "top.line"
+	TopUsersInvalidCount          Key = "top.invalid_count"
+	ExportConnectionError         Key = "export.connection_error"
+	ExportNoUsers                 Key = "export.no_users"
+	ExportBuildFailed             Key = "export.build_failed"
+	ExportCaption                 Key = "export.caption"
+	ExportSendFailed              Key = "export.send_failed"
+	ExportDone                    Key = "export.done"
+	ChartConnectionError          Key = "chart.connection_error"
+	ChartNoUsers                  Key = "chart.no_users"
+	ChartRenderFailed             Key = "chart.render_failed"
+	ChartCaptionHeader            Key = "chart.caption_header"
+	ChartLegendLine               Key = "chart.legend_line"
+	ChartSendFailed               Key = "chart.send_failed"
+	ConfirmationExpired           Key = "confirm.expired"
+	ResetAllInvalidSelection      Key = "reset_all.invalid_selection"
+	ResetAllInProgress            Key = "reset_all.in_progress"
+	ResetAllProgress              Key = "reset_all.progress"
+	ResetAllConnectionError       Key = "reset_all.connection_error"
+	ResetAllNoUsers               Key = "reset_all.no_users"
+	ResetAllCountChanged          Key = "reset_all.count_changed"
+	ResetAllDone                  Key = "reset_all.done"
+	ResetAllFailed                Key = "reset_all.failed"
+	NoUsersYet                    Key = "users.none_yet"
+	NoUsersYetHint                Key = "users.none_yet_hint"
+	SelectMemberToEdit            Key = "users.select_edit"
+	SelectMemberToDelete          Key = "users.select_delete"
+	InvalidSelectionShort         Key = "common.invalid_selection"
+	UnknownAction                 Key = "common.unknown_action"
+	BackupConnectionError         Key = "backup.connection_error"
+	BackupBuildFailed             Key = "backup.build_failed"
+	BackupCaption                 Key = "backup.caption"
+	BackupSendFailed              Key = "backup.send_failed"
+	BackupDone                    Key = "backup.done"
+	RestorePrompt                 Key = "restore.prompt"
+	RestoreUploadDocument         Key = "restore.upload_document"
+	RestoreDownloadFailed         Key = "restore.download_failed"
+	RestoreReadFailed             Key = "restore.read_failed"
+	RestoreInvalidBackup          Key = "restore.invalid_backup"
+	RestoreConfirm                Key = "restore.confirm"
+	RestoreInvalidSelection       Key = "restore.invalid_selection"
+	SessionBackupLost             Key = "session.backup_lost"
+	SessionBackupCorrupted        Key = "session.backup_corrupted"
+	RestoreDone                   Key = "restore.done"
+	RestorePartialErrors          Key = "restore.partial_errors"
+	SubscriptionQRCaption         Key = "subscription.qr_caption"
+	CopyLinkButton                Key = "subscription.copy_link_button"
+	CopyLinkText                  Key = "subscription.copy_link_text"
+	QRCaption                     Key = "subscription.qr_label"
+	AddMemberDone                 Key = "member.add.done"
+	HealthFailed                  Key = "health.failed"
+	HealthInbounds                Key = "health.inbounds"
+	HealthError                   Key = "health.error"
+	HealthPassed                  Key = "health.passed"
+	TrustedAddPrompt              Key = "trusted.add.prompt"
+	TrustedNone                   Key = "trusted.none"
+	TrustedSelectRevoke           Key = "trusted.select_revoke"
+	TrustedRevokeFailed           Key = "trusted.revoke_failed"
+	TrustedRevoked                Key = "trusted.revoked"
+	TrustedInvalidUsername        Key = "trusted.invalid_username"
+	TrustedAddFailed              Key = "trusted.add_failed"
+	TrustedAdded                  Key = "trusted.added"
+	AccountLimitReached           Key = "account.limit_reached"
+	TrustedWelcome                Key = "trusted.welcome"
+	TrustedMainMenu               Key = "trusted.main_menu"
+	TelegramUsernameRequired      Key = "account.username_required"
+	AccountCreating               Key = "account.creating"
+	AccountCreateFailed           Key = "account.create_failed"
+	AccountNoneToRemove           Key = "account.none_to_remove"
+	AccountSelectRemove           Key = "account.select_remove"
+	AccountInvalidSelection       Key = "account.invalid_selection"
+	AccountNotFoundShort          Key = "account.not_found_short"
+	AccountNoneToShow             Key = "account.none_to_show"
+	AccountSelectShow             Key = "account.select_show"
+	AccountNotOnServer            Key = "account.not_on_server"
+	AccountGone                   Key = "account.gone"
+	AccountResendHeader           Key = "account.resend_header"
+	AccountResendPassword         Key = "account.resend_password"
+	AccountResendClients          Key = "account.resend_clients"
+	MyUsageHeader                 Key = "account.usage_header"
+	MyUsageLine                   Key = "account.usage_line"
+	MyUsageMissing                Key = "account.usage_missing"
+	ExpiryReminder                Key = "account.expiry_reminder"
+	ExpiryReminderOffButton       Key = "account.expiry_reminder_off_button"
+	RemindersOff                  Key = "account.reminders_off"
+	RemindersOn                   Key = "account.reminders_on"
+	RemindersSaveFailed           Key = "account.reminders_save_failed"
+	RemindersUnavailable          Key = "account.reminders_unavailable"
+	AccountDeleteConfirm          Key = "account.delete.confirm"
+	AccountDeleteInvalidSelection Key = "account.delete.invalid_selection"
+	SessionAccountLost            Key = "session.account_lost"
+	AccountInvalidID              Key = "account.invalid_id"
+	AccountNotFound               Key = "account.not_found"
+	AccountDeleteInProgress       Key = "account.delete.in_progress"
+	AccountDeleteFailed           Key = "account.delete.failed"
+	AccountDeletePartial          Key = "account.delete.partial"
+	AccountDeleteDone             Key = "account.delete.done"
+	AccountDeleteGone             Key = "account.delete.gone"
+	MemberNoAccount               Key = "member.no_account"
+	MemberSubscriptionURL         Key = "member.subscription_url"
+	MemberSubscriptionFailed      Key = "member.subscription_failed"
+	MemberInboundsFailed          Key = "member.inbounds_failed"
+	ServerSelectionAutomatic      Key = "server.selection_automatic"
+	WhoAmI                        Key = "whoami"
+	WhoAmIRequestAccess           Key = "whoami.request_access"
+	NoAccessYourID                Key = "common.no_access_your_id"
+	SupportContactLine            Key = "support.contact_line"
+	SupportHeader                 Key = "support.header"
+	SupportNotConfigured          Key = "support.not_configured"
+	SupportYourID                 Key = "support.your_id"
+	VersionInfo                   Key = "version.info"
+	ActingAsUsage                 Key = "acting_as.usage"
+	ActingAsStarted               Key = "acting_as.started"
+	ActingAsStopped               Key = "acting_as.stopped"
+	ActingAsBanner                Key = "acting_as.banner"
+	ActingAsAdminOnly             Key = "acting_as.admin_only"
+	NoPermission                  Key = "common.no_permission"
+	TextExpected                  Key = "common.text_expected"
+	PanelUnhealthyAlert           Key = "alert.panel_unhealthy"
+	InputTooLong                  Key = "common.input_too_long"
+	GenericError                  Key = "common.generic_error"
+	RateLimited                   Key = "common.rate_limited"
+
+	// Connection links
+	ConnectionHeader          Key = "connection.header"
+	ConnectionLine            Key = "connection.line"
+	ConnectionUnsupported     Key = "connection.unsupported"
+	ConnectionFailed          Key = "connection.failed"
+	ConfigExportTitle         Key = "config_export.title"
+	ConfigExportSubscription  Key = "config_export.subscription"
+	ConfigExportInbound       Key = "config_export.inbound"
+	ConfigExportUnsupported   Key = "config_export.unsupported"
+	ConfigExportInboundFailed Key = "config_export.inbound_failed"
+	ConfigExportCaption       Key = "config_export.caption"
+	ConfigExportFailed        Key = "config_export.failed"
+
+	// Subscription reconciliation
+	SubIDMismatch       Key = "subscription.mismatch"
+	SubIDMismatchLine   Key = "subscription.mismatch_line"
+	SubIDUnifyButton    Key = "subscription.unify_button"
+	SubIDUnified        Key = "subscription.unified"
+	SubIDAlreadyUnified Key = "subscription.already_unified"
+	SubIDUnifyFailed    Key = "subscription.unify_failed"
+
+	// Runtime admins
+	AdminAddPrompt         Key = "admin.add.prompt"
+	AdminInvalidIdentifier Key = "admin.add.invalid_identifier"
+	AdminAlreadyAdmin      Key = "admin.add.already_admin"
+	AdminAddFailed         Key = "admin.add.failed"
+	AdminAdded             Key = "admin.add.done"
+	AdminNoneToRevoke      Key = "admin.revoke.none"
+	AdminSelectRevoke      Key = "admin.revoke.select"
+	AdminLastAdmin         Key = "admin.revoke.last_admin"
+	AdminRevokeFailed      Key = "admin.revoke.failed"
+	AdminRevoked           Key = "admin.revoke.done"
+	AdminNotFound          Key = "admin.revoke.not_found"
+
+	// Sort and expiry labels
+	SortByCreationOrder Key = "sort.creation_order"
+	SortByExpiryDate    Key = "sort.expiry_date"
+	SortByTrafficTotal  Key = "sort.traffic_total"
+	SortByStatus        Key = "sort.status"
+	SortByName          Key = "sort.name"
+	ExpiryNever         Key = "expiry.never"
+	ExpiryExpired       Key = "expiry.expired"
+	ExpiryToday         Key = "expiry.today"
+	ExpiryDaysLeftSoon  Key = "expiry.days_left_soon"
+	ExpiryDaysLeft      Key = "expiry.days_left"
+)
@@ -0,0 +1,349 @@
+package i18n
+
+// russian is the Russian message catalog
+var russian = map[Key]string{
+	AdminWelcome:                  "🚀 <b>Добро пожаловать в панель X-UI!</b>\n\nУ вас права администратора. Используйте меню ниже, чтобы управлять VPN-пользователями, следить за подключениями и настраивать бота.",
+	MainMenu:                      "🏠 <b>Главное меню</b>\n\nВыберите действие:",
+	AddMemberPrompt:               "👤 <b>Новый пользователь</b>\n\n📝 Введите имя для нового пользователя:\n\n<i>• Только буквы, цифры и подчёркивания\n• От 3 до 20 символов\n• Пример: john_doe, user123</i>",
+	OnlineConnectionError:         "❌ <b>Ошибка подключения</b>\n\nНе удалось получить список пользователей онлайн. Проверьте подключение к серверу и попробуйте снова.",
+	OnlineNone:                    "💤 <b>Нет активных подключений</b>\n\nСейчас к VPN-серверу никто не подключён.",
+	OnlineHeader:                  "🟢 <b>Активные подключения (%d)</b>\n\n",
+	OnlineMemberLine:              "👤 <b>%s</b> · подключений: %d · ↓ %.2f %s ↑ %.2f %s\n",
+	OnlineRecentHeader:            "\n🕓 <b>Были в сети за последние %d ч</b>\n\n",
+	OnlineRecentLine:              "⚪ %s · %s\n",
+	AgoJustNow:                    "только что",
+	AgoMinutes:                    "%d мин назад",
+	AgoHours:                      "%d ч назад",
+	UsageConnectionError:          "❌ <b>Ошибка подключения</b>\n\nНе удалось получить данные об использовании сети. Проверьте подключение к серверу и попробуйте снова.",
+	ResetAllConfirm:               "⚠️ <b>Сброс всего трафика</b>\n\nБудет сброшена статистика трафика для:\n\n👥 <b>%d пользователей</b> (клиентов: %d)\n📡 <b>%d подключений</b>\n📊 <b>%.2f %s</b> учтённого трафика\n\n<b>⚠️ Это действие нельзя отменить!</b>\n\nВы уверены, что хотите продолжить?",
+	AddMemberInvalidUsername:      "❌ <b>Недопустимое имя</b>\n\n%s\n\n💡 <b>Требования:</b>\n• От 3 до 20 символов\n• Только буквы, цифры и подчёркивания\n• Пример: john_doe, user123\n\nПопробуйте снова:",
+	AddMemberDurationPrompt:       "⏰ <b>Срок действия для %s</b>\n\n📅 Введите срок подписки в днях:\n\n<i>• Пример: 30 (на 30 дней)\n• Максимум: %d дн.\n• Или выберите готовый срок ниже либо Infinite для бессрочной подписки</i>",
+	SessionUsernameLost:           "❌ <b>Ошибка сессии</b>\n\nДанные об имени пользователя потеряны. Начните заново.",
+	NoEnabledInbounds:             "❌ <b>Нет включённых подключений</b>\n\nНа сервере нет ни одного включённого подключения, поэтому новые конфигурации сейчас создать нельзя. Администраторы уведомлены.",
+	MalformedInboundsAlert:        "⚠️ <b>Настройки подключения не читаются</b>\n\nНе удаётся разобрать настройки подключения %s. Его пользователи по-прежнему видны по статистике трафика, но их ссылки на подписку и лимиты боту неизвестны. Проверьте подключение в панели X-UI.",
+	NoEnabledInboundsHint:         "\n\n💡 Включите хотя бы одно подключение кнопкой %s или в панели X-UI и повторите попытку. <code>/inbounds</code> покажет, какие отключены.",
+	NoEnabledInboundsAlert:        "⚠️ <b>Нет включённых подключений</b>\n\nНа панели подключений: %d, и ни одно не включено и не разрешено INBOUNDS_INCLUDE/INBOUNDS_EXCLUDE одновременно, поэтому новых пользователей создать нельзя.",
+	AddMemberInvalidDuration:      "❌ <b>Недопустимый срок</b>\n\n%s\n\n💡 <b>Допустимые значения:</b>\n• Число: 30 (на 30 дней)\n• Диапазон: 1-%d дн.\n• Или кнопка Infinite\n\nПопробуйте снова:",
+	AddMemberCreating:             "⏳ <b>Создание пользователя...</b>\n\nПодождите, пока мы настроим нового пользователя на всех серверах.",
+	AddMemberFailed:               "❌ <b>Не удалось создать пользователя</b>\n\nНе удалось создать пользователя '%s' ни в одной конфигурации сервера.\n\n<b>Ошибки:</b>\n%s\n\nПроверьте конфигурацию сервера или попробуйте позже.",
+	AddMemberVerifyFailed:         "Не удалось проверить созданных клиентов: %s",
+	AddMemberVerifyMissing:        "%s: панель сообщила об успехе, но клиента нет",
+	AddMemberRetryButton:          "🔁 Повторить",
+	AddMemberRetryExpired:         "⌛ <b>Повтор недоступен</b>\n\nВремя для повтора истекло. Добавьте пользователя заново.",
+	ManageMember:                  "👤 <b>Управление пользователем: %s</b>\n\n🎛️ Выберите действие:",
+	MemberTags:                    "\n\n🏷 <b>Теги:</b> %s",
+	TagsPrompt:                    "🏷 <b>Теги для %s</b>\n\n<b>Сейчас:</b> %s\n\nОтправьте <code>+тег</code>, чтобы добавить, и <code>-тег</code>, чтобы удалить; несколько — через пробел.\n\n<i>• Пример: +vip -trial</i>",
+	TagsNone:                      "нет",
+	TagsInvalidInput:              "❌ <b>Неверный ввод</b> %s\n\nНачинайте каждый тег с <code>+</code>, чтобы добавить, или с <code>-</code>, чтобы удалить:",
+	TagsInvalidTag:                "❌ <b>Недопустимый тег</b> %s\n\n%s\n\nПопробуйте снова:",
+	TagsSaveFailed:                "❌ <b>Не удалось сохранить теги</b>\n\nОшибка записи в хранилище. Попробуйте снова.",
+	TagsSaved:                     "✅ Теги для <b>%s</b> обновлены",
+	TagFilterActive:               "\n\n🏷 Показаны пользователи с тегом <b>#%s</b>",
+	UsageTagHeader:                "🏷 <b>#%s</b>\n\n",
+	MemberPassword:                "\n\n🔑 <b>Пароль:</b> <code>%s</code>",
+	MemberNote:                    "\n\n📝 <b>Заметка:</b> %s",
+	NotePrompt:                    "📝 <b>Заметка для %s</b>\n\nОтправьте текст заметки или <code>-</code>, чтобы удалить её.",
+	NoteCurrent:                   "\n\n<b>Текущая заметка:</b> %s",
+	NoteEmpty:                     "❌ <b>Пустая заметка</b>\n\nОтправьте текст заметки или <code>-</code>, чтобы удалить её:",
+	NoteSaveFailed:                "❌ <b>Не удалось сохранить заметку</b>\n\nОшибка записи в хранилище. Попробуйте снова.",
+	NoteSaved:                     "✅ Заметка для <b>%s</b> сохранена",
+	TrafficLimitPrompt:            "📶 <b>Лимит трафика для %s</b>\n\n<b>Текущий лимит:</b> %s\n\nОтправьте новый лимит в ГБ, например <code>50</code> или <code>2.5</code>, или <code>0</code> для безлимита. Уже израсходованный трафик сохранится.",
+	TrafficLimitInvalid:           "❌ <b>Неверный лимит трафика</b>\n\n%s\n\nОтправьте лимит в ГБ или <code>0</code> для безлимита:",
+	TrafficLimitSet:               "✅ Лимит трафика <b>%s</b> установлен: <b>%s</b>, клиентов: %d",
+	TrafficLimitFailed:            "❌ <b>Не удалось обновить</b>\n\nНе получилось изменить лимит трафика '%s'.",
+	TrafficLimitUnlimited:         "безлимит",
+	NoteCleared:                   "✅ Заметка для <b>%s</b> удалена",
+	SessionUserLost:               "❌ <b>Ошибка сессии</b>\n\nДанные пользователя потеряны. Начните заново.",
+	InvalidAction:                 "❌ <b>Неизвестное действие</b>\n\nВыберите один из вариантов в меню.",
+	ViewConfigInboundsFailed:      "Не удалось получить подключения: %v",
+	MemberSettingsUnreadable:      "⚠️ <b>Настройки не читаются</b>\n\nУ пользователя '%s' есть трафик на подключении %s, но бот не может разобрать настройки этого подключения, поэтому ссылка на подписку неизвестна. Проверьте подключение в панели X-UI.",
+	MemberNotFound:                "❌ <b>Пользователь не найден</b>\n\nДля пользователя '%s' не найдено конфигураций. Возможно, он был удалён или никогда не существовал.",
+	MemberConfig:                  "🔗 <b>Конфигурация для %s</b>\n\n📋 <b>Ссылка на подписку:</b>\n<code>%s</code>\n\n<i>Скопируйте ссылку в VPN-клиент или отсканируйте QR-код ниже</i>",
+	SubscriptionDead:              "⚠️ <b>Ссылка на подписку не работает</b>\n\nПанель не отдаёт конфигурации для <b>%s</b> по адресу:\n<code>%s</code>\n\nПроверьте, что сервис подписок включён и клиент активен, прежде чем делиться ссылкой.",
+	SubscriptionConfigCount:       "\n\n📦 Конфигураций в подписке: <b>%d</b>",
+	ResetTrafficChooseInbound:     "🔄 <b>Сброс трафика для %s</b>\n\nСбросить все подключения или только одно?",
+	ResetNotifyButton:             "📨 Уведомить пользователя (%d)",
+	ResetNotifyMessage:            "🔄 <b>Трафик сброшен</b>\n\nТрафик вашего аккаунта <b>%s</b> сброшен для нового периода.",
+	ResetNotifyDone:               "📨 <b>%s</b> уведомлён о сбросе (получателей: %d из %d)",
+	ResetNotifyFailed:             "❌ <b>Уведомление не отправлено</b>\n\nНе удалось написать владельцу '%s'. Возможно, он заблокировал бота или ни разу его не запускал.",
+	ResetNotifyNoRecipients:       "ℹ️ Telegram ID для '%s' неизвестен, поэтому уведомить его нельзя.",
+	ResetTrafficAllButton:         "🔄 Все подключения",
+	ResetTrafficInProgress:        "⏳ <b>Сброс трафика...</b>\n\nСбрасываем статистику трафика пользователя '%s'. Подождите...",
+	PanelAuthError:                "❌ <b>Не удалось войти в панель</b>\n\nПанель X-UI отклонила учётные данные бота. Администраторам нужно проверить логин и пароль панели.",
+	PanelServerError:              "❌ <b>Ошибка панели</b>\n\nПанель X-UI вернула ошибку со статусом %d. Попробуйте позже.",
+	PanelErrDuplicateEmail:        "клиент с таким именем уже есть на панели. Выберите другое имя или сначала удалите старого клиента.",
+	PanelErrClientNotFound:        "клиента больше нет на панели. Возможно, его удалили прямо в X-UI.",
+	PanelErrInboundNotFound:       "подключения больше нет на панели. Проверьте подключения командой /inbounds.",
+	PanelErrPortInUse:             "порт уже занят другим подключением.",
+	PanelErrUnreachable:           "не удалось связаться с панелью. Проверьте, что X-UI запущена, и повторите попытку.",
+	PanelErrAuth:                  "панель отклонила учётные данные бота. Проверьте логин и пароль панели.",
+	PanelErrServer:                "панель вернула ошибку со статусом %d. Повторите попытку позже.",
+	PanelErrUnknown:               "запрос к панели не удался. Подробности в журнале бота.",
+	PanelErrInbound:               "Inbound %d: %s",
+	OperationStillWorking:         "⏳ <b>Всё ещё выполняется...</b>\n\nЭто занимает больше времени, чем обычно, но бот продолжает работу. Результат придёт сюда.",
+	OperationTimedOut:             "\n\n⌛ <b>Время истекло</b> после %d из %d. Остальное не затронуто; запустите операцию ещё раз, чтобы завершить её.",
+	ServerDataConnectionError:     "❌ <b>Ошибка подключения</b>\n\nНе удалось получить данные сервера. Проверьте подключение и попробуйте снова.",
+	ResetTrafficDone:              "✅ <b>Трафик сброшен</b>\n\n🔄 Трафик пользователя <b>%s</b> успешно сброшен (конфигураций: %d)",
+	SomeErrorsOccurred:            "\n\n⚠️ <b>Возникли ошибки:</b>\n%s",
+	ResetTrafficFailed:            "❌ <b>Сброс не удался</b>\n\nДля пользователя '%s' не найдено активных конфигураций.",
+	ErrorsList:                    "\n\n<b>Ошибки:</b>\n%s",
+	RenamePrompt:                  "✏️ <b>Переименование %s</b>\n\n📝 Введите новое имя пользователя:\n\n<i>• Только буквы, цифры и подчёркивания\n• Подписка, срок действия и трафик сохранятся</i>",
+	RenameInvalidUsername:         "❌ <b>Недопустимое имя</b>\n\n%s\n\nПопробуйте снова:",
+	RenameSameUsername:            "❌ <b>Имя не изменилось</b>\n\nНовое имя совпадает с текущим. Введите другое имя:",
+	UserListConnectionError:       "❌ <b>Ошибка подключения</b>\n\nНе удалось получить список пользователей. Проверьте подключение к серверу и попробуйте снова.",
+	UsernameTaken:                 "❌ <b>Имя занято</b>\n\nПользователь '%s' уже существует. Выберите другое имя:",
+	MemberAlreadyExists:           "⚠️ <b>Пользователь уже существует</b>\n\nПользователь <b>%s</b> уже есть на сервере, новые клиенты не созданы.\n\nВыберите действие для существующего пользователя:",
+	RenameInProgress:              "⏳ <b>Переименование...</b>\n\nПереименовываем '%s' в '%s' во всех конфигурациях сервера. Подождите...",
+	RenameFailed:                  "❌ <b>Переименование не удалось</b>\n\nНе удалось переименовать пользователя '%s'.",
+	RenameDone:                    "✅ <b>Пользователь переименован</b>\n\n✏️ <b>%s</b> → <b>%s</b> (конфигураций: %d)",
+	DeleteConfirm:                 "🗑️ <b>Подтверждение удаления</b>\n\n⚠️ Вы собираетесь навсегда удалить пользователя <b>%s</b>\n\n<b>Это действие:</b>\n• Удалит пользователя из всех конфигураций сервера\n• Удалит все связанные данные\n• Не может быть отменено\n\nВы точно уверены?",
+	DeleteInvalidSelection:        "❌ <b>Неверный выбор</b>\n\nНажмите кнопку подтверждения выше, чтобы удалить пользователя, или кнопку возврата для отмены.",
+	SessionDeleteLost:             "❌ <b>Ошибка сессии</b>\n\nДанные пользователя потеряны. Начните удаление заново.",
+	DeleteInProgress:              "⏳ <b>Удаление пользователя...</b>\n\nУдаляем пользователя '%s' из всех конфигураций сервера. Подождите...",
+	DeleteFailed:                  "❌ <b>Удаление не удалось</b>\n\nНе удалось удалить пользователя '%s'. Попробуйте снова или обратитесь к администратору.\n\n<b>Ошибка:</b> %v",
+	DeleteDone:                    "✅ <b>Пользователь удалён</b>\n\n🗑️ Пользователь '%s' удалён из всех конфигураций сервера.",
+	ExpiredNone:                   "✅ <b>Нет истёкших пользователей</b>\n\nВсе подписки ещё активны.",
+	ExpiredMemberLine:             "• %s\n",
+	ExpiredConfirm:                "🧹 <b>Удаление истёкших пользователей</b>\n\n⚠️ Срок действия истёк у следующих пользователей (<b>%d</b>), они будут удалены со всех конфигураций сервера:\n\n%s\n<b>Это действие нельзя отменить!</b>",
+	ExpiredInvalidSelection:       "❌ <b>Неверный выбор</b>\n\nНажмите «Подтвердить» выше, чтобы удалить истёкших пользователей, или вернитесь в меню для отмены.",
+	ExpiredDeleteInProgress:       "⏳ <b>Удаление истёкших пользователей (%d)...</b>\n\nПожалуйста, подождите...",
+	ExpiredDeleteFailed:           "❌ <b>Удаление не удалось</b>\n\nНе удалось удалить истёкших пользователей.\n\n<b>Ошибка:</b> %v",
+	ExpiredDeleteDone:             "✅ <b>Истёкшие пользователи удалены</b>\n\n🧹 Удалено пользователей со всех конфигураций сервера: <b>%d</b>.",
+	ReconcileClean:                "✅ <b>Аккаунты синхронизированы</b>\n\nВсе сохранённые аккаунты доверенных пользователей есть в панели.",
+	ReconcileHeader:               "🔍 <b>Сверка аккаунтов</b>\n",
+	ReconcileOrphanedHeader:       "\n🗑 <b>Сохранены, но отсутствуют в панели (%d):</b>\n<i>Они всё ещё учитываются в лимите доверенного пользователя.</i>\n",
+	ReconcileUntrackedHeader:      "\n❓ <b>Есть в панели, но не сохранены (%d):</b>\n<i>Они не учитываются в лимите. Проверьте их в панели.</i>\n",
+	ReconcileAccountLine:          "• %s (от %s)\n",
+	ReconcilePruneQuestion:        "\nУдалить из хранилища отсутствующие аккаунты (<b>%d</b>)?",
+	ReconcileInvalidSelection:     "❌ <b>Неверный выбор</b>\n\nИспользуйте кнопку подтверждения выше, чтобы удалить аккаунты, или кнопку возврата для отмены.",
+	ReconcilePruneFailed:          "❌ <b>Ошибка очистки</b>\n\nНе удалось обновить хранилище.\n\n<b>Ошибка:</b> %v",
+	ReconcilePruneDone:            "✅ <b>Аккаунты очищены</b>\n\n🗑 Удалено отсутствующих аккаунтов из хранилища: <b>%d</b>.",
+	BulkSelectPrompt:              "☑️ <b>Массовое включение/отключение</b>\n\nНажмите на пользователей, чтобы выбрать их, затем выберите <b>Включить</b> или <b>Отключить</b>.\n\n🟢 включён · 🔴 отключён",
+	BulkReturnHint:                "Используйте кнопки под следующим сообщением, чтобы выбрать пользователей.",
+	BulkUseButtons:                "❌ <b>Неверный выбор</b>\n\nИспользуйте кнопки в сообщении выбора или вернитесь в меню для отмены.",
+	BulkEnableButton:              "🟢 Включить (%d)",
+	BulkDisableButton:             "🔴 Отключить (%d)",
+	BulkNothingSelected:           "Сначала выберите хотя бы одного пользователя",
+	BulkActionEnabled:             "Включено",
+	BulkActionDisabled:            "Отключено",
+	BulkResultOK:                  "✅ %s\n",
+	BulkResultFailed:              "❌ %s: %s\n",
+	BulkNoClients:                 "клиенты не найдены",
+	BulkInProgress:                "⏳ <b>Обновление пользователей (%d)...</b>\n\nПожалуйста, подождите...",
+	BulkResultHeader:              "☑️ <b>%s: %d из %d пользователей</b>\n\n%s",
+	BulkPageIndicator:             "📄 %d/%d",
+	DetailedUsageConnectionError:  "❌ <b>Ошибка подключения</b>\n\nНе удалось получить подробные данные об использовании. Проверьте подключение к серверу и попробуйте снова.",
+	UsageInboundHeader:            "📡 <b>%s</b> · %s :%d\n\n",
+	UsageInboundNotFound:          "❌ <b>Подключение не найдено</b>\n\nЭтого подключения больше нет на сервере.",
+	UsageAllInboundsButton:        "📊 Все подключения",
+	UsageActiveOnlyHeader:         "✅ <b>Только активные подписки</b>\n\n",
+	UsageActiveOnlyButton:         "✅ Только активные",
+	UsageShowAllButton:            "👥 Показать всех",
+	FindInboundsUsage:             "\n\n🔎 Отправьте <code>/inbounds имя</code>, чтобы увидеть подключения с клиентами этого пользователя.",
+	FindInboundsHeader:            "🔎 <b>Подключения %s</b>\n\nНайден в <b>%d</b> из %d подключений:\n\n",
+	FindInboundsLine:              "📡 <b>#%d %s</b> · %s :%d\n<code>%s</code>\n\n",
+	FindInboundsMissingHeader:     "⚠️ <b>Отсутствует в:</b>\n",
+	FindInboundsMissingLine:       "• #%d %s\n",
+	FindInboundsExcludedHeader:    "\n🚫 <b>Нет в подключениях, исключённых настройками для новых пользователей:</b>\n",
+	InboundsOverviewHeader:        "📡 <b>Подключения</b>\n\nВключено <b>%d</b> из %d. Новые пользователи получают клиентов только во включённых подключениях.\n\n",
+	InboundsOverviewEmpty:         "📡 На панели пока нет подключений.",
+	InboundsOverviewMalformed:     "⚠️ Настройки не читаются, клиенты посчитаны по статистике трафика: %s\n\n",
+	InboundsOverviewExcluded:      "🚫 Исключены настройками для новых пользователей: %s\n\n",
+	InboundTogglePrompt:           "\n\n🔌 Нажмите на подключение, чтобы включить или отключить его.",
+	InboundToggleNotFound:         "❌ Подключения #%d больше нет.",
+	InboundToggleConfirmDisable:   "🔌 <b>Отключение подключения</b>\n\n⚠️ Отключить <b>#%d %s</b> (%s :%d)?\n\nВсе его клиенты (<b>%d</b>) потеряют доступ, пока оно не будет включено снова.",
+	InboundToggleConfirmEnable:    "🔌 <b>Включение подключения</b>\n\nВключить <b>#%d %s</b> (%s :%d)?\n\nЕго клиенты (<b>%d</b>) снова смогут подключаться, кроме отключённых или истёкших.",
+	InboundToggleInvalidSelection: "❌ <b>Неверный выбор</b>\n\nНажмите «Подтвердить» выше, чтобы изменить подключение, или вернитесь в меню для отмены.",
+	InboundToggleFailed:           "❌ <b>Изменение не удалось</b>\n\nНе удалось изменить подключение #%d.\n\n<b>Ошибка:</b> %v",
+	InboundToggleDisabled:         "🔴 Подключение <b>#%d %s</b> отключено.",
+	InboundToggleEnabled:          "🟢 Подключение <b>#%d %s</b> включено.",
+	TransferUsage:                 "🔁 Отправьте <code>/transfer аккаунт владелец</code>, чтобы передать аккаунт доверенного пользователя другому доверенному пользователю.\n\nАккаунт — его ID или имя, владелец — @username или Telegram ID.",
+	TransferAccountNotFound:       "❌ Нет сохранённого аккаунта <b>%s</b>.",
+	TransferAccountAmbiguous:      "❌ Аккаунтов с именем <b>%s</b> несколько. Укажите один из их ID: %s",
+	TransferTargetNotTrusted:      "❌ %s не является доверенным пользователем.",
+	TransferSameOwner:             "ℹ️ <b>%s</b> уже принадлежит %s.",
+	TransferLimitReached:          "❌ У %s уже %d аккаунта — больше доверенному пользователю не положено.",
+	TransferFailed:                "❌ Не удалось передать аккаунт: %v",
+	TransferDone:                  "✅ Аккаунт <b>#%d %s</b> передан от %s к %s.",
+	TransferPanelFailed:           "\n\n⚠️ Не удалось передать некоторых клиентов в панели, поэтому их напоминания по-прежнему получает прежний владелец:\n%s",
+	BroadcastPrompt:               "📣 <b>Рассылка</b>\n\nОтправьте объявление, которое получат все администраторы и доверенные пользователи. Оно отправляется простым текстом.",
+	BroadcastEmpty:                "❌ Объявление не может быть пустым. Отправьте текст или вернитесь в главное меню.",
+	BroadcastNoRecipients:         "ℹ️ Пока некому отправить объявление.",
+	BroadcastConfirm:              "📣 <b>Рассылка</b>\n\n<blockquote>%s</blockquote>\n\nОтправить это <b>%d</b> администраторам и доверенным пользователям?",
+	BroadcastOwnersHint:           "\n\nЕщё %d бывших доверенных пользователей владеют VPN-аккаунтами. Чтобы добавить их, нажмите вторую кнопку.",
+	BroadcastSendButton:           "✅ Отправить %d",
+	BroadcastOwnersButton:         "👥 И владельцам аккаунтов (%d)",
+	BroadcastInvalidSelection:     "❌ <b>Неверный выбор</b>\n\nИспользуйте кнопки выше, чтобы отправить объявление, или кнопку возврата для отмены.",
+	BroadcastInProgress:           "⏳ Отправляю объявление %d получателям...",
+	BroadcastDone:                 "📣 Доставлено <b>%d</b> из %d получателей.",
+	BroadcastFailedLine:           "\n❌ %s: %s",
+	TopUsersHeader:                "🏆 <b>Топ-%d пользователей по трафику</b>\n\n",
+	TopUsersLine:                  "%d. <b>%s</b> — %.2f %s · %s\n",
+	TopUsersInvalidCount:          "❌ <b>Неверное число</b>\n\nУкажите положительное число, например <code>/top 20</code>.",
+	ExportConnectionError:         "❌ <b>Ошибка подключения</b>\n\nНе удалось получить данные для экспорта. Проверьте подключение к серверу и попробуйте снова.",
+	ExportNoUsers:                 "📭 <b>Пользователи не найдены</b>\n\nВ системе нет пользователей для экспорта.",
+	ExportBuildFailed:             "❌ <b>Экспорт не удался</b>\n\nНе удалось сформировать отчёт. Попробуйте позже.",
+	ExportCaption:                 "📄 Отчёт об использовании (пользователей: %d)",
+	ExportSendFailed:              "❌ <b>Экспорт не удался</b>\n\nНе удалось отправить отчёт. Попробуйте позже.",
+	ExportDone:                    "✅ <b>Экспорт завершён</b>",
+	ChartConnectionError:          "❌ <b>Ошибка подключения</b>\n\nНе удалось получить данные о трафике. Проверьте подключение к серверу и попробуйте снова.",
+	ChartNoUsers:                  "📭 <b>Нет активных пользователей</b>\n\nДанные о трафике отсутствуют.",
+	ChartRenderFailed:             "❌ <b>Не удалось построить график</b>\n\nНе удалось построить график трафика. Попробуйте позже.",
+	ChartCaptionHeader:            "<b>📊 Топ-%d пользователей по трафику</b>\n🟦 загрузка  🟧 отдача\n\n",
+	ChartLegendLine:               "%d. %s — %.2f %s\n",
+	ChartSendFailed:               "❌ <b>Не удалось отправить график</b>\n\nНе удалось отправить график трафика. Попробуйте позже.",
+	ConfirmationExpired:           "⌛ <b>Подтверждение устарело</b>\n\nЭто подтверждение больше не действительно. Начните действие заново.",
+	ResetAllInvalidSelection:      "❌ <b>Неверный выбор</b>\n\nНажмите кнопку подтверждения выше, чтобы выполнить сброс, или кнопку возврата для отмены.",
+	ResetAllInProgress:            "⏳ <b>Сброс всего трафика...</b>\n\nЭто может занять некоторое время. Сбрасываем статистику трафика всех пользователей на всех серверах...",
+	ResetAllProgress:              "⏳ <b>Сброс всего трафика...</b>\n\nСброшено клиентов: %d/%d...",
+	ResetAllConnectionError:       "❌ <b>Ошибка подключения</b>\n\nНе удалось получить данные сервера для сброса. Проверьте подключение и попробуйте снова.",
+	ResetAllNoUsers:               "📭 <b>Пользователи не найдены</b>\n\nВ системе нет пользователей для сброса трафика.",
+	ResetAllCountChanged:          "⚠️ <b>Сброс отменён</b>\n\nВы подтвердили сброс для %d клиентов, но сейчас на сервере их %d. Ничего не сброшено. Начните заново, чтобы увидеть новые данные.",
+	ResetAllDone:                  "✅ <b>Массовый сброс трафика завершён</b>\n\n🔄 Трафик успешно сброшен для <b>%d пользователей</b>\n\n<i>Все счётчики трафика обнулены</i>",
+	ResetAllFailed:                "❌ <b>Массовый сброс не удался</b>\n\nНе удалось сбросить трафик ни для одного пользователя.\n\n<b>Ошибки:</b>\n%s",
+	NoUsersYet:                    "📭 <b>Пользователи не найдены</b>\n\nВ системе пока нет пользователей.",
+	NoUsersYetHint:                " Используйте <b>Add Member</b>, чтобы создать первого пользователя.",
+	SelectMemberToEdit:            "✏️ <b>Редактирование</b>\n\n👥 Выберите пользователя:",
+	SelectMemberToDelete:          "🗑️ <b>Удаление</b>\n\n⚠️ Выберите пользователя для удаления:",
+	InvalidSelectionShort:         "Неверный выбор.",
+	UnknownAction:                 "Неизвестное действие.",
+	BackupConnectionError:         "❌ <b>Ошибка подключения</b>\n\nНе удалось получить данные сервера для резервной копии. Проверьте подключение к серверу и попробуйте снова.",
+	BackupBuildFailed:             "❌ <b>Резервное копирование не удалось</b>\n\nНе удалось сформировать файл резервной копии. Попробуйте позже.",
+	BackupCaption:                 "💾 Резервная копия: доверенных пользователей %d, администраторов %d, VPN-аккаунтов %d, подключений %d",
+	BackupSendFailed:              "❌ <b>Резервное копирование не удалось</b>\n\nНе удалось отправить файл резервной копии. Попробуйте позже.",
+	BackupDone:                    "✅ <b>Резервная копия готова</b>\n\nСохраните этот файл в надёжном месте. Используйте <b>Restore</b>, чтобы восстановить его.",
+	RestorePrompt:                 "♻️ <b>Восстановление</b>\n\n📎 Загрузите файл резервной копии, созданный командой <b>Backup</b>.\n\n<i>Будут восстановлены доверенные пользователи, администраторы, VPN-аккаунты, а также заметки, теги и пароли пользователей. Клиенты панели не изменяются.</i>",
+	RestoreUploadDocument:         "📎 Загрузите файл резервной копии как документ:",
+	RestoreDownloadFailed:         "❌ <b>Ошибка загрузки</b>\n\nНе удалось скачать файл резервной копии. Попробуйте снова:",
+	RestoreReadFailed:             "❌ <b>Ошибка загрузки</b>\n\nНе удалось прочитать файл резервной копии. Попробуйте снова:",
+	RestoreInvalidBackup:          "❌ <b>Неверная резервная копия</b>\n\nФайл не является резервной копией: %v\n\nЗагрузите другой файл:",
+	RestoreConfirm:                "♻️ <b>Подтверждение восстановления</b>\n\nРезервная копия от <b>%s</b> содержит:\n• доверенных пользователей: %d\n• администраторов: %d\n• VPN-аккаунтов: %d\n• заметок, наборов тегов и паролей пользователей: %d\n\nСуществующие записи сохранятся, недостающие будут добавлены.\n\nВы уверены?",
+	RestoreInvalidSelection:       "❌ <b>Неверный выбор</b>\n\nНажмите кнопку подтверждения выше, чтобы выполнить восстановление, или кнопку возврата для отмены.",
+	SessionBackupLost:             "❌ <b>Ошибка сессии</b>\n\nДанные резервной копии потеряны. Начните восстановление заново.",
+	SessionBackupCorrupted:        "❌ <b>Ошибка сессии</b>\n\nДанные резервной копии повреждены. Начните восстановление заново.",
+	RestoreDone:                   "✅ <b>Восстановление завершено</b>\n\n• Восстановлено доверенных пользователей: %d\n• Восстановлено администраторов: %d\n• Восстановлено VPN-аккаунтов: %d\n• Восстановлено заметок, наборов тегов и паролей пользователей: %d",
+	RestorePartialErrors:          "\n\n⚠️ <b>Возникли ошибки:</b>\nНе удалось восстановить записей: %d, подробности в логах",
+	SubscriptionQRCaption:         "QR-код для подписки:",
+	CopyLinkButton:                "📋 Скопировать ссылку",
+	CopyLinkText:                  "🔗 <b>Ссылка на подписку</b>\n\n<code>%s</code>",
+	QRCaption:                     "📱 <b>%s</b> · %s",
+	AddMemberDone:                 "🎉 <b>Пользователь создан!</b>\n\nНовый пользователь готов к подключению к VPN.",
+	HealthFailed:                  "🩺 <b>Проверка не пройдена</b>\n\n🌐 <b>Панель:</b> <code>%s</code>\n🔑 <b>Вход:</b> %s\n",
+	HealthInbounds:                "📡 <b>Подключения:</b> HTTP %d, %s\n",
+	HealthError:                   "\n<b>Ошибка:</b> %v",
+	HealthPassed:                  "🩺 <b>Проверка пройдена</b>\n\n🌐 <b>Панель:</b> <code>%s</code>\n🔑 <b>Вход:</b> OK, %s\n📡 <b>Подключения:</b> HTTP %d, %s (подключений: %d)",
+	TrustedAddPrompt:              "Отправьте @username, чтобы добавить в список доверенных:",
+	TrustedNone:                   "Доверенные пользователи не найдены.",
+	TrustedSelectRevoke:           "Выберите пользователя для отзыва:",
+	TrustedRevokeFailed:           "Не удалось отозвать пользователя.",
+	TrustedRevoked:                "Пользователь удалён из списка доверенных.",
+	TrustedInvalidUsername:        "Отправьте корректный @username:",
+	TrustedAddFailed:              "Не удалось добавить пользователя в список доверенных.",
+	TrustedAdded:                  "@%s добавлен в список доверенных.",
+	AccountLimitReached:           "Можно создать не более %d аккаунтов.",
+	TrustedWelcome:                "Добро пожаловать! Вы доверенный пользователь.",
+	TrustedMainMenu:               "Главное меню",
+	TelegramUsernameRequired:      "Ошибка: сначала задайте имя пользователя в Telegram. Откройте Настройки Telegram -> Изменить профиль -> Имя пользователя",
+	AccountCreating:               "Создание аккаунта '%s'...",
+	AccountCreateFailed:           "Не удалось создать аккаунт:\n",
+	AccountNoneToRemove:           "У вас нет аккаунтов для удаления.",
+	AccountSelectRemove:           "Выберите аккаунт для удаления:",
+	AccountInvalidSelection:       "Неверный выбор аккаунта.",
+	AccountNotFoundShort:          "Аккаунт не найден.",
+	AccountNoneToShow:             "У вас пока нет аккаунтов. Нажмите ➕, чтобы создать.",
+	AccountSelectShow:             "Выберите аккаунт, чтобы снова получить ссылку подписки и QR-код:",
+	AccountNotOnServer:            "❌ Аккаунт <b>%s</b> на сервере неполный. Обратитесь к администратору.",
+	AccountGone:                   "❌ Вашего аккаунта <b>%s</b> больше нет на сервере, скорее всего его удалил администратор. Обратитесь к администратору, если он ещё нужен.",
+	AccountResendHeader:           "📋 Аккаунт <b>%s</b>\n\n⏳ Срок действия: %s\n📦 Трафик: использовано %.2f GB, лимит %s\n",
+	AccountResendPassword:         "🔑 Пароль: <code>%s</code>\n",
+	AccountResendClients:          "\nКлиенты:\n%s\n\n🔗 Ссылка для подключения: %s",
+	MyUsageHeader:                 "📊 <b>Ваш трафик</b>\n\n",
+	MyUsageLine:                   "👤 <b>%s</b>\n↓ %.2f %s · ↑ %.2f %s\n⏳ %s\n\n",
+	MyUsageMissing:                "👤 <b>%s</b>\n❌ Больше не существует на сервере, обратитесь к администратору\n\n",
+	ExpiryReminder:                "⏰ <b>Напоминание о сроке</b>\n\nАккаунт <b>%s</b> истекает <b>%s</b> (%s). Попросите администратора продлить его, чтобы он продолжил работать.",
+	ExpiryReminderOffButton:       "🔕 Не напоминать",
+	RemindersOff:                  "🔕 Напоминания о сроке отключены. Отправьте /reminders, чтобы включить их снова.",
+	RemindersSaveFailed:           "❌ Не удалось сохранить настройку напоминаний. Попробуйте ещё раз.",
+	RemindersUnavailable:          "ℹ️ Этот бот не присылает напоминания о сроке.",
+	RemindersOn:                   "🔔 Напоминания о сроке включены: они приходят за %d дн. до истечения аккаунта. Отправьте /reminders ещё раз, чтобы отключить их.",
+	AccountDeleteConfirm:          "🗑️ **Подтверждение удаления аккаунта**\n\n⚠️ Вы собираетесь навсегда удалить аккаунт **%s**\n\n**Это действие:**\n• Удалит аккаунт из всех конфигураций сервера\n• Удалит все связанные данные\n• Не может быть отменено\n\nВы точно уверены?",
+	AccountDeleteInvalidSelection: "❌ **Неверный выбор**\n\nНажмите Confirm, чтобы удалить аккаунт, или кнопку возврата для отмены.",
+	SessionAccountLost:            "❌ **Ошибка сессии**\n\nДанные аккаунта потеряны. Начните удаление заново.",
+	AccountInvalidID:              "❌ **Неверный ID аккаунта**\n\nНачните удаление заново.",
+	AccountNotFound:               "❌ **Аккаунт не найден**\n\nВозможно, аккаунт уже удалён.",
+	AccountDeleteInProgress:       "⏳ **Удаление аккаунта...**\n\nУдаляем аккаунт '%s' из всех конфигураций сервера. Подождите...",
+	AccountDeleteFailed:           "❌ **Удаление не удалось**\n\nНе удалось удалить аккаунт '%s' из конфигураций сервера.\n\n**Ошибка:** %v\n\nПопробуйте снова или обратитесь к администратору.",
+	AccountDeletePartial:          "⚠️ **Частичный успех**\n\nАккаунт удалён с сервера, но не удалось обновить базу данных:\n%v",
+	AccountDeleteDone:             "✅ **Аккаунт удалён**\n\n🗑️ Аккаунт '%s' удалён из всех конфигураций сервера.",
+	AccountDeleteGone:             "✅ **Аккаунт убран**\n\n🗑️ Аккаунта '%s' уже не было на сервере, поэтому он только убран из вашего списка.",
+	MemberNoAccount:               "❌ На сервере нет аккаунта, привязанного к вашему Telegram ID. Если он был, скорее всего его удалил администратор. Обратитесь к администратору, если он ещё нужен.",
+	MemberSubscriptionURL:         "Ваша ссылка на подписку:\n\n%s",
+	MemberSubscriptionFailed:      "Не удалось получить ссылку на подписку: %s",
+	MemberInboundsFailed:          "Не удалось получить подключения: %s",
+	ServerSelectionAutomatic:      "Конфигурация сервера выбирается автоматически.",
+	WhoAmI:                        "🪪 <b>Кто я</b>\n\n👤 <b>Имя пользователя:</b> %s\n🆔 <b>Telegram ID:</b> <code>%d</code>\n🔐 <b>Доступ:</b> %s",
+	WhoAmIRequestAccess:           "\n\nОтправьте свой Telegram ID администратору, чтобы запросить доступ.",
+	NoAccessYourID:                "\n\n🆔 Ваш Telegram ID: <code>%d</code>\nОтправьте его администратору, чтобы запросить доступ.",
+	SupportContactLine:            "\n\n💬 <b>Поддержка:</b> %s",
+	SupportHeader:                 "💬 <b>Поддержка</b>\n\nКонтакт: %s",
+	SupportNotConfigured:          "💬 <b>Поддержка</b>\n\nКонтакт поддержки не указан. Обратитесь к администратору бота.",
+	SupportYourID:                 "\n\n🆔 Ваш Telegram ID: <code>%d</code>",
+	VersionInfo:                   "🏷 <b>Версия</b>\n\n<b>Версия:</b> <code>%s</code>\n<b>Коммит:</b> <code>%s</code>\n<b>Сборка:</b> %s",
+	ActingAsUsage:                 "🎭 <b>Режим просмотра</b>\n\nПосмотреть на бота с другим уровнем доступа:\n<code>/as trusted</code> — меню доверенного пользователя\n<code>/as none</code> — что видят пользователи без доступа\n<code>/as admin</code> — вернуться к админскому виду\n\n<b>Сейчас:</b> %s",
+	ActingAsStarted:               "🎭 <b>Вы действуете как %s</b>\n\nБот считает вас пользователем с доступом %s, пока вы не отправите <code>/as admin</code> или не истечёт сессия. Все действия настоящие: созданные здесь аккаунты появятся на панели.",
+	ActingAsStopped:               "🎭 Вы вернулись к админскому виду.",
+	ActingAsBanner:                "\n\n🎭 <i>Вы действуете как %s. Отправьте /as admin, чтобы вернуться.</i>",
+	ActingAsAdminOnly:             "❌ Команда /as доступна только администраторам.",
+	NoPermission:                  "У вас нет доступа к этому боту.",
+	TextExpected:                  "✍️ Пожалуйста, отправьте текст. Фото, стикеры и другие медиа здесь не подходят.",
+	PanelUnhealthyAlert:           "🚨 <b>Панель работает нестабильно</b>\n\nОперация <code>%s</code> завершилась ошибкой <b>%d раз</b> за последние %d мин.\n\n<b>Последняя ошибка:</b> %s",
+	InputTooLong:                  "⚠️ <b>Слишком длинное сообщение</b>\n\nСообщение должно быть не длиннее %d символов.",
+	GenericError:                  "Произошла ошибка. Попробуйте позже.",
+	RateLimited:                   "⏳ Пожалуйста, помедленнее",
+
+	// Connection links
+	ConnectionHeader:          "🔌 <b>Ссылки подключения для %s</b>\n\n",
+	ConnectionLine:            "📡 <b>%s</b> · %s :%d\n<code>%s</code>\n\n",
+	ConnectionUnsupported:     "📡 <b>%s</b> · %s\n<i>Пока не поддерживается, используйте ссылку на подписку.</i>\n\n",
+	ConnectionFailed:          "📡 <b>%s</b> · %s\n<i>Не удалось прочитать настройки подключения.</i>\n\n",
+	ConfigExportTitle:         "%s — %s\n\n",
+	ConfigExportSubscription:  "Ссылка подписки (импортируйте её или отсканируйте QR-код):\n%s\n\n",
+	ConfigExportInbound:       "[%s] %s, порт %d\n%s\n\n",
+	ConfigExportUnsupported:   "Пока не поддерживается, используйте ссылку подписки.",
+	ConfigExportInboundFailed: "Не удалось прочитать настройки подключения.",
+	ConfigExportCaption:       "📦 Конфигурация %s: ссылка подписки, параметры подключений и QR-код",
+	ConfigExportFailed:        "❌ <b>Ошибка экспорта</b>\n\nНе удалось собрать архив конфигурации для <b>%s</b>. Попробуйте снова.",
+
+	// Subscription reconciliation
+	SubIDMismatch:       "⚠️ <b>Несовпадение подписок</b>\n\nУ пользователя <b>%s</b> клиенты с разными ID подписки (%d), поэтому ссылка выше покрывает не все подключения:\n%s\nОбъединить их под <code>%s</code>?",
+	SubIDMismatchLine:   "• <code>%s</code> — %d\n",
+	SubIDUnifyButton:    "🔗 Объединить подписку",
+	SubIDUnified:        "✅ <b>Подписка объединена</b>\n\n🔗 Клиентов пользователя <b>%[2]s</b> переведено на <code>%[3]s</code>: %[1]d",
+	SubIDAlreadyUnified: "✅ <b>Нечего объединять</b>\n\nВсе клиенты пользователя <b>%s</b> уже используют один ID подписки.",
+	SubIDUnifyFailed:    "❌ <b>Объединение не удалось</b>\n\nНе удалось обновить клиентов пользователя '%s'.",
+
+	// Runtime admins
+	AdminAddPrompt:         "👑 <b>Новый администратор</b>\n\nОтправьте Telegram ID (например, <code>123456789</code>) или @username нового администратора:\n\n<i>Пользователь может узнать свой ID командой /whoami</i>",
+	AdminInvalidIdentifier: "❌ <b>Неверный ввод</b>\n\nОтправьте числовой Telegram ID или @username:",
+	AdminAlreadyAdmin:      "ℹ️ <b>Уже администратор</b>\n\n%s уже имеет права администратора.",
+	AdminAddFailed:         "❌ <b>Не удалось добавить администратора</b>\n\nНе удалось сохранить нового администратора. Попробуйте позже.",
+	AdminAdded:             "✅ <b>Администратор добавлен</b>\n\n👑 %s теперь имеет права администратора.",
+	AdminNoneToRevoke:      "📭 <b>Нет добавленных администраторов</b>\n\nЧерез бота не добавлено ни одного администратора. Администраторов из TG_ADMIN_IDS можно убрать только через конфигурацию.",
+	AdminSelectRevoke:      "❎ <b>Отзыв прав администратора</b>\n\nВыберите администратора:",
+	AdminLastAdmin:         "❌ <b>Нельзя отозвать</b>\n\nДолжен остаться хотя бы один администратор.",
+	AdminRevokeFailed:      "❌ <b>Не удалось отозвать права</b>\n\nНе удалось обновить список администраторов. Попробуйте позже.",
+	AdminRevoked:           "✅ <b>Права отозваны</b>\n\n%s больше не администратор.",
+	AdminNotFound:          "❌ <b>Администратор не найден</b>\n\nВозможно, права уже отозваны.",
+
+	// Sort and expiry labels
+	SortByCreationOrder: "📅 По дате добавления",
+	SortByExpiryDate:    "⏰ По дате истечения",
+	SortByTrafficTotal:  "📊 По общему трафику",
+	SortByStatus:        "🔄 По статусу",
+	SortByName:          "🔤 По имени",
+	ExpiryNever:         "∞ Бессрочный",
+	ExpiryExpired:       "❌ Истек",
+	ExpiryToday:         "⚠️ Истекает сегодня",
+	ExpiryDaysLeftSoon:  "⚠️ %d дн.",
+	ExpiryDaysLeft:      "✅ %d дн.",
+}
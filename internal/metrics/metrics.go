@@ -0,0 +1,228 @@
+// Package metrics keeps the bot's counters and histograms and serves them in the
+// Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"xui-tg-admin/internal/constants"
+)
+
+// Metrics exposed on /metrics
+var (
+	UpdatesTotal = newCounterVec("xui_tg_admin_updates_total",
+		"Telegram updates processed, by kind and result.", "kind", "result")
+	UpdateDuration = newHistogramVec("xui_tg_admin_update_duration_seconds",
+		"Time spent handling Telegram updates, by kind.", "kind")
+	PanelRequestsTotal = newCounterVec("xui_tg_admin_panel_requests_total",
+		"X-UI panel operations, by operation and result.", "operation", "result")
+	PanelRequestDuration = newHistogramVec("xui_tg_admin_panel_request_duration_seconds",
+		"Latency of X-UI panel operations, by operation.", "operation")
+
+	activeUsers = &activeUserSet{seen: make(map[int64]time.Time)}
+)
+
+// durationBuckets are the histogram upper bounds in seconds, from a cached reply to a slow panel
+var durationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// labelSeparator joins label values into the key of a series
+const labelSeparator = "\xff"
+
+// labelEscaper escapes label values as the text format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// counterVec is a counter partitioned by label values
+type counterVec struct {
+	name   string
+	help   string
+	labels []string
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// newCounterVec creates a counter with the given label names
+func newCounterVec(name, help string, labels ...string) *counterVec {
+	return &counterVec{name: name, help: help, labels: labels, values: make(map[string]float64)}
+}
+
+// Inc adds one to the series with the given label values
+func (c *counterVec) Inc(labelValues ...string) {
+	key := strings.Join(labelValues, labelSeparator)
+	c.mu.Lock()
+	c.values[key]++
+	c.mu.Unlock()
+}
+
+// write renders the counter in the text format
+func (c *counterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, key, ""), formatValue(c.values[key]))
+	}
+}
+
+// histogram holds the cumulative bucket counts of one series
+type histogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// histogramVec is a histogram partitioned by label values
+type histogramVec struct {
+	name   string
+	help   string
+	labels []string
+	mu     sync.Mutex
+	series map[string]*histogram
+}
+
+// newHistogramVec creates a duration histogram with the given label names
+func newHistogramVec(name, help string, labels ...string) *histogramVec {
+	return &histogramVec{name: name, help: help, labels: labels, series: make(map[string]*histogram)}
+}
+
+// Observe records how long ago start was in the series with the given label values
+func (h *histogramVec) Observe(start time.Time, labelValues ...string) {
+	seconds := time.Since(start).Seconds()
+	key := strings.Join(labelValues, labelSeparator)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogram{buckets: make([]uint64, len(durationBuckets))}
+		h.series[key] = s
+	}
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.count++
+	s.sum += seconds
+}
+
+// write renders the histogram in the text format
+func (h *histogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		for i, bound := range durationBuckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, key, formatValue(bound)), s.buckets[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, key, "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, key, ""), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, key, ""), s.count)
+	}
+}
+
+// activeUserSet remembers when each Telegram user was last seen
+type activeUserSet struct {
+	mu     sync.Mutex
+	seen   map[int64]time.Time
+	pruned time.Time // when users outside the window were last forgotten
+}
+
+// SeenUser records activity of a Telegram user for the active users gauge. Users outside
+// the window are forgotten about once per window, so the set stays bounded even when
+// /metrics is never scraped.
+func SeenUser(userID int64) {
+	activeUsers.mu.Lock()
+	defer activeUsers.mu.Unlock()
+
+	now := time.Now()
+	activeUsers.seen[userID] = now
+	if now.Sub(activeUsers.pruned) > constants.MetricsActiveWindow*time.Minute {
+		activeUsers.prune(now)
+	}
+}
+
+// prune forgets users outside the window; the caller holds the mutex
+func (a *activeUserSet) prune(now time.Time) {
+	window := constants.MetricsActiveWindow * time.Minute
+	for userID, seen := range a.seen {
+		if now.Sub(seen) > window {
+			delete(a.seen, userID)
+		}
+	}
+	a.pruned = now
+}
+
+// write renders the active users gauge in the text format
+func (a *activeUserSet) write(w io.Writer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.prune(time.Now())
+
+	name := "xui_tg_admin_active_users"
+	fmt.Fprintf(w, "# HELP %s Telegram users who used the bot in the last %d minutes.\n# TYPE %s gauge\n%s %d\n",
+		name, constants.MetricsActiveWindow, name, name, len(a.seen))
+}
+
+// WriteAll writes every metric in the Prometheus text format
+func WriteAll(w io.Writer) {
+	activeUsers.write(w)
+	UpdatesTotal.write(w)
+	UpdateDuration.write(w)
+	PanelRequestsTotal.write(w)
+	PanelRequestDuration.write(w)
+}
+
+// formatLabels renders {name="value",...} for a series key, adding le for histogram buckets
+func formatLabels(names []string, key, le string) string {
+	var pairs []string
+	if len(names) > 0 {
+		for i, value := range strings.Split(key, labelSeparator) {
+			pairs = append(pairs, fmt.Sprintf(`%s="%s"`, names[i], labelEscaper.Replace(value)))
+		}
+	}
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf(`le="%s"`, le))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue renders a sample value the way Prometheus expects
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+// sortedKeys returns the series keys in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteAll(w)
+	})
+}
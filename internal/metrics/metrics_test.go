@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"xui-tg-admin/internal/constants"
+)
+
+func TestSeenUserForgetsIdleUsers(t *testing.T) {
+	window := constants.MetricsActiveWindow * time.Minute
+
+	activeUsers.mu.Lock()
+	activeUsers.seen = map[int64]time.Time{
+		1: time.Now().Add(-2 * window),
+		2: time.Now(),
+	}
+	activeUsers.pruned = time.Now().Add(-2 * window)
+	activeUsers.mu.Unlock()
+
+	SeenUser(3)
+
+	activeUsers.mu.Lock()
+	defer activeUsers.mu.Unlock()
+	if _, ok := activeUsers.seen[1]; ok {
+		t.Error("a user outside the window is still remembered")
+	}
+	for _, id := range []int64{2, 3} {
+		if _, ok := activeUsers.seen[id]; !ok {
+			t.Errorf("user %d inside the window was forgotten", id)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package models
+
+// AdminUser represents an admin added at runtime through the bot
+type AdminUser struct {
+	TelegramID int64  `json:"telegram_id"`
+	Username   string `json:"username"`
+	AddedBy    int64  `json:"added_by"`
+	AddedAt    int64  `json:"added_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// Backup represents a full bot backup: local storage plus a snapshot of panel inbounds
+type Backup struct {
+	CreatedAt    int64               `json:"created_at"`
+	TrustedUsers []TrustedUser       `json:"trusted_users"`
+	VpnAccounts  []VpnAccount        `json:"vpn_accounts"`
+	AdminUsers   []AdminUser         `json:"admin_users,omitempty"`
+	Notes        map[string]string   `json:"notes,omitempty"`     // admin notes keyed by base username
+	Tags         map[string][]string `json:"tags,omitempty"`      // admin tags keyed by base username
+	Passwords    map[string]string   `json:"passwords,omitempty"` // generated passwords of admin-created members keyed by base username
+	Inbounds     []Inbound           `json:"inbounds"`
+}
@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"strings"
 )
 
@@ -19,21 +21,29 @@ type Client struct {
 	Fingerprint string  `json:"fingerprint"`
 	TgID        string  `json:"tgId"`
 	SubID       string  `json:"subId"`
+	Password    string  `json:"password,omitempty"` // Trojan and Shadowsocks only
+
+	// Extra holds panel fields the bot doesn't manage, sent back as they were read
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
-// ToDictionary converts the client to a map for API requests
+// ToDictionary converts the client to a map for API requests. The fields the bot manages
+// take precedence over the ones kept in Extra.
 func (c *Client) ToDictionary() map[string]interface{} {
-	result := map[string]interface{}{
-		"id":          c.ID,
-		"enable":      c.Enable,
-		"email":       c.Email,
-		"totalGB":     c.TotalGB,
-		"limitIp":     c.LimitIP,
-		"fingerprint": c.Fingerprint,
-		"tgId":        c.TgID,
-		"subId":       c.SubID,
+	result := make(map[string]interface{}, len(c.Extra)+10)
+	for key, value := range c.Extra {
+		result[key] = value
 	}
 
+	result["id"] = c.ID
+	result["enable"] = c.Enable
+	result["email"] = c.Email
+	result["totalGB"] = c.TotalGB
+	result["limitIp"] = c.LimitIP
+	result["fingerprint"] = c.Fingerprint
+	result["tgId"] = c.TgID
+	result["subId"] = c.SubID
+
 	// Add optional fields if they exist
 	if c.Flow != nil {
 		result["flow"] = *c.Flow
@@ -43,6 +53,10 @@ func (c *Client) ToDictionary() map[string]interface{} {
 		result["expiryTime"] = *c.ExpiryTime
 	}
 
+	if c.Password != "" {
+		result["password"] = c.Password
+	}
+
 	return result
 }
 
@@ -68,3 +82,37 @@ func GenerateSubID() string {
 
 	return b64
 }
+
+// GenerateUUID generates a random (version 4) UUID for a client's protocol ID
+func GenerateUUID() (string, error) {
+	uuid := make([]byte, 16)
+	if _, err := rand.Read(uuid); err != nil {
+		return "", fmt.Errorf("failed to generate client ID: %w", err)
+	}
+	uuid[6] = uuid[6]&0x0f | 0x40 // version 4
+	uuid[8] = uuid[8]&0x3f | 0x80 // RFC 4122 variant
+
+	h := hex.EncodeToString(uuid)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32], nil
+}
+
+// GeneratePassword generates a random 32-byte secret, base64 encoded, usable as a Trojan
+// password and as a Shadowsocks 2022 key
+func GeneratePassword() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate client password: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(secret), nil
+}
+
+// NewMemberPassword generates a password for a new member if any of the inbounds
+// authenticates clients with one, and returns an empty string otherwise
+func NewMemberPassword(inbounds []Inbound) (string, error) {
+	for _, inbound := range inbounds {
+		if inbound.UsesPassword() {
+			return GeneratePassword()
+		}
+	}
+	return "", nil
+}
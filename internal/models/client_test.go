@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/base64"
+	"regexp"
+	"testing"
+)
+
+func TestGenerateUUID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first, err := GenerateUUID()
+	if err != nil {
+		t.Fatalf("GenerateUUID returned error: %v", err)
+	}
+	if !pattern.MatchString(first) {
+		t.Errorf("GenerateUUID() = %q, not a version 4 UUID", first)
+	}
+
+	second, err := GenerateUUID()
+	if err != nil {
+		t.Fatalf("GenerateUUID returned error: %v", err)
+	}
+	if first == second {
+		t.Errorf("GenerateUUID returned %q twice", first)
+	}
+}
+
+func TestNewMemberPassword(t *testing.T) {
+	password, err := NewMemberPassword([]Inbound{{Protocol: "vless"}, {Protocol: "vmess"}})
+	if err != nil {
+		t.Fatalf("NewMemberPassword returned error: %v", err)
+	}
+	if password != "" {
+		t.Errorf("NewMemberPassword gave VLESS and VMess inbounds the password %q", password)
+	}
+
+	password, err = NewMemberPassword([]Inbound{{Protocol: "vless"}, {Protocol: "trojan"}})
+	if err != nil {
+		t.Fatalf("NewMemberPassword returned error: %v", err)
+	}
+	secret, err := base64.StdEncoding.DecodeString(password)
+	if err != nil || len(secret) != 32 {
+		t.Errorf("NewMemberPassword() = %q, want a base64 32-byte secret", password)
+	}
+}
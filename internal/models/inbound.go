@@ -1,19 +1,72 @@
 package models
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"time"
+)
+
 // Inbound represents an X-ray inbound configuration
 type Inbound struct {
-	ID          int          `json:"id"`
-	Up          int64        `json:"up"`
-	Down        int64        `json:"down"`
-	Total       int64        `json:"total"`
-	Remark      string       `json:"remark"`
-	Enable      bool         `json:"enable"`
-	ExpiryTime  int64        `json:"expiryTime"`
-	ClientStats []ClientStat `json:"clientStats"`
-	Listen      string       `json:"listen"`
-	Port        int          `json:"port"`
-	Protocol    string       `json:"protocol"`
-	Settings    string       `json:"settings"`
+	ID             int          `json:"id"`
+	Up             int64        `json:"up"`
+	Down           int64        `json:"down"`
+	Total          int64        `json:"total"`
+	Remark         string       `json:"remark"`
+	Enable         bool         `json:"enable"`
+	ExpiryTime     int64        `json:"expiryTime"`
+	ClientStats    []ClientStat `json:"clientStats"`
+	Listen         string       `json:"listen"`
+	Port           int          `json:"port"`
+	Protocol       string       `json:"protocol"`
+	Settings       string       `json:"settings"`
+	StreamSettings string       `json:"streamSettings"`
+}
+
+// UsesPassword reports whether clients of the inbound authenticate with a password rather
+// than with their ID, as VLESS and VMess clients do
+func (i Inbound) UsesPassword() bool {
+	return i.Protocol == "trojan" || i.Protocol == "shadowsocks"
+}
+
+// ClientPassword returns the password a client of the inbound gets for the member's secret,
+// or an empty string if the protocol doesn't use passwords. Shadowsocks 2022 AES-128 keys
+// are 16 bytes, so they take the first half of the secret.
+func (i Inbound) ClientPassword(secret string) string {
+	if secret == "" || !i.UsesPassword() {
+		return ""
+	}
+
+	if i.Protocol == "shadowsocks" {
+		var settings struct {
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal([]byte(i.Settings), &settings); err == nil && settings.Method == "2022-blake3-aes-128-gcm" {
+			if key, err := base64.StdEncoding.DecodeString(secret); err == nil && len(key) >= 16 {
+				return base64.StdEncoding.EncodeToString(key[:16])
+			}
+		}
+	}
+
+	return secret
+}
+
+// ClientKey returns the value the panel identifies a client of the inbound by in update and
+// delete requests: the password for Trojan, the email for Shadowsocks and the ID otherwise.
+// Trojan clients without a password fall back to their ID.
+func (i Inbound) ClientKey(client InboundClient) string {
+	switch i.Protocol {
+	case "trojan":
+		if client.Password == "" {
+			return client.ID
+		}
+		return client.Password
+	case "shadowsocks":
+		return client.Email
+	default:
+		return client.ID
+	}
 }
 
 // ClientStat represents statistics for a client
@@ -27,6 +80,12 @@ type ClientStat struct {
 	ExpiryTime int64  `json:"expiryTime"`
 	Total      int64  `json:"total"`
 	Reset      int64  `json:"reset"`
+	LastOnline int64  `json:"lastOnline"` // milliseconds, zero on panels that don't track it
+}
+
+// IsActive reports whether the client is enabled and its subscription hasn't expired
+func (cs ClientStat) IsActive() bool {
+	return cs.Enable && (cs.ExpiryTime == 0 || time.Now().UnixMilli() <= cs.ExpiryTime)
 }
 
 // InboundSettings represents the parsed settings of an inbound
@@ -34,12 +93,108 @@ type InboundSettings struct {
 	Clients []InboundClient `json:"clients"`
 }
 
+// StreamSettings represents the parsed transport and security settings of an inbound
+type StreamSettings struct {
+	Network         string          `json:"network"`
+	Security        string          `json:"security"`
+	TLSSettings     TLSSettings     `json:"tlsSettings"`
+	RealitySettings RealitySettings `json:"realitySettings"`
+	WSSettings      WSSettings      `json:"wsSettings"`
+	GRPCSettings    GRPCSettings    `json:"grpcSettings"`
+}
+
+// TLSSettings represents the TLS settings of an inbound
+type TLSSettings struct {
+	ServerName string `json:"serverName"`
+	Settings   struct {
+		Fingerprint string `json:"fingerprint"`
+	} `json:"settings"`
+}
+
+// RealitySettings represents the REALITY settings of an inbound
+type RealitySettings struct {
+	ServerNames []string `json:"serverNames"`
+	ShortIDs    []string `json:"shortIds"`
+	Settings    struct {
+		PublicKey   string `json:"publicKey"`
+		Fingerprint string `json:"fingerprint"`
+		SpiderX     string `json:"spiderX"`
+	} `json:"settings"`
+}
+
+// WSSettings represents the WebSocket transport settings of an inbound
+type WSSettings struct {
+	Path    string            `json:"path"`
+	Host    string            `json:"host"`
+	Headers map[string]string `json:"headers"`
+}
+
+// GRPCSettings represents the gRPC transport settings of an inbound
+type GRPCSettings struct {
+	ServiceName string `json:"serviceName"`
+}
+
 // InboundClient represents a client in inbound settings
 type InboundClient struct {
-	ID         string `json:"id"`
-	Email      string `json:"email"`
-	Enable     bool   `json:"enable"`
-	ExpiryTime int64  `json:"expiryTime"`
-	SubID      string `json:"subId"`
-	TgID       string `json:"tgId"`
+	ID          string `json:"id"`
+	Email       string `json:"email"`
+	Enable      bool   `json:"enable"`
+	Flow        string `json:"flow"`
+	TotalGB     int64  `json:"totalGB"`
+	LimitIP     int    `json:"limitIp"`
+	ExpiryTime  int64  `json:"expiryTime"`
+	Fingerprint string `json:"fingerprint"`
+	SubID       string `json:"subId"`
+	TgID        string `json:"tgId"`
+	Password    string `json:"password"`
+
+	// Raw holds every field of the client as the panel sent it, including the ones the bot
+	// doesn't model (reset, comment, ...), so updates can send them back unchanged
+	Raw map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the client and keeps all of its fields in Raw
+func (ic *InboundClient) UnmarshalJSON(data []byte) error {
+	type plain InboundClient
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &decoded.Raw); err != nil {
+		return err
+	}
+	*ic = InboundClient(decoded)
+	return nil
+}
+
+// InboundClientRef is a client together with the ID of the inbound it belongs to
+type InboundClientRef struct {
+	InboundID int
+	Client    InboundClient
+}
+
+// ToClient converts the inbound client to a Client suitable for update requests. Fields
+// the bot doesn't model are carried over, so an update doesn't reset them on the panel.
+func (ic InboundClient) ToClient() Client {
+	expiryTime := ic.ExpiryTime
+	client := Client{
+		ID:          ic.ID,
+		Enable:      ic.Enable,
+		Email:       ic.Email,
+		TotalGB:     int(ic.TotalGB),
+		LimitIP:     ic.LimitIP,
+		ExpiryTime:  &expiryTime,
+		Fingerprint: ic.Fingerprint,
+		TgID:        ic.TgID,
+		SubID:       ic.SubID,
+		Password:    ic.Password,
+		Extra:       maps.Clone(ic.Raw),
+	}
+
+	if ic.Flow != "" {
+		flow := ic.Flow
+		client.Flow = &flow
+	}
+
+	return client
 }
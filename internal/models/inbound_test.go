@@ -0,0 +1,94 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInboundClientToClientKeepsPanelFields(t *testing.T) {
+	raw := `{"clients":[{"id":"0b8c6f5e-1111-4a2b-9c3d-123456789abc","email":"alice-1","enable":true,` +
+		`"flow":"xtls-rprx-vision","totalGB":1073741824,"limitIp":2,"expiryTime":1700000000000,` +
+		`"fingerprint":"abc-1","subId":"sub123","tgId":"42","reset":30,"comment":"family plan",` +
+		`"created_at":1690000000000}]}`
+
+	var settings InboundSettings
+	if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+		t.Fatalf("failed to parse settings: %v", err)
+	}
+	if len(settings.Clients) != 1 {
+		t.Fatalf("parsed %d clients, want 1", len(settings.Clients))
+	}
+
+	client := settings.Clients[0].ToClient()
+	client.Enable = false
+	client.Email = "alice-renamed-1"
+
+	body, err := json.Marshal(client.ToDictionary())
+	if err != nil {
+		t.Fatalf("failed to marshal client: %v", err)
+	}
+	var sent map[string]interface{}
+	if err := json.Unmarshal(body, &sent); err != nil {
+		t.Fatalf("failed to parse marshalled client: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"id":          "0b8c6f5e-1111-4a2b-9c3d-123456789abc",
+		"email":       "alice-renamed-1",
+		"enable":      false,
+		"flow":        "xtls-rprx-vision",
+		"totalGB":     float64(1073741824),
+		"limitIp":     float64(2),
+		"expiryTime":  float64(1700000000000),
+		"fingerprint": "abc-1",
+		"subId":       "sub123",
+		"tgId":        "42",
+		"reset":       float64(30),
+		"comment":     "family plan",
+		"created_at":  float64(1690000000000),
+	}
+	for key, value := range want {
+		if sent[key] != value {
+			t.Errorf("%s = %v, want %v", key, sent[key], value)
+		}
+	}
+	if len(sent) != len(want) {
+		t.Errorf("sent %d fields, want %d: %v", len(sent), len(want), sent)
+	}
+}
+
+func TestInboundClientToClientDoesNotShareRaw(t *testing.T) {
+	var ic InboundClient
+	if err := json.Unmarshal([]byte(`{"id":"x","email":"bob","comment":"a"}`), &ic); err != nil {
+		t.Fatalf("failed to parse client: %v", err)
+	}
+
+	client := ic.ToClient()
+	client.Extra["comment"] = json.RawMessage(`"b"`)
+	if string(ic.Raw["comment"]) != `"a"` {
+		t.Errorf("changing the client's Extra changed the inbound client: %s", ic.Raw["comment"])
+	}
+}
+
+func TestInboundClientKey(t *testing.T) {
+	client := InboundClient{ID: "0b8c6f5e-1111-4a2b-9c3d-123456789abc", Email: "alice-1", Password: "secret"}
+	noPassword := InboundClient{ID: "0b8c6f5e-1111-4a2b-9c3d-123456789abc", Email: "alice-1"}
+
+	tests := []struct {
+		protocol string
+		client   InboundClient
+		want     string
+	}{
+		{protocol: "vless", client: client, want: client.ID},
+		{protocol: "vmess", client: client, want: client.ID},
+		{protocol: "trojan", client: client, want: "secret"},
+		{protocol: "trojan", client: noPassword, want: client.ID},
+		{protocol: "shadowsocks", client: client, want: "alice-1"},
+	}
+
+	for _, tt := range tests {
+		if got := (Inbound{Protocol: tt.protocol}).ClientKey(tt.client); got != tt.want {
+			t.Errorf("ClientKey on %s with password %q = %q, want %q", tt.protocol, tt.client.Password, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,10 @@
 package models
 
 import (
-	"fmt"
 	"sort"
 	"time"
+
+	"xui-tg-admin/internal/i18n"
 )
 
 // SortType представляет тип сортировки пользователей
@@ -28,23 +29,24 @@ type MemberInfo struct {
 	TotalDown    int64    // Общий скачанный трафик
 	TotalTraffic int64    // Общий трафик (Up + Down)
 	IsExpired    bool     // Истек ли срок действия
+	LastOnline   int64    // Последняя активность (миллисекунды), 0 если панель не сообщает
 }
 
 // GetSortName возвращает читаемое название типа сортировки
-func (st SortType) GetSortName() string {
+func (st SortType) GetSortName(l *i18n.Localizer) string {
 	switch st {
 	case SortByCreationOrder:
-		return "📅 По дате добавления"
+		return l.T(i18n.SortByCreationOrder)
 	case SortByExpiryDate:
-		return "⏰ По дате истечения"
+		return l.T(i18n.SortByExpiryDate)
 	case SortByTrafficTotal:
-		return "📊 По общему трафику"
+		return l.T(i18n.SortByTrafficTotal)
 	case SortByStatus:
-		return "🔄 По статусу"
+		return l.T(i18n.SortByStatus)
 	case SortByName:
-		return "🔤 По имени"
+		return l.T(i18n.SortByName)
 	default:
-		return "📅 По дате добавления"
+		return l.T(i18n.SortByCreationOrder)
 	}
 }
 
@@ -57,25 +59,25 @@ func (m *MemberInfo) IsExpiredMember() bool {
 }
 
 // GetExpiryStatus возвращает статус истечения в читаемом виде
-func (m *MemberInfo) GetExpiryStatus() string {
+func (m *MemberInfo) GetExpiryStatus(l *i18n.Localizer) string {
 	if m.ExpiryTime == 0 {
-		return "∞ Бессрочный"
+		return l.T(i18n.ExpiryNever)
 	}
 
 	if m.IsExpiredMember() {
-		return "❌ Истек"
+		return l.T(i18n.ExpiryExpired)
 	}
 
 	expiryDate := time.Unix(m.ExpiryTime/1000, 0)
 	daysLeft := int(time.Until(expiryDate).Hours() / 24)
 
 	if daysLeft <= 0 {
-		return "⚠️ Истекает сегодня"
+		return l.T(i18n.ExpiryToday)
 	} else if daysLeft <= 7 {
-		return fmt.Sprintf("⚠️ %d дн.", daysLeft)
+		return l.T(i18n.ExpiryDaysLeftSoon, daysLeft)
 	}
 
-	return fmt.Sprintf("✅ %d дн.", daysLeft)
+	return l.T(i18n.ExpiryDaysLeft, daysLeft)
 }
 
 // SortMembers сортирует список пользователей по указанному типу
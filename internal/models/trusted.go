@@ -11,7 +11,7 @@ type TrustedUser struct {
 type VpnAccount struct {
 	ID        int    `json:"id"`
 	Username  string `json:"username"`
-	Password  string `json:"password"`
+	Password  string `json:"password"` // secret for Trojan/Shadowsocks inbounds, empty if none uses one
 	AddedBy   int64  `json:"added_by"`
 	CreatedAt int64  `json:"created_at"`
 }
\ No newline at end of file
@@ -1,5 +1,11 @@
 package models
 
+import (
+	"time"
+
+	"xui-tg-admin/internal/permissions"
+)
+
 // ConversationState represents the state of a conversation with a user
 type ConversationState int
 
@@ -24,6 +30,32 @@ const (
 	StateAwaitingVpnUsername
 	// StateAwaitingVpnPassword is the state when trusted user is inputting VPN password
 	StateAwaitingVpnPassword
+	// AwaitingRenameUserName is the state when admin is inputting a new username for a member
+	AwaitingRenameUserName
+	// AwaitingRestoreDocument is the state when admin is uploading a backup file
+	AwaitingRestoreDocument
+	// AwaitConfirmRestore is the state when admin is confirming a backup restore
+	AwaitConfirmRestore
+	// AwaitingAdminIdentifier is the state when admin is inputting the ID or username of a new admin
+	AwaitingAdminIdentifier
+	// AwaitConfirmDeleteExpired is the state when admin is confirming deletion of all expired members
+	AwaitConfirmDeleteExpired
+	// AwaitConfirmPruneAccounts is the state when admin is confirming removal of orphaned VPN account records
+	AwaitConfirmPruneAccounts
+	// AwaitBulkSelection is the state when admin is selecting members to enable or disable at once
+	AwaitBulkSelection
+	// AwaitingUserNote is the state when admin is inputting a note for a member
+	AwaitingUserNote
+	// AwaitingUserTags is the state when admin is adding or removing tags of a member
+	AwaitingUserTags
+	// AwaitingTrafficLimit is the state when admin is inputting a new traffic limit for a member
+	AwaitingTrafficLimit
+	// AwaitConfirmInboundToggle is the state when admin is confirming enabling or disabling an inbound
+	AwaitConfirmInboundToggle
+	// AwaitingBroadcastMessage is the state when admin is inputting an announcement for all users
+	AwaitingBroadcastMessage
+	// AwaitConfirmBroadcast is the state when admin is confirming an announcement and its recipients
+	AwaitConfirmBroadcast
 )
 
 // Additional state constants for trusted user functionality
@@ -37,4 +69,34 @@ type UserState struct {
 	Payload    *string
 	SortType   *SortType // Хранит выбранный тип сортировки
 	ActionType *string   // Хранит тип действия (edit/delete)
+	// ConfirmRequestedAt is when the pending destructive confirmation was shown
+	ConfirmRequestedAt *time.Time
+	// Selected holds the members picked for a bulk operation
+	Selected map[string]bool
+	// Page is the page of the bulk selection keyboard being shown
+	Page int
+	// ActiveOnly limits reports to enabled, unexpired subscriptions
+	ActiveOnly bool
+	// FailedCreation is the last member creation that failed, kept for a retry
+	FailedCreation *FailedCreation
+	// ActingAs is the access level an admin sees the bot as after /as, kept when the state is cleared
+	ActingAs *permissions.AccessType
+}
+
+// FailedCreation holds what is needed to repeat a member creation without prompting again
+type FailedCreation struct {
+	BaseUsername string
+	DurationStr  string
+	ExpiryTime   int64
+	SubID        string
+	Password     string
+	FailedAt     time.Time
+}
+
+// IsConfirmationExpired reports whether the pending confirmation is missing or older than ttl
+func (s *UserState) IsConfirmationExpired(ttl time.Duration) bool {
+	if s.ConfirmRequestedAt == nil {
+		return true
+	}
+	return time.Since(*s.ConfirmRequestedAt) > ttl
 }
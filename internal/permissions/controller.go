@@ -16,6 +16,18 @@ const (
 	Trusted
 )
 
+// String returns the display name of the access type
+func (a AccessType) String() string {
+	switch a {
+	case Admin:
+		return "Admin"
+	case Trusted:
+		return "Trusted"
+	default:
+		return "None"
+	}
+}
+
 // PermissionController manages user permissions
 type PermissionController struct {
 	adminIDs       map[int64]bool
@@ -23,8 +35,9 @@ type PermissionController struct {
 	logger         *logrus.Logger
 }
 
-// StorageService interface for trusted user storage
+// StorageService interface for trusted user and runtime admin storage
 type StorageService interface {
+	IsAdmin(telegramID int64) bool
 	IsTrusted(telegramID int64) bool
 	IsTrustedByUsername(username string) (bool, int64)
 	UpdateTrustedUserTelegramID(username string, realTelegramID int64) error
@@ -61,9 +74,12 @@ func (p *PermissionController) GetAccessType(userID int64) AccessType {
 	return None
 }
 
-// IsAdmin checks if a user is an admin
+// IsAdmin checks if a user is an admin, either from TG_ADMIN_IDS or added at runtime
 func (p *PermissionController) IsAdmin(userID int64) bool {
 	isAdmin := p.adminIDs[userID]
+	if !isAdmin && p.storageService != nil {
+		isAdmin = p.storageService.IsAdmin(userID)
+	}
 	p.logger.Debugf("Checking if user %d is admin: %v", userID, isAdmin)
 	return isAdmin
 }
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/sirupsen/logrus"
+
+	"xui-tg-admin/internal/helpers"
+)
+
+const (
+	chartWidth      = 800
+	chartPadding    = 20
+	chartBarHeight  = 28
+	chartBarSpacing = 12
+)
+
+var (
+	chartBackground = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	chartAxis       = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+	chartDownColor  = color.RGBA{R: 52, G: 120, B: 246, A: 255}
+	chartUpColor    = color.RGBA{R: 255, G: 149, B: 0, A: 255}
+)
+
+// ChartService renders traffic charts as PNG images
+type ChartService struct {
+	logger *logrus.Logger
+}
+
+// NewChartService creates a new chart service
+func NewChartService(logger *logrus.Logger) *ChartService {
+	return &ChartService{
+		logger: logger,
+	}
+}
+
+// RenderTrafficChart renders a horizontal bar chart of per-user traffic for the top N users.
+// Each bar is split into download (blue) and upload (orange); bars follow the order of users.
+func (s *ChartService) RenderTrafficChart(users []*helpers.UserTrafficSummary, topN int) ([]byte, error) {
+	if topN > 0 && len(users) > topN {
+		users = users[:topN]
+	}
+	if len(users) == 0 {
+		return nil, errors.New("no traffic data to render")
+	}
+
+	var maxTotal int64
+	for _, user := range users {
+		if total := user.TotalUp + user.TotalDown; total > maxTotal {
+			maxTotal = total
+		}
+	}
+
+	height := chartPadding*2 + len(users)*(chartBarHeight+chartBarSpacing) - chartBarSpacing
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: chartBackground}, image.Point{}, draw.Src)
+
+	// Draw the baseline axis
+	draw.Draw(img, image.Rect(chartPadding-2, chartPadding, chartPadding, height-chartPadding), &image.Uniform{C: chartAxis}, image.Point{}, draw.Src)
+
+	plotWidth := chartWidth - chartPadding*2
+	for i, user := range users {
+		top := chartPadding + i*(chartBarHeight+chartBarSpacing)
+		bottom := top + chartBarHeight
+
+		downWidth, upWidth := 0, 0
+		if maxTotal > 0 {
+			downWidth = int(float64(user.TotalDown) / float64(maxTotal) * float64(plotWidth))
+			upWidth = int(float64(user.TotalUp) / float64(maxTotal) * float64(plotWidth))
+		}
+
+		draw.Draw(img, image.Rect(chartPadding, top, chartPadding+downWidth, bottom), &image.Uniform{C: chartDownColor}, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(chartPadding+downWidth, top, chartPadding+downWidth+upWidth, bottom), &image.Uniform{C: chartUpColor}, image.Point{}, draw.Src)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		s.logger.Errorf("Failed to encode traffic chart: %v", err)
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
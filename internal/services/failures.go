@@ -0,0 +1,62 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// failureTracker counts recent failures per operation within a sliding window
+type failureTracker struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	failures  map[string][]time.Time
+	alerted   map[string]bool
+}
+
+// newFailureTracker creates a tracker that trips after threshold failures within window
+func newFailureTracker(threshold int, window time.Duration) *failureTracker {
+	return &failureTracker{
+		threshold: threshold,
+		window:    window,
+		failures:  make(map[string][]time.Time),
+		alerted:   make(map[string]bool),
+	}
+}
+
+// RecordFailure records a failed operation and returns the number of failures in the window.
+// The second return value is true only when the threshold is first reached, so admins are
+// alerted once per outage instead of on every failed request.
+func (t *failureTracker) RecordFailure(operation string) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-t.window)
+
+	// Drop failures that fell out of the window
+	recent := t.failures[operation][:0]
+	for _, ts := range t.failures[operation] {
+		if ts.After(cutoff) {
+			recent = append(recent, ts)
+		}
+	}
+	recent = append(recent, now)
+	t.failures[operation] = recent
+
+	if len(recent) < t.threshold || t.alerted[operation] {
+		return len(recent), false
+	}
+
+	t.alerted[operation] = true
+	return len(recent), true
+}
+
+// RecordSuccess clears the failures of an operation once it works again
+func (t *failureTracker) RecordSuccess(operation string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.failures, operation)
+	delete(t.alerted, operation)
+}
@@ -1,32 +1,154 @@
 package services
 
 import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"strings"
+
 	"github.com/sirupsen/logrus"
 	"github.com/skip2/go-qrcode"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"xui-tg-admin/internal/config"
+	"xui-tg-admin/internal/constants"
 )
 
 // QRService provides QR code generation functionality
 type QRService struct {
-	logger *logrus.Logger
+	size          int
+	recoveryLevel qrcode.RecoveryLevel
+	label         string // printed under every QR image when QR_LABEL is on
+	logger        *logrus.Logger
 }
 
 // NewQRService creates a new QR code service
-func NewQRService(logger *logrus.Logger) *QRService {
+func NewQRService(cfg *config.Config, logger *logrus.Logger) *QRService {
+	size := cfg.QR.Size
+	if size == 0 {
+		size = constants.DefaultQRSize
+	}
+
+	var label string
+	if cfg.QR.Label {
+		label = cfg.Server.Name
+	}
+
 	return &QRService{
-		logger: logger,
+		size:          size,
+		recoveryLevel: parseRecoveryLevel(cfg.QR.RecoveryLevel),
+		label:         label,
+		logger:        logger,
 	}
 }
 
-// GenerateQR generates a QR code for the given text
+// GenerateQR generates a QR code for the given text using the configured size
 func (s *QRService) GenerateQR(text string) ([]byte, error) {
-	s.logger.Debugf("Generating QR code for text: %s", text)
+	return s.GenerateQRWithSize(text, s.size)
+}
+
+// GenerateQRWithSize generates a QR code for the given text with an explicit size in pixels
+func (s *QRService) GenerateQRWithSize(text string, size int) ([]byte, error) {
+	s.logger.Debugf("Generating %dpx QR code for text: %s", size, text)
 
-	// Generate QR code with medium recovery level and size 256
-	qr, err := qrcode.Encode(text, qrcode.Medium, 256)
+	if size < constants.MinQRSize || size > constants.MaxQRSize {
+		return nil, fmt.Errorf("QR size must be between %d and %d, got %d", constants.MinQRSize, constants.MaxQRSize, size)
+	}
+
+	qr, err := qrcode.Encode(text, s.recoveryLevel, size)
 	if err != nil {
 		s.logger.Errorf("Failed to generate QR code: %v", err)
 		return nil, err
 	}
 
-	return qr, nil
+	if s.label == "" {
+		return qr, nil
+	}
+	labeled, err := labelQR(qr, s.label)
+	if err != nil {
+		// The bare code still works, so send it rather than nothing
+		s.logger.Warnf("Failed to add label to QR code: %v", err)
+		return qr, nil
+	}
+	return labeled, nil
+}
+
+// labelQR adds a white band under a QR code PNG with the label centered in it. The text is
+// drawn with the built-in 7x13 font, scaled up with the image, so characters outside ASCII
+// are left out and a label too wide for the image is cut short.
+func labelQR(qr []byte, label string) ([]byte, error) {
+	code, err := png.Decode(bytes.NewReader(qr))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode QR code: %w", err)
+	}
+	bounds := code.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	face := basicfont.Face7x13
+	scale := max(1, width/constants.QRLabelScaleStep)
+	text := fitLabel(label, width/scale/face.Advance)
+	if text == "" {
+		return qr, nil
+	}
+
+	// Draw the text at the font's size, then scale the whole band to the image
+	lineHeight := face.Height + 2*constants.QRLabelPadding
+	band := image.NewRGBA(image.Rect(0, 0, width/scale, lineHeight))
+	draw.Draw(band, band.Bounds(), image.White, image.Point{}, draw.Src)
+	drawer := font.Drawer{Dst: band, Src: image.Black, Face: face}
+	drawer.Dot = fixed.Point26_6{
+		X: (fixed.I(band.Bounds().Dx()) - drawer.MeasureString(text)) / 2,
+		Y: fixed.I(constants.QRLabelPadding + face.Ascent),
+	}
+	drawer.DrawString(text)
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height+lineHeight*scale))
+	draw.Draw(out, out.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(out, image.Rect(0, 0, width, height), code, bounds.Min, draw.Src)
+	xdraw.NearestNeighbor.Scale(out, image.Rect(0, height, band.Bounds().Dx()*scale, height+lineHeight*scale), band, band.Bounds(), draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return nil, fmt.Errorf("failed to encode labeled QR code: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fitLabel keeps the printable ASCII characters of the label, which the built-in font has
+// glyphs for, and cuts it to at most maxChars with an ellipsis
+func fitLabel(label string, maxChars int) string {
+	text := strings.Map(func(r rune) rune {
+		if r < ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, label)
+	text = strings.Join(strings.Fields(text), " ")
+
+	if len(text) <= maxChars {
+		return text
+	}
+	if maxChars <= 3 {
+		return ""
+	}
+	return text[:maxChars-3] + "..."
+}
+
+// parseRecoveryLevel converts a config value to a QR recovery level, defaulting to medium
+func parseRecoveryLevel(level string) qrcode.RecoveryLevel {
+	switch level {
+	case "low":
+		return qrcode.Low
+	case "high":
+		return qrcode.High
+	case "highest":
+		return qrcode.Highest
+	default:
+		return qrcode.Medium
+	}
 }
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"xui-tg-admin/internal/config"
+)
+
+func newTestQRService(t *testing.T, label bool) *QRService {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cfg := &config.Config{
+		Server: config.ServerConfig{Name: "vpn.example.com"},
+		QR:     config.QRConfig{Size: 256, Label: label},
+	}
+	return NewQRService(cfg, logger)
+}
+
+func decodePNG(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("QR code isn't a PNG: %v", err)
+	}
+	return img
+}
+
+func TestGenerateQRLabel(t *testing.T) {
+	plain, err := newTestQRService(t, false).GenerateQR("https://sub.example.com/abc")
+	if err != nil {
+		t.Fatalf("GenerateQR returned error: %v", err)
+	}
+	if b := decodePNG(t, plain).Bounds(); b.Dx() != 256 || b.Dy() != 256 {
+		t.Fatalf("unlabeled QR code is %dx%d, want 256x256", b.Dx(), b.Dy())
+	}
+
+	labeled, err := newTestQRService(t, true).GenerateQR("https://sub.example.com/abc")
+	if err != nil {
+		t.Fatalf("GenerateQR returned error: %v", err)
+	}
+	img := decodePNG(t, labeled)
+	b := img.Bounds()
+	if b.Dx() != 256 || b.Dy() <= 256 {
+		t.Fatalf("labeled QR code is %dx%d, want 256 wide with a band under the code", b.Dx(), b.Dy())
+	}
+
+	// The band under the code has the text in it
+	dark := 0
+	for y := 256; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			if r, _, _, _ := img.At(x, y).RGBA(); r < 0x8000 {
+				dark++
+			}
+		}
+	}
+	if dark == 0 {
+		t.Error("the label band is blank")
+	}
+}
+
+func TestFitLabel(t *testing.T) {
+	tests := []struct {
+		label    string
+		maxChars int
+		want     string
+	}{
+		{"vpn.example.com", 36, "vpn.example.com"},
+		{"Сервер  NL-1", 36, "NL-1"},
+		{"a-very-long-server-name.example.com", 20, "a-very-long-serve..."},
+		{"Сервер", 36, ""},
+		{"vpn.example.com", 3, ""},
+	}
+
+	for _, tt := range tests {
+		if got := fitLabel(tt.label, tt.maxChars); got != tt.want {
+			t.Errorf("fitLabel(%q, %d) = %q, want %q", tt.label, tt.maxChars, got, tt.want)
+		}
+	}
+}
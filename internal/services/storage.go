@@ -2,7 +2,12 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
@@ -11,38 +16,98 @@ import (
 	"xui-tg-admin/internal/models"
 )
 
+// storageSchemaVersion is the StorageData format written by this build. Files without
+// a version predate versioning and are treated as version 1.
+const storageSchemaVersion = 3
+
+// ErrStorageTooNew is returned when the storage file was written by a newer build
+var ErrStorageTooNew = errors.New("storage file schema is newer than supported")
+
+// Errors returned by TransferVpnAccount
+var (
+	ErrAccountNotFound     = errors.New("vpn account not found")
+	ErrTransferTarget      = errors.New("transfer target is not a trusted user")
+	ErrTransferSameOwner   = errors.New("vpn account already belongs to the target")
+	ErrAccountLimitReached = errors.New("target has reached the account limit")
+)
+
+// storageMigrations upgrade StorageData one version at a time; the migration at index i
+// upgrades version i+1 to i+2
+var storageMigrations = []func(*StorageData){
+	migrateStorageV1,
+	migrateStorageV2,
+}
+
 // StorageData represents the JSON structure stored in data.json
 type StorageData struct {
-	TrustedUsers []models.TrustedUser `json:"trusted_users"`
-	VpnAccounts  []models.VpnAccount  `json:"vpn_accounts"`
-	NextID       int                  `json:"next_id"`
+	SchemaVersion int                  `json:"schema_version"`
+	TrustedUsers  []models.TrustedUser `json:"trusted_users"`
+	VpnAccounts   []models.VpnAccount  `json:"vpn_accounts"`
+	AdminUsers    []models.AdminUser   `json:"admin_users"`
+	Notes         map[string]string    `json:"notes,omitempty"`     // admin notes keyed by base username
+	Tags          map[string][]string  `json:"tags,omitempty"`      // admin tags keyed by base username
+	Passwords     map[string]string    `json:"passwords,omitempty"` // generated passwords of admin-created members keyed by base username
+	NextID        int                  `json:"next_id"`
+
+	Reminders    map[string]int64 `json:"reminders,omitempty"`     // expiry time an account's owner was last reminded of, keyed by base username
+	RemindersOff []int64          `json:"reminders_off,omitempty"` // Telegram IDs that opted out of expiry reminders
 }
 
 // StorageService handles JSON file operations for trusted users and VPN accounts
 type StorageService struct {
 	filename string
+	lock     *os.File       // advisory lock held for the lifetime of the service
+	cipher   *storageCipher // encrypts the file at rest when a key is configured
 	data     *StorageData
 	mu       sync.RWMutex
 	logger   *logrus.Logger
 }
 
-// NewStorageService creates a new storage service
-func NewStorageService(filename string, logger *logrus.Logger) *StorageService {
+// NewStorageService creates a new storage service. The file is encrypted with key when
+// it is set; previousKeys still open files written before a key rotation. It fails if
+// another process already holds the lock on the same storage file.
+func NewStorageService(filename, key string, previousKeys []string, logger *logrus.Logger) (*StorageService, error) {
+	storageCipher, err := newStorageCipher(key, previousKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := lockStorageFile(filename + ".lock")
+	if err != nil {
+		return nil, err
+	}
+
 	s := &StorageService{
+		lock:     lock,
+		cipher:   storageCipher,
 		filename: filename,
 		data: &StorageData{
-			TrustedUsers: make([]models.TrustedUser, 0),
-			VpnAccounts:  make([]models.VpnAccount, 0),
-			NextID:       1,
+			SchemaVersion: storageSchemaVersion,
+			TrustedUsers:  make([]models.TrustedUser, 0),
+			VpnAccounts:   make([]models.VpnAccount, 0),
+			AdminUsers:    make([]models.AdminUser, 0),
+			Notes:         make(map[string]string),
+			Tags:          make(map[string][]string),
+			NextID:        1,
 		},
 		logger: logger,
 	}
 
 	if err := s.Load(); err != nil {
+		// Saving over a newer or undecryptable file would lose its contents
+		if errors.Is(err, ErrStorageTooNew) || errors.Is(err, ErrStorageKey) {
+			lock.Close()
+			return nil, err
+		}
 		logger.Warnf("Failed to load storage file: %v", err)
 	}
 
-	return s
+	return s, nil
+}
+
+// Close releases the storage file lock
+func (s *StorageService) Close() error {
+	return s.lock.Close()
 }
 
 // Load reads data from JSON file
@@ -50,6 +115,8 @@ func (s *StorageService) Load() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.cleanupTempFile()
+
 	data, err := os.ReadFile(s.filename)
 	if os.IsNotExist(err) {
 		s.logger.Info("Storage file does not exist, starting with empty data")
@@ -59,7 +126,119 @@ func (s *StorageService) Load() error {
 		return err
 	}
 
-	return json.Unmarshal(data, s.data)
+	data, stale, err := s.cipher.decrypt(data)
+	if err != nil {
+		return err
+	}
+
+	// Files written before versioning have no schema_version
+	s.data.SchemaVersion = 0
+	if err := json.Unmarshal(data, s.data); err != nil {
+		return err
+	}
+
+	if err := s.migrate(); err != nil {
+		return err
+	}
+
+	// Rewrite the file so it matches the current key, or is plain JSON if the key was removed
+	if stale {
+		s.logger.Info("Re-writing storage file with the current encryption settings")
+		return s.save()
+	}
+	return nil
+}
+
+// cleanupTempFile deals with a temp file left by a save interrupted before its rename.
+// It is only trusted when the storage file itself is missing and it holds readable JSON;
+// otherwise the storage file is authoritative and the temp file is removed.
+func (s *StorageService) cleanupTempFile() {
+	tmpFile := s.filename + ".tmp"
+	data, err := os.ReadFile(tmpFile)
+	if os.IsNotExist(err) {
+		return
+	}
+
+	if _, statErr := os.Stat(s.filename); os.IsNotExist(statErr) && err == nil && s.readable(data) {
+		if err := os.Rename(tmpFile, s.filename); err != nil {
+			s.logger.Errorf("Failed to recover storage from %s: %v", tmpFile, err)
+			return
+		}
+		s.logger.Warnf("Recovered storage file from interrupted save %s", tmpFile)
+		return
+	}
+
+	if err := os.Remove(tmpFile); err != nil {
+		s.logger.Errorf("Failed to remove stale temp file %s: %v", tmpFile, err)
+		return
+	}
+	s.logger.Warnf("Removed stale temp file %s left by an interrupted save", tmpFile)
+}
+
+// readable reports whether raw decrypts, if needed, to valid JSON
+func (s *StorageService) readable(raw []byte) bool {
+	data, _, err := s.cipher.decrypt(raw)
+	return err == nil && json.Valid(data)
+}
+
+// migrate upgrades data loaded from an older file to the current schema and saves it
+func (s *StorageService) migrate() error {
+	version := s.data.SchemaVersion
+	if version == 0 {
+		version = 1
+	}
+	if version > storageSchemaVersion {
+		return fmt.Errorf("%w: file has version %d, this build supports up to %d", ErrStorageTooNew, version, storageSchemaVersion)
+	}
+	if version == storageSchemaVersion {
+		return nil
+	}
+
+	for ; version < storageSchemaVersion; version++ {
+		storageMigrations[version-1](s.data)
+	}
+	s.data.SchemaVersion = storageSchemaVersion
+
+	s.logger.Infof("Migrated storage file to schema version %d", storageSchemaVersion)
+	return s.save()
+}
+
+// migrateStorageV1 fills in collections missing from files written before versioning
+// and makes sure NextID doesn't reuse an existing account ID
+func migrateStorageV1(data *StorageData) {
+	if data.TrustedUsers == nil {
+		data.TrustedUsers = make([]models.TrustedUser, 0)
+	}
+	if data.VpnAccounts == nil {
+		data.VpnAccounts = make([]models.VpnAccount, 0)
+	}
+	if data.AdminUsers == nil {
+		data.AdminUsers = make([]models.AdminUser, 0)
+	}
+	if data.Notes == nil {
+		data.Notes = make(map[string]string)
+	}
+	if data.Tags == nil {
+		data.Tags = make(map[string][]string)
+	}
+
+	for _, account := range data.VpnAccounts {
+		if account.ID >= data.NextID {
+			data.NextID = account.ID + 1
+		}
+	}
+	if data.NextID < 1 {
+		data.NextID = 1
+	}
+}
+
+// migrateStorageV2 clears the placeholder password older builds stored for every account
+func migrateStorageV2(data *StorageData) {
+	for i := range data.VpnAccounts {
+		if data.VpnAccounts[i].Password == "auto-generated" {
+			data.VpnAccounts[i].Password = ""
+		}
+	}
 }
 
 // Save writes data to JSON file atomically
@@ -72,12 +251,7 @@ func (s *StorageService) Save() error {
 		return err
 	}
 
-	tmpFile := s.filename + ".tmp"
-	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
-		return err
-	}
-
-	return os.Rename(tmpFile, s.filename)
+	return s.writeFile(data)
 }
 
 // IsTrusted checks if a user is in the trusted list
@@ -165,6 +339,102 @@ func (s *StorageService) GetTrustedUsers() []models.TrustedUser {
 	return users
 }
 
+// IsAdmin checks if a user is in the runtime admin list
+func (s *StorageService) IsAdmin(telegramID int64) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, admin := range s.data.AdminUsers {
+		if admin.TelegramID == telegramID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdminByUsername checks if a username is in the admin list and returns the stored telegram ID
+func (s *StorageService) IsAdminByUsername(username string) (bool, int64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, admin := range s.data.AdminUsers {
+		if admin.Username != "" && admin.Username == username {
+			return true, admin.TelegramID
+		}
+	}
+	return false, 0
+}
+
+// UpdateAdminTelegramID updates the telegram ID for an admin added by username
+func (s *StorageService) UpdateAdminTelegramID(username string, realTelegramID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, admin := range s.data.AdminUsers {
+		if admin.Username == username {
+			s.data.AdminUsers[i].TelegramID = realTelegramID
+			return s.save()
+		}
+	}
+	return nil
+}
+
+// AddAdmin adds a user to the runtime admin list
+func (s *StorageService) AddAdmin(telegramID int64, username string, addedBy int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check if already exists
+	for _, admin := range s.data.AdminUsers {
+		if admin.TelegramID == telegramID {
+			return nil // Already exists
+		}
+	}
+
+	s.data.AdminUsers = append(s.data.AdminUsers, models.AdminUser{
+		TelegramID: telegramID,
+		Username:   username,
+		AddedBy:    addedBy,
+		AddedAt:    time.Now().Unix(),
+	})
+
+	return s.save()
+}
+
+// RemoveAdmin removes a user from the runtime admin list
+func (s *StorageService) RemoveAdmin(telegramID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, admin := range s.data.AdminUsers {
+		if admin.TelegramID == telegramID {
+			s.data.AdminUsers = append(s.data.AdminUsers[:i], s.data.AdminUsers[i+1:]...)
+			return s.save()
+		}
+	}
+	return nil
+}
+
+// GetAdminUsers returns all runtime admins
+func (s *StorageService) GetAdminUsers() []models.AdminUser {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	admins := make([]models.AdminUser, len(s.data.AdminUsers))
+	copy(admins, s.data.AdminUsers)
+	return admins
+}
+
+// GetAllVpnAccounts returns all stored VPN accounts
+func (s *StorageService) GetAllVpnAccounts() []models.VpnAccount {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	accounts := make([]models.VpnAccount, len(s.data.VpnAccounts))
+	copy(accounts, s.data.VpnAccounts)
+	return accounts
+}
+
 // GetUserAccountCount returns the number of VPN accounts created by a user
 func (s *StorageService) GetUserAccountCount(telegramID int64) int {
 	s.mu.RLock()
@@ -179,11 +449,22 @@ func (s *StorageService) GetUserAccountCount(telegramID int64) int {
 	return count
 }
 
-// AddVpnAccount adds a new VPN account
+// AddVpnAccount adds a new VPN account, or updates the existing record when the
+// same user already added this username (e.g. when retrying after a panel error)
 func (s *StorageService) AddVpnAccount(username, password string, addedBy int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	for i, account := range s.data.VpnAccounts {
+		if account.Username == username && account.AddedBy == addedBy {
+			if account.Password == password {
+				return nil
+			}
+			s.data.VpnAccounts[i].Password = password
+			return s.save()
+		}
+	}
+
 	s.data.VpnAccounts = append(s.data.VpnAccounts, models.VpnAccount{
 		ID:        s.data.NextID,
 		Username:  username,
@@ -210,6 +491,88 @@ func (s *StorageService) RemoveVpnAccount(id int, telegramID int64) error {
 	return nil
 }
 
+// RemoveVpnAccountsByID removes the VPN accounts with the given IDs regardless of owner
+// and returns how many were removed
+func (s *StorageService) RemoveVpnAccountsByID(ids []int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remove := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = true
+	}
+
+	kept := s.data.VpnAccounts[:0]
+	for _, account := range s.data.VpnAccounts {
+		if !remove[account.ID] {
+			kept = append(kept, account)
+		}
+	}
+
+	removed := len(s.data.VpnAccounts) - len(kept)
+	s.data.VpnAccounts = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.save()
+}
+
+// RenameVpnAccount updates the username of stored VPN accounts after a rename
+func (s *StorageService) RenameVpnAccount(oldUsername, newUsername string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for i, account := range s.data.VpnAccounts {
+		if account.Username == oldUsername {
+			s.data.VpnAccounts[i].Username = newUsername
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return s.save()
+}
+
+// TransferVpnAccount hands a VPN account over to another trusted user, who must stay
+// within limit accounts afterwards.
+func (s *StorageService) TransferVpnAccount(id int, toTelegramID int64, limit int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := slices.IndexFunc(s.data.VpnAccounts, func(account models.VpnAccount) bool {
+		return account.ID == id
+	})
+	if index < 0 {
+		return ErrAccountNotFound
+	}
+	account := s.data.VpnAccounts[index]
+
+	if !slices.ContainsFunc(s.data.TrustedUsers, func(user models.TrustedUser) bool {
+		return user.TelegramID == toTelegramID
+	}) {
+		return ErrTransferTarget
+	}
+	if account.AddedBy == toTelegramID {
+		return ErrTransferSameOwner
+	}
+
+	owned := 0
+	for _, other := range s.data.VpnAccounts {
+		if other.AddedBy == toTelegramID {
+			owned++
+		}
+	}
+	if owned >= limit {
+		return ErrAccountLimitReached
+	}
+
+	s.data.VpnAccounts[index].AddedBy = toTelegramID
+	return s.save()
+}
+
 // GetUserAccounts returns all VPN accounts created by a specific user
 func (s *StorageService) GetUserAccounts(telegramID int64) []models.VpnAccount {
 	s.mu.RLock()
@@ -224,6 +587,276 @@ func (s *StorageService) GetUserAccounts(telegramID int64) []models.VpnAccount {
 	return accounts
 }
 
+// GetNote returns the admin note for a member, or an empty string if there is none
+func (s *StorageService) GetNote(username string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data.Notes[username]
+}
+
+// GetNotes returns the admin notes of all members keyed by base username
+func (s *StorageService) GetNotes() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return maps.Clone(s.data.Notes)
+}
+
+// SetNote stores the admin note for a member; an empty note removes it
+func (s *StorageService) SetNote(username, note string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if note == "" {
+		delete(s.data.Notes, username)
+		return s.save()
+	}
+
+	if s.data.Notes == nil {
+		s.data.Notes = make(map[string]string)
+	}
+	s.data.Notes[username] = note
+	return s.save()
+}
+
+// GetTags returns the tags of a member in alphabetical order
+func (s *StorageService) GetTags(username string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]string(nil), s.data.Tags[username]...)
+}
+
+// AddTag adds a tag to a member unless it is already set
+func (s *StorageService) AddTag(username, tag string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if slices.Contains(s.data.Tags[username], tag) {
+		return nil
+	}
+
+	if s.data.Tags == nil {
+		s.data.Tags = make(map[string][]string)
+	}
+	tags := append(s.data.Tags[username], tag)
+	slices.Sort(tags)
+	s.data.Tags[username] = tags
+	return s.save()
+}
+
+// RemoveTag removes a tag from a member
+func (s *StorageService) RemoveTag(username, tag string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tags := s.data.Tags[username]
+	i := slices.Index(tags, tag)
+	if i < 0 {
+		return nil
+	}
+
+	tags = slices.Delete(tags, i, i+1)
+	if len(tags) == 0 {
+		delete(s.data.Tags, username)
+	} else {
+		s.data.Tags[username] = tags
+	}
+	return s.save()
+}
+
+// GetMemberTags returns the tags of all members keyed by base username
+func (s *StorageService) GetMemberTags() map[string][]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tags := make(map[string][]string, len(s.data.Tags))
+	for username, memberTags := range s.data.Tags {
+		tags[username] = slices.Clone(memberTags)
+	}
+	return tags
+}
+
+// GetAllTags returns every tag in use, in alphabetical order
+func (s *StorageService) GetAllTags() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var all []string
+	for _, tags := range s.data.Tags {
+		for _, tag := range tags {
+			if !slices.Contains(all, tag) {
+				all = append(all, tag)
+			}
+		}
+	}
+	slices.Sort(all)
+	return all
+}
+
+// TaggedUsernames returns the set of members that carry the tag
+func (s *StorageService) TaggedUsernames(tag string) map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	usernames := make(map[string]bool)
+	for username, tags := range s.data.Tags {
+		if slices.Contains(tags, tag) {
+			usernames[username] = true
+		}
+	}
+	return usernames
+}
+
+// RemindedExpiry returns the expiry time the account's owner was last reminded of, or 0
+func (s *StorageService) RemindedExpiry(username string) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data.Reminders[username]
+}
+
+// SetRemindedExpiry records that the account's owner was reminded of the expiry time, so
+// the reminder isn't repeated until the account is renewed
+func (s *StorageService) SetRemindedExpiry(username string, expiryTime int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.Reminders == nil {
+		s.data.Reminders = make(map[string]int64)
+	}
+	s.data.Reminders[username] = expiryTime
+	return s.save()
+}
+
+// RemindersEnabled reports whether the user still wants expiry reminders
+func (s *StorageService) RemindersEnabled(telegramID int64) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return !slices.Contains(s.data.RemindersOff, telegramID)
+}
+
+// SetRemindersEnabled turns expiry reminders on or off for the user
+func (s *StorageService) SetRemindersEnabled(telegramID int64, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.Index(s.data.RemindersOff, telegramID)
+	switch {
+	case enabled && i >= 0:
+		s.data.RemindersOff = slices.Delete(s.data.RemindersOff, i, i+1)
+	case !enabled && i < 0:
+		s.data.RemindersOff = append(s.data.RemindersOff, telegramID)
+	default:
+		return nil
+	}
+	return s.save()
+}
+
+// GetPassword returns the generated password of a member, stored with the member when an
+// admin created it or with the VPN account when a trusted user did, or an empty string
+func (s *StorageService) GetPassword(username string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if password, ok := s.data.Passwords[username]; ok {
+		return password
+	}
+	for _, account := range s.data.VpnAccounts {
+		if account.Username == username && account.Password != "" {
+			return account.Password
+		}
+	}
+	return ""
+}
+
+// GetPasswords returns the generated passwords of admin-created members keyed by base
+// username; passwords of trusted users' accounts are kept with the accounts
+func (s *StorageService) GetPasswords() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return maps.Clone(s.data.Passwords)
+}
+
+// SetPassword stores the generated password of a member created by an admin
+func (s *StorageService) SetPassword(username, password string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.Passwords == nil {
+		s.data.Passwords = make(map[string]string)
+	}
+	s.data.Passwords[username] = password
+	return s.save()
+}
+
+// RenameMemberData moves a member's note, tags, password and reminder state to the new username after a rename
+func (s *StorageService) RenameMemberData(oldUsername, newUsername string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	if note, ok := s.data.Notes[oldUsername]; ok {
+		delete(s.data.Notes, oldUsername)
+		s.data.Notes[newUsername] = note
+		changed = true
+	}
+	if tags, ok := s.data.Tags[oldUsername]; ok {
+		delete(s.data.Tags, oldUsername)
+		s.data.Tags[newUsername] = tags
+		changed = true
+	}
+	if password, ok := s.data.Passwords[oldUsername]; ok {
+		delete(s.data.Passwords, oldUsername)
+		s.data.Passwords[newUsername] = password
+		changed = true
+	}
+	if expiry, ok := s.data.Reminders[oldUsername]; ok {
+		delete(s.data.Reminders, oldUsername)
+		s.data.Reminders[newUsername] = expiry
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return s.save()
+}
+
+// RemoveMemberData deletes the notes, tags, passwords and reminder state of members that were deleted
+func (s *StorageService) RemoveMemberData(usernames ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for _, username := range usernames {
+		if _, ok := s.data.Notes[username]; ok {
+			delete(s.data.Notes, username)
+			changed = true
+		}
+		if _, ok := s.data.Tags[username]; ok {
+			delete(s.data.Tags, username)
+			changed = true
+		}
+		if _, ok := s.data.Passwords[username]; ok {
+			delete(s.data.Passwords, username)
+			changed = true
+		}
+		if _, ok := s.data.Reminders[username]; ok {
+			delete(s.data.Reminders, username)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return s.save()
+}
+
 // save is an internal method that assumes the mutex is already locked
 func (s *StorageService) save() error {
 	data, err := json.MarshalIndent(s.data, "", "  ")
@@ -231,6 +864,21 @@ func (s *StorageService) save() error {
 		return err
 	}
 
+	return s.writeFile(data)
+}
+
+// writeFile encrypts data if a key is configured and atomically replaces the storage
+// file, creating its directory if needed
+func (s *StorageService) writeFile(data []byte) error {
+	data, err := s.cipher.encrypt(data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.filename), 0755); err != nil {
+		return err
+	}
+
 	tmpFile := s.filename + ".tmp"
 	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
 		return err
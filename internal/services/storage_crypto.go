@@ -0,0 +1,171 @@
+package services
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptedStorageHeader marks a storage file encrypted with AES-256-GCM under a key
+// derived with scrypt. It is followed by the salt, the nonce and the sealed JSON.
+var encryptedStorageHeader = []byte("xtga-encrypted:v2\n")
+
+// scrypt cost of the storage key: about 32 MB and a fraction of a second per derivation
+const (
+	storageSaltSize = 16
+	storageScryptN  = 1 << 15
+	storageScryptR  = 8
+	storageScryptP  = 1
+)
+
+// ErrStorageKey is returned when the storage file is encrypted and none of the configured
+// keys can open it
+var ErrStorageKey = errors.New("storage file cannot be decrypted with the configured keys")
+
+// storageCipher encrypts the storage file with the current key and can still open files
+// written with previous keys. Without a current key files are written as plain JSON.
+type storageCipher struct {
+	current  string
+	previous []string
+
+	// The salt of the file and the key derived from it for the current passphrase are
+	// kept, so saves don't pay for scrypt again
+	mu   sync.Mutex
+	salt []byte
+	aead cipher.AEAD
+}
+
+// newStorageCipher keeps the configured passphrases. Empty keys are ignored, so with no
+// keys at all the storage file stays plain JSON.
+func newStorageCipher(key string, previousKeys []string) (*storageCipher, error) {
+	c := &storageCipher{current: key}
+
+	for _, previous := range previousKeys {
+		if previous == "" {
+			continue
+		}
+		c.previous = append(c.previous, previous)
+	}
+
+	return c, nil
+}
+
+// newStorageAEAD derives an AES-256 key from a passphrase and salt with scrypt and wraps
+// it in GCM
+func newStorageAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, storageScryptN, storageScryptR, storageScryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive storage key: %w", err)
+	}
+	return newGCM(key)
+}
+
+// newGCM wraps an AES-256 key in GCM
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt seals the storage JSON with the current key, or returns it unchanged when
+// encryption is off
+func (c *storageCipher) encrypt(plain []byte) ([]byte, error) {
+	if c.current == "" {
+		return plain, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.aead == nil {
+		salt := make([]byte, storageSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		aead, err := newStorageAEAD(c.current, salt)
+		if err != nil {
+			return nil, err
+		}
+		c.salt, c.aead = salt, aead
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	header := storageHeader(c.salt)
+	out := make([]byte, 0, len(header)+len(nonce)+len(plain)+c.aead.Overhead())
+	out = append(out, header...)
+	out = append(out, nonce...)
+	return c.aead.Seal(out, nonce, plain, header), nil
+}
+
+// decrypt opens a storage file written as plain JSON or with any configured key. stale
+// reports that the file isn't in the form encrypt would write now (plain JSON while a key
+// is set, or sealed with a previous key) and should be saved again.
+func (c *storageCipher) decrypt(raw []byte) (plain []byte, stale bool, err error) {
+	if !bytes.HasPrefix(raw, encryptedStorageHeader) {
+		return raw, c.current != "", nil
+	}
+
+	rest := raw[len(encryptedStorageHeader):]
+	if len(rest) < storageSaltSize {
+		return nil, false, errors.New("encrypted storage file is truncated")
+	}
+	salt, sealed := rest[:storageSaltSize], rest[storageSaltSize:]
+	header := storageHeader(salt)
+
+	if c.current != "" {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		aead := c.aead
+		if aead == nil || !bytes.Equal(c.salt, salt) {
+			if aead, err = newStorageAEAD(c.current, salt); err != nil {
+				return nil, false, err
+			}
+		}
+		if plain, err := openSealed(aead, sealed, header); err == nil {
+			// Keep writing with this salt, so the key isn't derived again
+			c.salt, c.aead = bytes.Clone(salt), aead
+			return plain, false, nil
+		}
+	}
+	for _, previous := range c.previous {
+		aead, err := newStorageAEAD(previous, salt)
+		if err != nil {
+			return nil, false, err
+		}
+		if plain, err := openSealed(aead, sealed, header); err == nil {
+			return plain, true, nil
+		}
+	}
+
+	return nil, false, ErrStorageKey
+}
+
+// storageHeader returns the header and salt that start a sealed file, which are also
+// authenticated with it
+func storageHeader(salt []byte) []byte {
+	header := make([]byte, 0, len(encryptedStorageHeader)+len(salt))
+	header = append(header, encryptedStorageHeader...)
+	return append(header, salt...)
+}
+
+// openSealed splits the nonce off a sealed payload and decrypts it
+func openSealed(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("encrypted storage file is truncated")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestStorageCipherRoundTrip(t *testing.T) {
+	c, err := newStorageCipher("correct horse battery staple", nil)
+	if err != nil {
+		t.Fatalf("newStorageCipher returned error: %v", err)
+	}
+
+	plain := []byte(`{"schema_version":1}`)
+	sealed, err := c.encrypt(plain)
+	if err != nil {
+		t.Fatalf("encrypt returned error: %v", err)
+	}
+	if !bytes.HasPrefix(sealed, encryptedStorageHeader) {
+		t.Fatalf("sealed file doesn't start with the header")
+	}
+	if bytes.Contains(sealed, plain) {
+		t.Fatalf("sealed file contains the plain JSON")
+	}
+
+	// A fresh cipher has to derive the key from the salt in the file
+	reopened, _ := newStorageCipher("correct horse battery staple", nil)
+	got, stale, err := reopened.decrypt(sealed)
+	if err != nil {
+		t.Fatalf("decrypt returned error: %v", err)
+	}
+	if stale {
+		t.Errorf("decrypt reported a file sealed with the current key as stale")
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("decrypt = %s, want %s", got, plain)
+	}
+}
+
+func TestStorageCipherSaltsFiles(t *testing.T) {
+	first, _ := newStorageCipher("correct horse battery staple", nil)
+	second, _ := newStorageCipher("correct horse battery staple", nil)
+
+	a, err := first.encrypt([]byte("{}"))
+	if err != nil {
+		t.Fatalf("encrypt returned error: %v", err)
+	}
+	b, err := second.encrypt([]byte("{}"))
+	if err != nil {
+		t.Fatalf("encrypt returned error: %v", err)
+	}
+
+	saltOf := func(sealed []byte) []byte {
+		return sealed[len(encryptedStorageHeader) : len(encryptedStorageHeader)+storageSaltSize]
+	}
+	if bytes.Equal(saltOf(a), saltOf(b)) {
+		t.Errorf("two storage files got the same salt")
+	}
+}
+
+func TestStorageCipherWrongPassphrase(t *testing.T) {
+	c, _ := newStorageCipher("correct horse battery staple", nil)
+	sealed, err := c.encrypt([]byte("{}"))
+	if err != nil {
+		t.Fatalf("encrypt returned error: %v", err)
+	}
+
+	wrong, _ := newStorageCipher("incorrect horse battery staple", []string{"another wrong passphrase"})
+	if _, _, err := wrong.decrypt(sealed); !errors.Is(err, ErrStorageKey) {
+		t.Errorf("decrypt with the wrong passphrase = %v, want ErrStorageKey", err)
+	}
+
+	plainOnly, _ := newStorageCipher("", nil)
+	if _, _, err := plainOnly.decrypt(sealed); !errors.Is(err, ErrStorageKey) {
+		t.Errorf("decrypt without a key = %v, want ErrStorageKey", err)
+	}
+}
+
+func TestStorageCipherTamperedSalt(t *testing.T) {
+	c, _ := newStorageCipher("correct horse battery staple", nil)
+	sealed, err := c.encrypt([]byte("{}"))
+	if err != nil {
+		t.Fatalf("encrypt returned error: %v", err)
+	}
+
+	sealed[len(encryptedStorageHeader)] ^= 0xff
+	if _, _, err := c.decrypt(sealed); !errors.Is(err, ErrStorageKey) {
+		t.Errorf("decrypt of a file with a changed salt = %v, want ErrStorageKey", err)
+	}
+}
+
+func TestStorageCipherRotation(t *testing.T) {
+	old, _ := newStorageCipher("the old storage passphrase", nil)
+	plain := []byte(`{"notes":{"alice":"hi"}}`)
+	sealed, err := old.encrypt(plain)
+	if err != nil {
+		t.Fatalf("encrypt returned error: %v", err)
+	}
+
+	rotated, _ := newStorageCipher("the new storage passphrase", []string{"", "the old storage passphrase"})
+	got, stale, err := rotated.decrypt(sealed)
+	if err != nil {
+		t.Fatalf("decrypt with the previous key returned error: %v", err)
+	}
+	if !stale {
+		t.Errorf("decrypt didn't report a file sealed with a previous key as stale")
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("decrypt = %s, want %s", got, plain)
+	}
+
+	// Once rewritten, the file opens with the new key alone
+	resealed, err := rotated.encrypt(got)
+	if err != nil {
+		t.Fatalf("encrypt returned error: %v", err)
+	}
+	current, _ := newStorageCipher("the new storage passphrase", nil)
+	if _, stale, err := current.decrypt(resealed); err != nil || stale {
+		t.Errorf("decrypt of the rewritten file = stale %v, err %v; want fresh", stale, err)
+	}
+	if _, _, err := old.decrypt(resealed); !errors.Is(err, ErrStorageKey) {
+		t.Errorf("the old key still opens the rewritten file: %v", err)
+	}
+}
+
+func TestStorageCipherPlain(t *testing.T) {
+	c, _ := newStorageCipher("correct horse battery staple", nil)
+
+	plain := []byte(`{"next_id":1}`)
+	got, stale, err := c.decrypt(plain)
+	if err != nil || !stale || !bytes.Equal(got, plain) {
+		t.Errorf("decrypt of plain JSON = %s, stale %v, err %v; want it unchanged and stale", got, stale, err)
+	}
+
+}
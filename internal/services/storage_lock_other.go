@@ -0,0 +1,17 @@
+//go:build !unix
+
+package services
+
+import (
+	"fmt"
+	"os"
+)
+
+// lockStorageFile opens the lock file without locking it, as flock is only available on Unix
+func lockStorageFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage lock file: %w", err)
+	}
+	return f, nil
+}
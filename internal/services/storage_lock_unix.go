@@ -0,0 +1,29 @@
+//go:build unix
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockStorageFile takes an exclusive advisory lock so two bot instances can't share one storage file.
+// The lock is released when the returned file is closed or the process exits.
+func lockStorageFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage lock file: %w", err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("storage file is in use by another bot instance (lock %s is held)", path)
+		}
+		return nil, fmt.Errorf("failed to lock storage file: %w", err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestStorage(t *testing.T, filename, key string) *StorageService {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	storage, err := NewStorageService(filename, key, nil, logger)
+	if err != nil {
+		t.Fatalf("NewStorageService returned error: %v", err)
+	}
+	return storage
+}
+
+func TestAddVpnAccountRetry(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "data.json")
+	storage := newTestStorage(t, filename, "")
+
+	// A retry after a panel error adds the same account again, with a new password
+	if err := storage.AddVpnAccount("alice", "first", 100); err != nil {
+		t.Fatalf("AddVpnAccount returned error: %v", err)
+	}
+	if err := storage.AddVpnAccount("alice", "first", 100); err != nil {
+		t.Fatalf("AddVpnAccount retry returned error: %v", err)
+	}
+	if err := storage.AddVpnAccount("alice", "second", 100); err != nil {
+		t.Fatalf("AddVpnAccount retry with a new password returned error: %v", err)
+	}
+
+	accounts := storage.GetUserAccounts(100)
+	if len(accounts) != 1 {
+		t.Fatalf("retries left %d accounts, want 1: %+v", len(accounts), accounts)
+	}
+	if accounts[0].Password != "second" {
+		t.Errorf("password = %q, want the one from the last attempt", accounts[0].Password)
+	}
+	if n := storage.GetUserAccountCount(100); n != 1 {
+		t.Errorf("GetUserAccountCount = %d, want 1 so retries don't use up the limit", n)
+	}
+
+	// The same username added by someone else is a separate account
+	if err := storage.AddVpnAccount("alice", "other", 200); err != nil {
+		t.Fatalf("AddVpnAccount for another owner returned error: %v", err)
+	}
+	if n := len(storage.GetAllVpnAccounts()); n != 2 {
+		t.Errorf("got %d accounts, want 2", n)
+	}
+
+	// The result survives a restart
+	storage.Close()
+	reloaded := newTestStorage(t, filename, "")
+	defer reloaded.Close()
+
+	accounts = reloaded.GetUserAccounts(100)
+	if len(accounts) != 1 || accounts[0].Password != "second" {
+		t.Errorf("after reload got %+v, want one account with the last password", accounts)
+	}
+}
+
+func TestMemberPasswords(t *testing.T) {
+	storage := newTestStorage(t, filepath.Join(t.TempDir(), "data.json"), "")
+	defer storage.Close()
+
+	if err := storage.AddVpnAccount("bob", "trusted-secret", 100); err != nil {
+		t.Fatalf("AddVpnAccount returned error: %v", err)
+	}
+	if err := storage.SetPassword("alice", "admin-secret"); err != nil {
+		t.Fatalf("SetPassword returned error: %v", err)
+	}
+
+	// Both flows' passwords can be looked up by username
+	if got := storage.GetPassword("alice"); got != "admin-secret" {
+		t.Errorf("GetPassword(alice) = %q, want the one an admin stored", got)
+	}
+	if got := storage.GetPassword("bob"); got != "trusted-secret" {
+		t.Errorf("GetPassword(bob) = %q, want the one stored with the account", got)
+	}
+
+	if err := storage.RenameMemberData("alice", "carol"); err != nil {
+		t.Fatalf("RenameMemberData returned error: %v", err)
+	}
+	if got := storage.GetPassword("carol"); got != "admin-secret" {
+		t.Errorf("GetPassword after rename = %q, want the password to move", got)
+	}
+
+	if err := storage.RemoveMemberData("carol"); err != nil {
+		t.Fatalf("RemoveMemberData returned error: %v", err)
+	}
+	if got := storage.GetPassword("carol"); got != "" {
+		t.Errorf("GetPassword after removal = %q, want none", got)
+	}
+}
@@ -2,17 +2,20 @@ package services
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
 	"github.com/sirupsen/logrus"
 
 	"xui-tg-admin/internal/models"
+	"xui-tg-admin/internal/permissions"
 )
 
 // UserStateService manages user conversation states
 type UserStateService struct {
 	cache  *cache.Cache
+	mu     sync.Mutex // serializes read-modify-write updates so concurrent ones don't clobber each other
 	logger *logrus.Logger
 }
 
@@ -24,13 +27,14 @@ func NewUserStateService(logger *logrus.Logger) *UserStateService {
 	}
 }
 
-// GetState gets a user's state
+// GetState gets a copy of a user's state; changes to it take effect only through SetState
 func (s *UserStateService) GetState(userID int64) (*models.UserState, error) {
 	key := fmt.Sprintf("user_state_%d", userID)
 
 	if data, found := s.cache.Get(key); found {
 		if state, ok := data.(*models.UserState); ok {
-			return state, nil
+			stateCopy := *state
+			return &stateCopy, nil
 		}
 		return nil, fmt.Errorf("invalid state type for user %d", userID)
 	}
@@ -46,62 +50,157 @@ func (s *UserStateService) GetState(userID int64) (*models.UserState, error) {
 
 // SetState sets a user's state
 func (s *UserStateService) SetState(userID int64, state models.UserState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.setState(userID, state)
+}
+
+// setState stores a user's state; callers hold s.mu
+func (s *UserStateService) setState(userID int64, state models.UserState) error {
 	key := fmt.Sprintf("user_state_%d", userID)
 	s.cache.Set(key, &state, cache.DefaultExpiration)
 	s.logger.Debugf("Set state for user %d: %+v", userID, state)
 	return nil
 }
 
-// ClearState clears a user's state
+// ClearState clears a user's state. An admin's /as level is kept, so only /as admin or the
+// state expiring ends it.
 func (s *UserStateService) ClearState(userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.GetState(userID)
+	if err != nil {
+		return err
+	}
+	if state.ActingAs != nil {
+		return s.setState(userID, models.UserState{State: models.Default, ActingAs: state.ActingAs})
+	}
+
 	key := fmt.Sprintf("user_state_%d", userID)
 	s.cache.Delete(key)
 	s.logger.Debugf("Cleared state for user %d", userID)
 	return nil
 }
 
-// WithConversationState updates a user's conversation state
-func (s *UserStateService) WithConversationState(userID int64, conversationState models.ConversationState) error {
+// update applies fn to the user's current state and stores the result, with no other
+// update able to run in between
+func (s *UserStateService) update(userID int64, fn func(state *models.UserState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	state, err := s.GetState(userID)
 	if err != nil {
 		return err
 	}
 
-	state.State = conversationState
-	return s.SetState(userID, *state)
+	fn(state)
+	return s.setState(userID, *state)
+}
+
+// WithConversationState updates a user's conversation state
+func (s *UserStateService) WithConversationState(userID int64, conversationState models.ConversationState) error {
+	return s.update(userID, func(state *models.UserState) {
+		state.State = conversationState
+	})
+}
+
+// WithActingAs sets the access level an admin sees the bot as, or ends that with nil
+func (s *UserStateService) WithActingAs(userID int64, accessType *permissions.AccessType) error {
+	return s.update(userID, func(state *models.UserState) {
+		state.ActingAs = accessType
+	})
 }
 
 // WithPayload updates a user's payload
 func (s *UserStateService) WithPayload(userID int64, payload string) error {
-	state, err := s.GetState(userID)
-	if err != nil {
-		return err
-	}
-
-	state.Payload = &payload
-	return s.SetState(userID, *state)
+	return s.update(userID, func(state *models.UserState) {
+		state.Payload = &payload
+	})
 }
 
 // WithSortType updates a user's sort type
 func (s *UserStateService) WithSortType(userID int64, sortType models.SortType) error {
-	state, err := s.GetState(userID)
-	if err != nil {
-		return err
-	}
-
-	state.SortType = &sortType
-	return s.SetState(userID, *state)
+	return s.update(userID, func(state *models.UserState) {
+		state.SortType = &sortType
+	})
 }
 
 // WithActionType updates a user's action type
 func (s *UserStateService) WithActionType(userID int64, actionType string) error {
+	return s.update(userID, func(state *models.UserState) {
+		state.ActionType = &actionType
+	})
+}
+
+// WithConfirmationRequested records that a confirmation was just shown to the user
+func (s *UserStateService) WithConfirmationRequested(userID int64) error {
+	return s.update(userID, func(state *models.UserState) {
+		now := time.Now()
+		state.ConfirmRequestedAt = &now
+	})
+}
+
+// ToggleSelected adds a member to the user's bulk selection or removes it if already selected
+func (s *UserStateService) ToggleSelected(userID int64, username string) error {
+	return s.update(userID, func(state *models.UserState) {
+		selected := make(map[string]bool, len(state.Selected)+1)
+		for name := range state.Selected {
+			selected[name] = true
+		}
+		if selected[username] {
+			delete(selected, username)
+		} else {
+			selected[username] = true
+		}
+
+		state.Selected = selected
+	})
+}
+
+// WithPage sets the page of the bulk selection keyboard being shown
+func (s *UserStateService) WithPage(userID int64, page int) error {
+	return s.update(userID, func(state *models.UserState) {
+		state.Page = page
+	})
+}
+
+// WithFailedCreation keeps a failed member creation so it can be retried
+func (s *UserStateService) WithFailedCreation(userID int64, creation models.FailedCreation) error {
+	return s.update(userID, func(state *models.UserState) {
+		state.FailedCreation = &creation
+	})
+}
+
+// TakeFailedCreation returns the user's failed member creation and forgets it, so a
+// double-tapped Retry button repeats the creation only once
+func (s *UserStateService) TakeFailedCreation(userID int64) (*models.FailedCreation, error) {
+	var failed *models.FailedCreation
+	err := s.update(userID, func(state *models.UserState) {
+		failed = state.FailedCreation
+		state.FailedCreation = nil
+	})
+	return failed, err
+}
+
+// ToggleActiveOnly flips whether the user's reports show only active subscriptions and returns the new value
+func (s *UserStateService) ToggleActiveOnly(userID int64) (bool, error) {
+	var activeOnly bool
+	err := s.update(userID, func(state *models.UserState) {
+		state.ActiveOnly = !state.ActiveOnly
+		activeOnly = state.ActiveOnly
+	})
+	return activeOnly, err
+}
+
+// GetActiveOnly reports whether the user's reports show only active subscriptions
+func (s *UserStateService) GetActiveOnly(userID int64) bool {
 	state, err := s.GetState(userID)
 	if err != nil {
-		return err
+		return false
 	}
-
-	state.ActionType = &actionType
-	return s.SetState(userID, *state)
+	return state.ActiveOnly
 }
 
 // GetSortType gets the user's sort type or returns default
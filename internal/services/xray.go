@@ -2,57 +2,246 @@ package services
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"xui-tg-admin/internal/config"
 	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/metrics"
 	"xui-tg-admin/internal/models"
 	"xui-tg-admin/pkg/xrayclient"
 )
 
+// UnhealthyNotifier is called when an operation keeps failing against the panel
+type UnhealthyNotifier func(operation string, failures int, window time.Duration, lastErr error)
+
+// NoEnabledInboundsNotifier is called when the panel turns out to have no enabled inbound
+type NoEnabledInboundsNotifier func(total int)
+
+// MalformedInboundsNotifier is called when inbounds turn up whose settings can't be parsed
+type MalformedInboundsNotifier func(inbounds []models.Inbound)
+
 // XrayService manages X-ray API client for a single server
 type XrayService struct {
-	client *xrayclient.Client
-	config *config.Config
-	logger *logrus.Logger
+	client   *xrayclient.Client
+	config   *config.Config
+	logger   *logrus.Logger
+	failures *failureTracker
+	notifier UnhealthyNotifier
+
+	inboundsMu        sync.Mutex
+	inboundsNotifier  NoEnabledInboundsNotifier
+	noEnabledInbounds bool // admins were alerted and no inbound has been enabled since
+
+	malformedNotifier MalformedInboundsNotifier
+	malformedInbounds map[int]bool // inbounds admins were alerted about, until they parse again
 }
 
 // NewXrayService creates a new X-ray service
 func NewXrayService(cfg *config.Config, logger *logrus.Logger) *XrayService {
 	client := xrayclient.NewClient(cfg.Server, logger)
 
-	return &XrayService{
+	service := &XrayService{
 		client: client,
 		config: cfg,
 		logger: logger,
 	}
+
+	// Track repeated panel failures unless alerts are disabled
+	if cfg.PanelAlertThreshold > 0 {
+		service.failures = newFailureTracker(cfg.PanelAlertThreshold, time.Duration(cfg.PanelAlertWindow)*time.Minute)
+	}
+
+	return service
+}
+
+// SetUnhealthyNotifier sets the callback used to alert admins about repeated panel failures
+func (s *XrayService) SetUnhealthyNotifier(notifier UnhealthyNotifier) {
+	s.notifier = notifier
+}
+
+// SetNoEnabledInboundsNotifier sets the callback used to alert admins when no inbound is enabled
+func (s *XrayService) SetNoEnabledInboundsNotifier(notifier NoEnabledInboundsNotifier) {
+	s.inboundsNotifier = notifier
+}
+
+// SetMalformedInboundsNotifier sets the callback used to alert admins about inbounds with
+// unparseable settings
+func (s *XrayService) SetMalformedInboundsNotifier(notifier MalformedInboundsNotifier) {
+	s.malformedNotifier = notifier
+}
+
+// checkInboundSettings alerts admins once per inbound whose settings can't be parsed. An
+// inbound that parses again is forgotten, so breaking it later alerts again.
+func (s *XrayService) checkInboundSettings(inbounds []models.Inbound) {
+	malformed := helpers.MalformedInbounds(inbounds)
+
+	s.inboundsMu.Lock()
+	previous := s.malformedInbounds
+	s.malformedInbounds = make(map[int]bool, len(malformed))
+	var fresh []models.Inbound
+	for _, inbound := range malformed {
+		s.malformedInbounds[inbound.ID] = true
+		if !previous[inbound.ID] {
+			fresh = append(fresh, inbound)
+		}
+	}
+	s.inboundsMu.Unlock()
+
+	for _, inbound := range fresh {
+		_, err := helpers.ParseInboundClients(inbound)
+		s.logger.WithField("inbound_id", inbound.ID).WithError(err).Warn("Inbound settings can't be parsed")
+	}
+	if len(fresh) > 0 && s.malformedNotifier != nil {
+		s.malformedNotifier(fresh)
+	}
+}
+
+// checkEnabledInbounds alerts admins once when the fetched inbounds include none new clients
+// can be created on, enabled and allowed by INBOUNDS_INCLUDE/INBOUNDS_EXCLUDE, and re-arms
+// the alert as soon as there is one again
+func (s *XrayService) checkEnabledInbounds(inbounds []models.Inbound) {
+	enabled := false
+	for _, inbound := range inbounds {
+		if inbound.Enable && s.config.Server.TargetsInbound(inbound.ID, inbound.Remark) {
+			enabled = true
+			break
+		}
+	}
+
+	s.inboundsMu.Lock()
+	alert := !enabled && !s.noEnabledInbounds
+	s.noEnabledInbounds = !enabled
+	s.inboundsMu.Unlock()
+
+	if !alert {
+		return
+	}
+
+	s.logger.WithField("inbounds", len(inbounds)).Warn("Panel has no enabled inbounds allowed by INBOUNDS_INCLUDE/INBOUNDS_EXCLUDE")
+	if s.inboundsNotifier != nil {
+		s.inboundsNotifier(len(inbounds))
+	}
+}
+
+// track records the outcome and latency of a panel operation started at start, and alerts
+// once failures pile up
+func (s *XrayService) track(start time.Time, operation string, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	metrics.PanelRequestsTotal.Inc(operation, result)
+	metrics.PanelRequestDuration.Observe(start, operation)
+
+	if s.failures == nil {
+		return
+	}
+
+	if err == nil {
+		s.failures.RecordSuccess(operation)
+		return
+	}
+
+	count, alert := s.failures.RecordFailure(operation)
+	if !alert {
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"operation": operation,
+		"failures":  count,
+	}).Warn("Panel seems unhealthy")
+
+	if s.notifier != nil {
+		s.notifier(operation, count, s.failures.window, err)
+	}
 }
 
 // GetInbounds gets the inbounds from the server
 func (s *XrayService) GetInbounds(ctx context.Context) ([]models.Inbound, error) {
-	return s.client.GetInbounds(ctx)
+	start := time.Now()
+	inbounds, err := s.client.GetInbounds(ctx)
+	s.track(start, "get_inbounds", err)
+	if err == nil {
+		s.checkEnabledInbounds(inbounds)
+		s.checkInboundSettings(inbounds)
+	}
+	return inbounds, err
+}
+
+// Ping checks connectivity and credentials against the server
+func (s *XrayService) Ping(ctx context.Context) (*xrayclient.PingResult, error) {
+	return s.client.Ping(ctx)
 }
 
 // AddClient adds a client to an inbound on the server
 func (s *XrayService) AddClient(ctx context.Context, inboundID int, client models.Client) error {
-	return s.client.AddClientToInbound(ctx, inboundID, client)
+	start := time.Now()
+	err := s.client.AddClientToInbound(ctx, inboundID, client)
+	s.track(start, "add_client", err)
+	return err
+}
+
+// UpdateClient updates an existing client in an inbound on the server
+func (s *XrayService) UpdateClient(ctx context.Context, inboundID int, clientUUID string, client models.Client) error {
+	start := time.Now()
+	err := s.client.UpdateClient(ctx, inboundID, clientUUID, client)
+	s.track(start, "update_client", err)
+	return err
 }
 
 // RemoveClients removes clients from the server
 func (s *XrayService) RemoveClients(ctx context.Context, emails []string) error {
-	return s.client.RemoveClients(ctx, emails)
+	start := time.Now()
+	err := s.client.RemoveClients(ctx, emails)
+
+	// Clients that are already gone don't mean the panel is failing
+	tracked := err
+	if errors.Is(err, xrayclient.ErrClientNotFound) {
+		tracked = nil
+	}
+	s.track(start, "remove_clients", tracked)
+	return err
+}
+
+// GetClientsByCreator returns the clients whose TgId is the given Telegram ID across all
+// inbounds, that is the clients the user created, see helpers.GroupClientsByCreator
+func (s *XrayService) GetClientsByCreator(ctx context.Context, telegramID int64) ([]models.InboundClientRef, error) {
+	inbounds, err := s.GetInbounds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.GroupClientsByCreator(inbounds)[strconv.FormatInt(telegramID, 10)], nil
 }
 
 // GetOnlineUsers gets the online users from the server
 func (s *XrayService) GetOnlineUsers(ctx context.Context) ([]string, error) {
-	return s.client.GetOnlineUsers(ctx)
+	start := time.Now()
+	users, err := s.client.GetOnlineUsers(ctx)
+	s.track(start, "get_online_users", err)
+	return users, err
 }
 
 // ResetUserTraffic resets a user's traffic on the server
 func (s *XrayService) ResetUserTraffic(ctx context.Context, inboundID int, email string) error {
-	return s.client.ResetUserTraffic(ctx, inboundID, email)
+	start := time.Now()
+	err := s.client.ResetUserTraffic(ctx, inboundID, email)
+	s.track(start, "reset_traffic", err)
+	return err
+}
+
+// SetInboundEnabled turns a whole inbound on or off on the server
+func (s *XrayService) SetInboundEnabled(ctx context.Context, inboundID int, enable bool) error {
+	start := time.Now()
+	err := s.client.SetInboundEnabled(ctx, inboundID, enable)
+	s.track(start, "set_inbound_enabled", err)
+	return err
 }
 
 // GetSubscriptionURL gets a user's subscription URL from the server
@@ -60,6 +249,11 @@ func (s *XrayService) GetSubscriptionURL(ctx context.Context, email string) (str
 	return s.client.GetSubscriptionURL(ctx, email)
 }
 
+// FetchSubscription downloads a subscription link from the panel to check that it is live
+func (s *XrayService) FetchSubscription(ctx context.Context, subURL string) (*xrayclient.SubscriptionInfo, error) {
+	return s.client.FetchSubscription(ctx, subURL)
+}
+
 // GetAllMembers gets all members from the server
 func (s *XrayService) GetAllMembers(ctx context.Context) ([]string, error) {
 	inbounds, err := s.GetInbounds(ctx)
@@ -106,6 +300,9 @@ func (s *XrayService) GetAllMembersWithInfo(ctx context.Context, sortType models
 				if clientStat.ExpiryTime > memberInfo.ExpiryTime {
 					memberInfo.ExpiryTime = clientStat.ExpiryTime
 				}
+				if clientStat.LastOnline > memberInfo.LastOnline {
+					memberInfo.LastOnline = clientStat.LastOnline
+				}
 				// Используем наименьший ID для сортировки по порядку создания
 				if clientStat.ID < memberInfo.ID {
 					memberInfo.ID = clientStat.ID
@@ -121,6 +318,7 @@ func (s *XrayService) GetAllMembersWithInfo(ctx context.Context, sortType models
 					TotalUp:      clientStat.Up,
 					TotalDown:    clientStat.Down,
 					TotalTraffic: clientStat.Up + clientStat.Down,
+					LastOnline:   clientStat.LastOnline,
 				}
 				memberMap[baseUsername] = memberInfo
 			}
@@ -129,16 +327,16 @@ func (s *XrayService) GetAllMembersWithInfo(ctx context.Context, sortType models
 
 	// Получаем дополнительную информацию из InboundSettings для каждого пользователя
 	for _, inbound := range inbounds {
-		if inbound.Settings == "" {
-			continue
-		}
-
-		var settings models.InboundSettings
-		if err := json.Unmarshal([]byte(inbound.Settings), &settings); err != nil {
+		clients, err := helpers.ParseInboundClients(inbound)
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"operation":  "get_members_with_info",
+				"inbound_id": inbound.ID,
+			}).WithError(err).Warn("Failed to parse inbound settings")
 			continue
 		}
 
-		for _, client := range settings.Clients {
+		for _, client := range clients {
 			baseUsername := helpers.ExtractBaseUsername(client.Email)
 			if memberInfo, exists := memberMap[baseUsername]; exists {
 				// Обновляем время истечения из настроек, если оно больше
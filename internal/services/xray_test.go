@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"xui-tg-admin/internal/config"
+	"xui-tg-admin/internal/models"
+)
+
+func TestCheckEnabledInboundsHonoursFilter(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cfg := &config.Config{Server: config.ServerConfig{APIURL: "http://127.0.0.1:1", InboundsExclude: []string{"1"}}}
+	s := NewXrayService(cfg, logger)
+
+	var alerts []int
+	s.SetNoEnabledInboundsNotifier(func(total int) { alerts = append(alerts, total) })
+
+	inbounds := []models.Inbound{
+		{ID: 1, Remark: "excluded", Enable: true},
+		{ID: 2, Remark: "disabled"},
+	}
+
+	// The only enabled inbound is excluded, so new clients have nowhere to go
+	s.checkEnabledInbounds(inbounds)
+	s.checkEnabledInbounds(inbounds)
+	if len(alerts) != 1 || alerts[0] != 2 {
+		t.Fatalf("alerts = %v, want one alert for 2 inbounds", alerts)
+	}
+
+	// An allowed inbound re-arms the alert
+	inbounds[1].Enable = true
+	s.checkEnabledInbounds(inbounds)
+	inbounds[1].Enable = false
+	s.checkEnabledInbounds(inbounds)
+	if len(alerts) != 2 {
+		t.Errorf("alerts = %v, want a second alert after an allowed inbound was disabled again", alerts)
+	}
+}
+
+func TestGetAllMembersWithInfoLogsMalformedInbound(t *testing.T) {
+	inbounds := []models.Inbound{
+		{ID: 1, Protocol: "vless", Settings: `{"clients":[]}`, ClientStats: []models.ClientStat{{ID: 1, InboundID: 1, Email: "alice-1"}}},
+		{ID: 2, Protocol: "vless", Settings: `{not json`},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "test"})
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+		case "/xui/API/inbounds":
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "obj": inbounds})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	logger, hook := test.NewNullLogger()
+	s := NewXrayService(&config.Config{Server: config.ServerConfig{APIURL: server.URL}}, logger)
+
+	members, err := s.GetAllMembersWithInfo(context.Background(), models.SortByName)
+	if err != nil {
+		t.Fatalf("GetAllMembersWithInfo failed: %v", err)
+	}
+	if len(members) != 1 {
+		t.Errorf("got %d members, want the member of the readable inbound", len(members))
+	}
+
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Failed to parse inbound settings" {
+			if entry.Data["inbound_id"] != 2 {
+				t.Errorf("warning has inbound_id %v, want 2", entry.Data["inbound_id"])
+			}
+			return
+		}
+	}
+	t.Error("no warning logged for the malformed inbound")
+}
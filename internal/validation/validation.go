@@ -2,7 +2,10 @@ package validation
 
 import (
 	"fmt"
+	"math"
+	"net/url"
 	"strconv"
+	"strings"
 	"xui-tg-admin/internal/constants"
 )
 
@@ -22,8 +25,50 @@ func ValidateUsername(username string) error {
 	return nil
 }
 
-// ValidateDuration validates and parses a duration string
-func ValidateDuration(durationStr string) (int, error) {
+// ValidateTelegramUsername validates a Telegram @username without the "@"
+func ValidateTelegramUsername(username string) error {
+	if len(username) < constants.MinTelegramUsernameLength || len(username) > constants.MaxTelegramUsernameLength {
+		return fmt.Errorf("telegram username must be between %d and %d characters",
+			constants.MinTelegramUsernameLength, constants.MaxTelegramUsernameLength)
+	}
+
+	for _, r := range username {
+		if !isValidUsernameChar(r) {
+			return fmt.Errorf("telegram username can only contain letters, numbers, and underscores")
+		}
+	}
+
+	return nil
+}
+
+// NormalizeUsername trims surrounding whitespace and lowercases a username so that
+// "John " and "john" refer to the same member
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// NormalizeTag trims whitespace and a leading "#" and lowercases a tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// ValidateTag validates a member tag
+func ValidateTag(tag string) error {
+	if tag == "" || len(tag) > constants.MaxTagLength {
+		return fmt.Errorf("tag must be between 1 and %d characters", constants.MaxTagLength)
+	}
+
+	for _, r := range tag {
+		if !isValidUsernameChar(r) && r != '-' {
+			return fmt.Errorf("tag can only contain letters, numbers, underscores, and hyphens")
+		}
+	}
+
+	return nil
+}
+
+// ValidateDuration validates and parses a duration string of at most maxDays days
+func ValidateDuration(durationStr string, maxDays int) (int, error) {
 	days, err := strconv.Atoi(durationStr)
 	if err != nil {
 		return 0, fmt.Errorf("invalid duration format: must be a number")
@@ -33,13 +78,58 @@ func ValidateDuration(durationStr string) (int, error) {
 		return 0, fmt.Errorf("duration must be at least 1 day")
 	}
 
-	if days > 3650 { // 10 years max
-		return 0, fmt.Errorf("duration cannot exceed 3650 days")
+	if days > maxDays {
+		return 0, fmt.Errorf("duration cannot exceed %d days", maxDays)
 	}
 
 	return days, nil
 }
 
+// ValidateTrafficLimit validates and parses a traffic limit in GB, where 0 means unlimited
+func ValidateTrafficLimit(limitStr string) (float64, error) {
+	gb, err := strconv.ParseFloat(strings.ReplaceAll(limitStr, ",", "."), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid traffic limit format: must be a number")
+	}
+
+	if math.IsNaN(gb) || math.IsInf(gb, 0) {
+		return 0, fmt.Errorf("invalid traffic limit format: must be a number")
+	}
+
+	if gb < 0 {
+		return 0, fmt.Errorf("traffic limit cannot be negative")
+	}
+
+	if gb > constants.MaxTrafficLimitGB {
+		return 0, fmt.Errorf("traffic limit cannot exceed %d GB", constants.MaxTrafficLimitGB)
+	}
+
+	// A limit too small to be a single byte would be stored as 0, i.e. unlimited
+	if gb > 0 && int64(gb*constants.BytesInGB) == 0 {
+		return 0, fmt.Errorf("traffic limit is too small")
+	}
+
+	return gb, nil
+}
+
+// ValidateURL validates that a string is an absolute http(s) URL with a host
+func ValidateURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("malformed URL: %v", err)
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("URL must start with http:// or https://")
+	}
+
+	if parsed.Host == "" {
+		return fmt.Errorf("URL must include a host")
+	}
+
+	return nil
+}
+
 // isValidUsernameChar checks if a character is valid for usernames
 func isValidUsernameChar(r rune) bool {
 	return (r >= 'a' && r <= 'z') ||
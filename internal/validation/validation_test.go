@@ -0,0 +1,87 @@
+package validation
+
+import "testing"
+
+func TestValidateTrafficLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{name: "unlimited", input: "0", want: 0},
+		{name: "whole", input: "50", want: 50},
+		{name: "fraction", input: "1.5", want: 1.5},
+		{name: "comma decimal", input: "2,5", want: 2.5},
+		{name: "max", input: "100000", want: 100000},
+		{name: "not a number", input: "abc", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+		{name: "negative", input: "-1", wantErr: true},
+		{name: "too large", input: "100001", wantErr: true},
+		{name: "nan", input: "NaN", wantErr: true},
+		{name: "inf", input: "Inf", wantErr: true},
+		{name: "negative inf", input: "-Inf", wantErr: true},
+		{name: "rounds to zero bytes", input: "1e-12", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateTrafficLimit(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ValidateTrafficLimit(%q) = %v, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateTrafficLimit(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateTrafficLimit(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTelegramUsername(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{input: "alice"},
+		{input: "John_Doe_42"},
+		{input: "abcdefghijklmnopqrstuvwxyz012345"},
+		{input: "abcd", wantErr: true},
+		{input: "abcdefghijklmnopqrstuvwxyz0123456", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "<b>bob</b>", wantErr: true},
+		{input: "bob smith", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateTelegramUsername(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTelegramUsername(%q) error = %v, want error %v", tt.input, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "john", want: "john"},
+		{input: "John", want: "john"},
+		{input: "  JOHN_doe ", want: "john_doe"},
+		{input: "\tjohn\n", want: "john"},
+		{input: "", want: ""},
+		{input: "Иван", want: "иван"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeUsername(tt.input); got != tt.want {
+			t.Errorf("NormalizeUsername(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
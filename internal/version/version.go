@@ -0,0 +1,13 @@
+// Package version holds the build information, injected at build time with
+//
+//	go build -ldflags "-X xui-tg-admin/internal/version.Version=v1.2.3 \
+//	  -X xui-tg-admin/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X xui-tg-admin/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+// Build information; plain go build leaves the defaults
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
@@ -3,16 +3,25 @@ package telegrambot
 import (
 	"context"
 	"fmt"
+	"html"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 	telebot "gopkg.in/telebot.v3"
 
 	"xui-tg-admin/internal/commands"
 	"xui-tg-admin/internal/config"
+	"xui-tg-admin/internal/constants"
 	"xui-tg-admin/internal/handlers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/metrics"
+	"xui-tg-admin/internal/models"
 	"xui-tg-admin/internal/permissions"
 	"xui-tg-admin/internal/services"
+	"xui-tg-admin/internal/version"
 )
 
 // Bot represents a Telegram bot
@@ -21,9 +30,15 @@ type Bot struct {
 	config         *config.Config
 	handlers       map[permissions.AccessType]handlers.MessageHandler
 	stateService   *services.UserStateService
+	xrayService    *services.XrayService
 	storageService *services.StorageService
 	permCtrl       *permissions.PermissionController
 	logger         *logrus.Logger
+	inFlight       sync.WaitGroup
+	inFlightMu     sync.Mutex // orders inFlight.Add against the shutdown wait
+	stopping       bool       // set once shutdown waits for handlers; later updates are dropped
+	limiter        *rateLimiter
+	localizer      *i18n.Localizer
 }
 
 // NewBot creates a new Telegram bot
@@ -33,9 +48,12 @@ func NewBot(
 	xrayService *services.XrayService,
 	qrService *services.QRService,
 	storageService *services.StorageService,
+	chartService *services.ChartService,
 	permCtrl *permissions.PermissionController,
 	logger *logrus.Logger,
 ) (*Bot, error) {
+	localizer := i18n.NewLocalizer(cfg.Language)
+
 	// Create bot settings
 	settings := telebot.Settings{
 		Token:  cfg.Telegram.Token,
@@ -43,7 +61,7 @@ func NewBot(
 		OnError: func(err error, c telebot.Context) {
 			logger.Errorf("Telegram bot error: %v", err)
 			if c != nil {
-				c.Send("An error occurred. Please try again later.")
+				c.Send(localizer.T(i18n.GenericError))
 			}
 		},
 	}
@@ -55,7 +73,7 @@ func NewBot(
 	}
 
 	// Create handler factory
-	factory := handlers.NewHandlerFactory(xrayService, stateService, qrService, storageService, cfg, logger)
+	factory := handlers.NewHandlerFactory(xrayService, stateService, qrService, storageService, chartService, cfg, logger)
 
 	// Create bot
 	bot := &Bot{
@@ -63,11 +81,23 @@ func NewBot(
 		config:         cfg,
 		handlers:       make(map[permissions.AccessType]handlers.MessageHandler),
 		stateService:   stateService,
+		xrayService:    xrayService,
 		storageService: storageService,
 		permCtrl:       permCtrl,
+		localizer:      localizer,
 		logger:         logger,
 	}
 
+	// Enable per-user rate limiting unless disabled
+	if cfg.Telegram.RateLimit > 0 {
+		bot.limiter = newRateLimiter(cfg.Telegram.RateLimit, time.Minute)
+	}
+
+	// Alert admins when the panel keeps failing
+	xrayService.SetUnhealthyNotifier(bot.notifyPanelUnhealthy)
+	xrayService.SetNoEnabledInboundsNotifier(bot.notifyNoEnabledInbounds)
+	xrayService.SetMalformedInboundsNotifier(bot.notifyMalformedInbounds)
+
 	// Initialize handlers for different access types
 	bot.handlers[permissions.Admin] = factory.CreateHandler(permissions.Admin)
 	bot.handlers[permissions.Trusted] = factory.CreateHandler(permissions.Trusted)
@@ -82,6 +112,23 @@ func NewBot(
 func (b *Bot) Start(ctx context.Context) error {
 	b.logger.Info("Starting Telegram bot")
 
+	if b.config.Telegram.WebhookURL != "" {
+		poller, err := newWebhookPoller(b.bot, b.config.Telegram, b.logger)
+		if err != nil {
+			return err
+		}
+		b.bot.Poller = poller
+		b.logger.Infof("Receiving updates through the webhook on %s", b.config.Telegram.WebhookListen)
+	} else if err := b.bot.RemoveWebhook(); err != nil {
+		// Telegram refuses long polling while a webhook from an earlier run is registered
+		b.logger.Warnf("Failed to remove webhook: %v", err)
+	}
+
+	// Remind trusted users of their expiring accounts
+	if b.config.ExpiryReminderDays > 0 {
+		go b.runExpiryReminders(ctx)
+	}
+
 	// Setup context for graceful shutdown
 	go func() {
 		<-ctx.Done()
@@ -91,52 +138,387 @@ func (b *Bot) Start(ctx context.Context) error {
 
 	// Start the bot
 	b.bot.Start()
+
+	// Let in-flight handlers finish so multi-step operations aren't cut in half
+	b.waitForHandlers()
 	return nil
 }
 
+// trackHandler counts a handler as in flight, or reports false once shutdown has started.
+// telebot runs each handler in its own goroutine, so without the mutex an Add could race
+// with the Wait in waitForHandlers.
+func (b *Bot) trackHandler() bool {
+	b.inFlightMu.Lock()
+	defer b.inFlightMu.Unlock()
+
+	if b.stopping {
+		return false
+	}
+	b.inFlight.Add(1)
+	return true
+}
+
+// waitForHandlers waits for in-flight handlers to finish, up to the configured shutdown timeout
+func (b *Bot) waitForHandlers() {
+	b.inFlightMu.Lock()
+	b.stopping = true
+	b.inFlightMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.inFlight.Wait()
+		close(done)
+	}()
+
+	timeout := time.Duration(b.config.Telegram.ShutdownTimeout) * time.Second
+	select {
+	case <-done:
+		b.logger.Info("All in-flight handlers finished")
+	case <-time.After(timeout):
+		b.logger.Warnf("Timed out after %s waiting for in-flight handlers", timeout)
+	}
+}
+
 // setupMiddleware sets up the bot middleware
 func (b *Bot) setupMiddleware() {
 	// Add middleware for all updates
 	b.bot.Use(func(next telebot.HandlerFunc) telebot.HandlerFunc {
 		return func(c telebot.Context) error {
+			// Track handler execution for graceful shutdown
+			if !b.trackHandler() {
+				return nil
+			}
+			defer b.inFlight.Done()
+
+			kind := updateKind(c)
+			start := time.Now()
+			if b.config.MetricsAddr != "" {
+				metrics.SeenUser(c.Sender().ID)
+			}
+
 			// Log incoming message
-			b.logger.Infof("Received message from %d: %s", c.Sender().ID, c.Text())
+			b.logger.WithFields(logrus.Fields{
+				"user_id":  c.Sender().ID,
+				"username": c.Sender().Username,
+			}).Infof("Received message: %s", c.Text())
+
+			// Drop updates from users who are sending too fast
+			if !b.allowUpdate(c) {
+				metrics.UpdatesTotal.Inc(kind, "dropped")
+				return nil
+			}
+
+			// Reject oversized text before it can reach a handler or the state cache
+			if !b.allowInputLength(c) {
+				metrics.UpdatesTotal.Inc(kind, "dropped")
+				return nil
+			}
 
 			// Pass to the next handler
-			return next(c)
+			err := next(c)
+
+			result := "ok"
+			if err != nil {
+				result = "error"
+			}
+			metrics.UpdatesTotal.Inc(kind, result)
+			metrics.UpdateDuration.Observe(start, kind)
+			return err
 		}
 	})
 
 	// Handle all messages
 	b.bot.Handle(telebot.OnText, b.handleUpdate)
 	b.bot.Handle(telebot.OnCallback, b.handleUpdate)
+	b.bot.Handle(telebot.OnDocument, b.handleUpdate)
+	for _, endpoint := range []string{
+		telebot.OnPhoto, telebot.OnSticker, telebot.OnVideo, telebot.OnAnimation, telebot.OnVoice,
+		telebot.OnVideoNote, telebot.OnAudio, telebot.OnLocation, telebot.OnContact,
+	} {
+		b.bot.Handle(endpoint, b.handleNonText)
+	}
 	b.bot.Handle(commands.Start, b.handleUpdate)
+	b.bot.Handle(commands.Menu, b.handleMenu)
+	b.bot.Handle(commands.WhoAmI, b.handleWhoAmI)
+	b.bot.Handle(commands.Support, b.handleSupport)
+	b.bot.Handle(commands.Version, b.handleVersion)
+	b.bot.Handle(commands.As, b.handleAs)
+}
+
+// updateKind names the kind of update for metrics
+func updateKind(c telebot.Context) string {
+	switch {
+	case c.Callback() != nil:
+		return "callback"
+	case c.Message() == nil:
+		return "other"
+	case c.Message().Document != nil:
+		return "document"
+	case strings.HasPrefix(c.Text(), "/"):
+		return "command"
+	case c.Text() != "":
+		return "text"
+	default:
+		return "media"
+	}
 }
 
-// handleUpdate handles an update from Telegram
+// handleSupport shows the configured support contact and the caller's Telegram ID.
+// It is available to everyone, so users without access know whom to ask.
+func (b *Bot) handleSupport(c telebot.Context) error {
+	message := b.localizer.T(i18n.SupportNotConfigured)
+	if contact := b.config.Telegram.SupportContact; contact != "" {
+		message = b.localizer.T(i18n.SupportHeader, html.EscapeString(contact))
+	}
+	message += b.localizer.T(i18n.SupportYourID, c.Sender().ID)
+
+	return c.Send(message, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+}
+
+// handleVersion reports the version, commit and build date of the running bot.
+// It is available to everyone.
+func (b *Bot) handleVersion(c telebot.Context) error {
+	message := b.localizer.T(i18n.VersionInfo,
+		html.EscapeString(version.Version), html.EscapeString(version.Commit), html.EscapeString(version.BuildDate))
+
+	return c.Send(message, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+}
+
+// supportContactLine returns the support contact line to append to access notices, if configured
+func (b *Bot) supportContactLine() string {
+	if b.config.Telegram.SupportContact == "" {
+		return ""
+	}
+	return b.localizer.T(i18n.SupportContactLine, html.EscapeString(b.config.Telegram.SupportContact))
+}
+
+// handleWhoAmI reports the caller's Telegram identity and access level.
+// It is available to everyone, so users without access can find their ID.
+func (b *Bot) handleWhoAmI(c telebot.Context) error {
+	userID := c.Sender().ID
+	username := c.Sender().Username
+
+	if username != "" {
+		b.checkAndUpdateTrustedUser(username, userID)
+		b.checkAndUpdateAdminUser(username, userID)
+	}
+
+	displayName := "—"
+	if username != "" {
+		displayName = "@" + username
+	}
+
+	accessType, _ := b.effectiveAccessType(userID)
+	message := b.localizer.T(i18n.WhoAmI, displayName, userID, accessType)
+	if accessType == permissions.None {
+		message += b.localizer.T(i18n.WhoAmIRequestAccess) + b.supportContactLine()
+	}
+	message += b.actingAsBanner(userID)
+
+	return c.Send(message, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+}
+
+// allowUpdate applies the per-user rate limit, warning the user once when it is exceeded
+func (b *Bot) allowUpdate(c telebot.Context) bool {
+	if b.limiter == nil {
+		return true
+	}
+
+	userID := c.Sender().ID
+	if !b.config.Telegram.RateLimitAdmins && b.permCtrl.GetAccessType(userID) == permissions.Admin {
+		return true
+	}
+
+	allowed, notify := b.limiter.Allow(userID)
+	if allowed {
+		return true
+	}
+
+	b.logger.WithField("user_id", userID).Warn("Rate limit exceeded, dropping update")
+	if notify {
+		if err := c.Send(b.localizer.T(i18n.RateLimited)); err != nil {
+			b.logger.Errorf("Failed to send rate limit notice: %v", err)
+		}
+	}
+	return false
+}
+
+// allowInputLength rejects text messages longer than constants.MaxInputLength
+func (b *Bot) allowInputLength(c telebot.Context) bool {
+	// Callback text is the bot's own message, not user input
+	if c.Callback() != nil {
+		return true
+	}
+
+	if utf8.RuneCountInString(c.Text()) <= constants.MaxInputLength {
+		return true
+	}
+
+	b.logger.WithField("user_id", c.Sender().ID).Warn("Input too long, dropping update")
+	if err := c.Send(b.localizer.T(i18n.InputTooLong, constants.MaxInputLength), &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+		b.logger.Errorf("Failed to send input length notice: %v", err)
+	}
+	return false
+}
+
+// notifyPanelUnhealthy messages every admin that a panel operation keeps failing
+func (b *Bot) notifyPanelUnhealthy(operation string, failures int, window time.Duration, lastErr error) {
+	message := b.localizer.T(i18n.PanelUnhealthyAlert,
+		html.EscapeString(operation), failures, int(window.Minutes()), html.EscapeString(lastErr.Error()))
+
+	for _, adminID := range b.adminIDs() {
+		if _, err := b.bot.Send(&telebot.User{ID: adminID}, message, &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+			b.logger.Errorf("Failed to send panel alert to admin %d: %v", adminID, err)
+		}
+	}
+}
+
+// notifyNoEnabledInbounds messages every admin that no inbound is enabled, with a hint on
+// how to fix it
+func (b *Bot) notifyNoEnabledInbounds(total int) {
+	message := b.localizer.T(i18n.NoEnabledInboundsAlert, total) + b.localizer.T(i18n.NoEnabledInboundsHint, "🔌 "+commands.Label(commands.ToggleInbound))
+
+	for _, adminID := range b.adminIDs() {
+		if _, err := b.bot.Send(&telebot.User{ID: adminID}, message, &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+			b.logger.Errorf("Failed to send inbounds alert to admin %d: %v", adminID, err)
+		}
+	}
+}
+
+// notifyMalformedInbounds messages every admin which inbounds have settings the bot can't
+// read
+func (b *Bot) notifyMalformedInbounds(inbounds []models.Inbound) {
+	labels := make([]string, 0, len(inbounds))
+	for _, inbound := range inbounds {
+		labels = append(labels, fmt.Sprintf("#%d %s", inbound.ID, html.EscapeString(inbound.Remark)))
+	}
+	message := b.localizer.T(i18n.MalformedInboundsAlert, strings.Join(labels, ", "))
+
+	for _, adminID := range b.adminIDs() {
+		if _, err := b.bot.Send(&telebot.User{ID: adminID}, message, &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+			b.logger.Errorf("Failed to send inbounds alert to admin %d: %v", adminID, err)
+		}
+	}
+}
+
+// adminIDs returns the Telegram IDs of all known admins, from the config and storage
+func (b *Bot) adminIDs() []int64 {
+	seen := make(map[int64]bool)
+	var ids []int64
+
+	for _, id := range b.config.Telegram.AdminIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	// Admins added by username keep a placeholder ID until they message the bot,
+	// so sending to them fails and is only logged
+	for _, admin := range b.storageService.GetAdminUsers() {
+		if !seen[admin.TelegramID] {
+			seen[admin.TelegramID] = true
+			ids = append(ids, admin.TelegramID)
+		}
+	}
+
+	return ids
+}
+
+// handleUpdate routes an update to the handler for the sender's access type
 func (b *Bot) handleUpdate(c telebot.Context) error {
+	handler, err := b.resolveHandler(c)
+	if handler == nil {
+		return err
+	}
+
+	// Handle the update
+	ctx := context.Background()
+	if err := handler.Handle(ctx, c); err != nil {
+		return err
+	}
+
+	// Remind admins using /as whenever they land on the start message
+	if c.Message() != nil && strings.HasPrefix(c.Text(), commands.Start) {
+		b.sendActingAsBanner(c)
+	}
+	return nil
+}
+
+// handleMenu re-shows the main keyboard without the welcome text or clearing the selected member
+func (b *Bot) handleMenu(c telebot.Context) error {
+	handler, err := b.resolveHandler(c)
+	if handler == nil {
+		return err
+	}
+
+	if err := handler.ShowMenu(c); err != nil {
+		return err
+	}
+	b.sendActingAsBanner(c)
+	return nil
+}
+
+// handleNonText answers photos, stickers and other non-text messages, which no flow accepts.
+// Mid-flow the user is asked for text and the flow is kept, otherwise the menu is shown.
+func (b *Bot) handleNonText(c telebot.Context) error {
+	handler, err := b.resolveHandler(c)
+	if handler == nil {
+		return err
+	}
+
+	userState, err := b.stateService.GetState(c.Sender().ID)
+	if err != nil {
+		b.logger.Errorf("Failed to get user state: %v", err)
+		return err
+	}
+
+	switch userState.State {
+	case models.Default:
+		return handler.ShowMenu(c)
+	case models.AwaitingRestoreDocument:
+		// The restore flow explains that it needs a backup document
+		return handler.Handle(context.Background(), c)
+	default:
+		return c.Send(b.localizer.T(i18n.TextExpected), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	}
+}
+
+// resolveHandler returns the handler for the sender's access type. Users without access
+// get a notice instead, in which case the handler is nil and the send error is returned.
+func (b *Bot) resolveHandler(c telebot.Context) (handlers.MessageHandler, error) {
 	// Get user ID and username
 	userID := c.Sender().ID
 	username := c.Sender().Username
 
-	// Check if user is trusted by username and update their telegram ID if needed
+	// Check if user is trusted or an admin by username and update their telegram ID if needed
 	if username != "" {
 		b.checkAndUpdateTrustedUser(username, userID)
+		b.checkAndUpdateAdminUser(username, userID)
 	}
 
-	// Get access type
-	accessType := b.permCtrl.GetAccessType(userID)
+	// Get access type, which admins may have switched with /as
+	accessType, _ := b.effectiveAccessType(userID)
 
 	// Get handler for access type
 	handler, ok := b.handlers[accessType]
 	if !ok || accessType == permissions.None {
 		b.logger.Warnf("No handler for access type %d", accessType)
-		return c.Send("You don't have permission to use this bot.")
+		// Give the user what they need to ask for access instead of a dead end
+		message := b.noAccessMessage() + b.localizer.T(i18n.NoAccessYourID, userID) + b.supportContactLine() +
+			b.actingAsBanner(userID)
+		return nil, c.Send(message, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
 	}
 
-	// Handle the update
-	ctx := context.Background()
-	return handler.Handle(ctx, c)
+	return handler, nil
+}
+
+// noAccessMessage returns the message for users without access, preferring the configured one
+func (b *Bot) noAccessMessage() string {
+	if b.config.Welcome.None != "" {
+		return b.config.Welcome.None
+	}
+	return b.localizer.T(i18n.NoPermission)
 }
 
 // checkAndUpdateTrustedUser checks if a user is trusted by username and updates their telegram ID
@@ -151,3 +533,15 @@ func (b *Bot) checkAndUpdateTrustedUser(username string, telegramID int64) {
 		}
 	}
 }
+
+// checkAndUpdateAdminUser checks if a user was made admin by username and updates their telegram ID
+func (b *Bot) checkAndUpdateAdminUser(username string, telegramID int64) {
+	if isAdmin, storedID := b.storageService.IsAdminByUsername(username); isAdmin {
+		if storedID != telegramID {
+			b.logger.Infof("Updating telegram ID for admin @%s: %d -> %d", username, storedID, telegramID)
+			if err := b.storageService.UpdateAdminTelegramID(username, telegramID); err != nil {
+				b.logger.Errorf("Failed to update telegram ID for admin @%s: %v", username, err)
+			}
+		}
+	}
+}
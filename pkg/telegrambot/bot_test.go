@@ -0,0 +1,42 @@
+package telegrambot
+
+import (
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"xui-tg-admin/internal/config"
+)
+
+func TestWaitForHandlers(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	b := &Bot{
+		config: &config.Config{Telegram: config.TelegramConfig{ShutdownTimeout: 5}},
+		logger: logger,
+	}
+
+	if !b.trackHandler() {
+		t.Fatal("trackHandler refused a handler before shutdown")
+	}
+	var finished atomic.Bool
+	go func() {
+		defer b.inFlight.Done()
+		time.Sleep(20 * time.Millisecond)
+		finished.Store(true)
+	}()
+
+	b.waitForHandlers()
+	if !finished.Load() {
+		t.Error("waitForHandlers returned before the in-flight handler finished")
+	}
+
+	// Updates that arrive once shutdown started aren't handled
+	if b.trackHandler() {
+		b.inFlight.Done()
+		t.Error("trackHandler accepted a handler after shutdown started")
+	}
+}
@@ -0,0 +1,101 @@
+package telegrambot
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/permissions"
+)
+
+// actingLevels maps the /as arguments to the access levels an admin can see the bot as
+var actingLevels = map[string]permissions.AccessType{
+	"trusted": permissions.Trusted,
+	"none":    permissions.None,
+}
+
+// handleAs lets an admin see the bot as another access level, e.g. "/as trusted", to try
+// its menus. "/as admin" returns to the admin view; the level also ends with the session.
+func (b *Bot) handleAs(c telebot.Context) error {
+	userID := c.Sender().ID
+	if b.permCtrl.GetAccessType(userID) != permissions.Admin {
+		return c.Send(b.localizer.T(i18n.ActingAsAdminOnly), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	}
+
+	arg := strings.ToLower(strings.TrimSpace(c.Message().Payload))
+	current, _ := b.effectiveAccessType(userID)
+	log := b.logger.WithFields(logrus.Fields{"operation": "act_as", "user_id": userID})
+
+	if arg == "admin" {
+		if err := b.stateService.WithActingAs(userID, nil); err != nil {
+			b.logger.Errorf("Failed to set state: %v", err)
+			return err
+		}
+		log.Info("Stopped acting as another access level")
+		if err := c.Send(b.localizer.T(i18n.ActingAsStopped), &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+			return err
+		}
+		return b.handlers[permissions.Admin].ShowMenu(c)
+	}
+
+	level, ok := actingLevels[arg]
+	if !ok {
+		return c.Send(b.localizer.T(i18n.ActingAsUsage, current), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	}
+
+	// Start from a clean conversation so no admin flow carries over
+	if err := b.stateService.ClearState(userID); err != nil {
+		b.logger.Errorf("Failed to clear user state: %v", err)
+	}
+	if err := b.stateService.WithActingAs(userID, &level); err != nil {
+		b.logger.Errorf("Failed to set state: %v", err)
+		return err
+	}
+	log.WithField("level", level.String()).Info("Acting as another access level")
+
+	if err := c.Send(b.localizer.T(i18n.ActingAsStarted, level, level), &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+		return err
+	}
+	handler, err := b.resolveHandler(c)
+	if handler == nil {
+		return err
+	}
+	return handler.ShowMenu(c)
+}
+
+// effectiveAccessType returns the access level the bot treats the user as: the level an
+// admin picked with /as, or their real one. It reports whether /as is in effect.
+func (b *Bot) effectiveAccessType(userID int64) (permissions.AccessType, bool) {
+	accessType := b.permCtrl.GetAccessType(userID)
+	if accessType != permissions.Admin {
+		return accessType, false
+	}
+
+	userState, err := b.stateService.GetState(userID)
+	if err != nil || userState.ActingAs == nil {
+		return accessType, false
+	}
+	return *userState.ActingAs, true
+}
+
+// actingAsBanner reminds an admin using /as which level they see the bot as, or is empty
+func (b *Bot) actingAsBanner(userID int64) string {
+	accessType, acting := b.effectiveAccessType(userID)
+	if !acting {
+		return ""
+	}
+	return b.localizer.T(i18n.ActingAsBanner, accessType)
+}
+
+// sendActingAsBanner reminds an admin using /as which level they see the bot as
+func (b *Bot) sendActingAsBanner(c telebot.Context) {
+	banner := b.actingAsBanner(c.Sender().ID)
+	if banner == "" {
+		return
+	}
+	if err := c.Send(strings.TrimSpace(banner), &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+		b.logger.Errorf("Failed to send acting-as banner: %v", err)
+	}
+}
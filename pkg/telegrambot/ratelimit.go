@@ -0,0 +1,72 @@
+package telegrambot
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter tracks recent updates per Telegram user within a sliding window
+type rateLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	window   time.Duration
+	hits     map[int64][]time.Time
+	notified map[int64]bool
+	swept    time.Time // when users without recent hits were last forgotten
+}
+
+// newRateLimiter creates a rate limiter allowing limit updates per window
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:    limit,
+		window:   window,
+		hits:     make(map[int64][]time.Time),
+		notified: make(map[int64]bool),
+	}
+}
+
+// Allow records an update from the user and reports whether it is within the limit.
+// The second return value is true only for the first rejected update in a burst,
+// so the user is warned once instead of on every dropped update.
+func (r *rateLimiter) Allow(userID int64) (bool, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-r.window)
+
+	// Forget users who went quiet once per window, so the maps don't grow forever
+	if now.Sub(r.swept) > r.window {
+		r.sweep(cutoff)
+		r.swept = now
+	}
+
+	// Drop hits that fell out of the window
+	recent := r.hits[userID][:0]
+	for _, t := range r.hits[userID] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= r.limit {
+		r.hits[userID] = recent
+		shouldNotify := !r.notified[userID]
+		r.notified[userID] = true
+		return false, shouldNotify
+	}
+
+	r.hits[userID] = append(recent, now)
+	delete(r.notified, userID)
+	return true, false
+}
+
+// sweep forgets users whose last update is older than cutoff; the caller holds the mutex
+func (r *rateLimiter) sweep(cutoff time.Time) {
+	for userID, hits := range r.hits {
+		if len(hits) == 0 || !hits[len(hits)-1].After(cutoff) {
+			delete(r.hits, userID)
+			delete(r.notified, userID)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package telegrambot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	r := newRateLimiter(2, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := r.Allow(1); !ok {
+			t.Fatalf("update %d was rejected within the limit", i+1)
+		}
+	}
+	if ok, notify := r.Allow(1); ok || !notify {
+		t.Errorf("Allow over the limit = %v, %v; want rejected with a warning", ok, notify)
+	}
+	if ok, notify := r.Allow(1); ok || notify {
+		t.Errorf("Allow over the limit again = %v, %v; want rejected without a warning", ok, notify)
+	}
+	if ok, _ := r.Allow(2); !ok {
+		t.Error("another user was rejected")
+	}
+}
+
+func TestRateLimiterForgetsQuietUsers(t *testing.T) {
+	r := newRateLimiter(1, time.Minute)
+
+	// User 1 hit the limit two windows ago and hasn't written since
+	old := time.Now().Add(-2 * time.Minute)
+	r.hits[1] = []time.Time{old}
+	r.notified[1] = true
+	r.swept = old
+
+	if ok, _ := r.Allow(2); !ok {
+		t.Fatal("first update of a user was rejected")
+	}
+	if _, ok := r.hits[1]; ok {
+		t.Error("hits of a quiet user are still kept")
+	}
+	if _, ok := r.notified[1]; ok {
+		t.Error("warning state of a quiet user is still kept")
+	}
+	if len(r.hits[2]) != 1 {
+		t.Errorf("user 2 has %d hits, want 1", len(r.hits[2]))
+	}
+}
@@ -0,0 +1,112 @@
+package telegrambot
+
+import (
+	"context"
+	"html"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/constants"
+	"xui-tg-admin/internal/handlers"
+	"xui-tg-admin/internal/helpers"
+	"xui-tg-admin/internal/i18n"
+	"xui-tg-admin/internal/models"
+)
+
+// runExpiryReminders checks for expiring accounts right away and then every
+// ExpiryReminderInterval until ctx is cancelled
+func (b *Bot) runExpiryReminders(ctx context.Context) {
+	ticker := time.NewTicker(constants.ExpiryReminderInterval * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		b.sendExpiryReminders(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendExpiryReminders messages trusted users whose accounts expire within
+// ExpiryReminderDays. Each expiry is reminded of once, so a renewed account gets a new
+// reminder before its next expiry. Users still known only by a placeholder ID, or who
+// opted out, are skipped.
+func (b *Bot) sendExpiryReminders(ctx context.Context) {
+	var owners []models.TrustedUser
+	for _, user := range b.storageService.GetTrustedUsers() {
+		if user.Username != "" && user.TelegramID == helpers.PseudoTelegramID(user.Username) {
+			continue
+		}
+		if b.storageService.RemindersEnabled(user.TelegramID) && len(b.storageService.GetUserAccounts(user.TelegramID)) > 0 {
+			owners = append(owners, user)
+		}
+	}
+	if len(owners) == 0 {
+		return
+	}
+
+	inbounds, err := b.xrayService.GetInbounds(ctx)
+	if err != nil {
+		b.logger.Warnf("Failed to get inbounds for expiry reminders: %v", err)
+		return
+	}
+
+	now := time.Now()
+	window := time.Duration(b.config.ExpiryReminderDays) * 24 * time.Hour
+	sent := 0
+	for _, owner := range owners {
+		for _, account := range b.storageService.GetUserAccounts(owner.TelegramID) {
+			usage := helpers.AccountUsage(inbounds, account.Username)
+			if usage == nil || usage.ExpiryTime == 0 {
+				continue
+			}
+			expiry := time.UnixMilli(usage.ExpiryTime)
+			if !expiry.After(now) || expiry.Sub(now) > window {
+				continue
+			}
+			if b.storageService.RemindedExpiry(account.Username) == usage.ExpiryTime {
+				continue
+			}
+
+			if sent > 0 {
+				time.Sleep(constants.BroadcastInterval * time.Millisecond)
+			}
+			sent++
+
+			log := b.logger.WithFields(logrus.Fields{
+				"operation": "expiry_reminder",
+				"user_id":   owner.TelegramID,
+				"username":  account.Username,
+			})
+			if err := b.sendExpiryReminder(owner.TelegramID, usage, expiry); err != nil {
+				log.WithError(err).Warn("Failed to send expiry reminder")
+				continue
+			}
+			if err := b.storageService.SetRemindedExpiry(account.Username, usage.ExpiryTime); err != nil {
+				log.WithError(err).Error("Failed to save expiry reminder")
+			}
+			log.Info("Sent expiry reminder")
+		}
+	}
+}
+
+// sendExpiryReminder sends one reminder with a button to stop further ones
+func (b *Bot) sendExpiryReminder(telegramID int64, usage *models.MemberInfo, expiry time.Time) error {
+	message := b.localizer.T(i18n.ExpiryReminder,
+		html.EscapeString(usage.BaseUsername), expiry.Format(constants.DateFormat), usage.GetExpiryStatus(b.localizer))
+	message += b.supportContactLine()
+
+	markup := &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{{Text: b.localizer.T(i18n.ExpiryReminderOffButton), Data: handlers.RemindersOffData}},
+		},
+	}
+
+	_, err := b.bot.Send(&telebot.User{ID: telegramID}, message, &telebot.SendOptions{ParseMode: telebot.ModeHTML, ReplyMarkup: markup})
+	return err
+}
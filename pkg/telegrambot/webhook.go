@@ -0,0 +1,128 @@
+package telegrambot
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	telebot "gopkg.in/telebot.v3"
+
+	"xui-tg-admin/internal/config"
+	"xui-tg-admin/internal/constants"
+)
+
+// webhookPoller receives updates through a Telegram webhook. telebot's own Webhook
+// poller can't be stopped without panicking, so this one serves the updates itself and
+// shuts its server down when the bot stops.
+type webhookPoller struct {
+	listener net.Listener
+	server   *http.Server
+	secret   string
+	certFile string
+	keyFile  string
+	logger   *logrus.Logger
+}
+
+// newWebhookPoller registers the webhook with Telegram and opens the listener, so a bad
+// URL or a busy port fails startup instead of leaving a bot that never gets updates
+func newWebhookPoller(b *telebot.Bot, cfg config.TelegramConfig, logger *logrus.Logger) (*webhookPoller, error) {
+	// Updates carry the sender's ID, so only requests bearing this secret are trusted
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", cfg.WebhookListen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", cfg.WebhookListen, err)
+	}
+
+	endpoint := &telebot.WebhookEndpoint{PublicURL: cfg.WebhookURL, Cert: cfg.WebhookCert}
+	if err := b.SetWebhook(&telebot.Webhook{Endpoint: endpoint, SecretToken: secret}); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to register webhook: %w", err)
+	}
+
+	return &webhookPoller{
+		listener: listener,
+		secret:   secret,
+		certFile: cfg.WebhookCert,
+		keyFile:  cfg.WebhookKey,
+		logger:   logger,
+	}, nil
+}
+
+// generateWebhookSecret creates the token Telegram sends back with every webhook request
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Poll serves webhook requests until stop is closed
+func (p *webhookPoller) Poll(b *telebot.Bot, dest chan telebot.Update, stop chan struct{}) {
+	p.server = &http.Server{
+		Handler:           p.handler(dest, stop),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), constants.WebhookShutdownTimeout*time.Second)
+		defer cancel()
+		if err := p.server.Shutdown(ctx); err != nil {
+			p.logger.Warnf("Webhook server did not shut down cleanly: %v", err)
+		}
+	}()
+
+	var err error
+	if p.certFile != "" && p.keyFile != "" {
+		err = p.server.ServeTLS(p.listener, p.certFile, p.keyFile)
+	} else {
+		err = p.server.Serve(p.listener)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		p.logger.Errorf("Webhook server failed: %v", err)
+	}
+}
+
+// handler decodes each webhook request into an update for the bot
+func (p *webhookPoller) handler(dest chan telebot.Update, stop chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		// Compare in constant time so the secret can't be guessed byte by byte
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Telegram-Bot-Api-Secret-Token")), []byte(p.secret)) != 1 {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		var update telebot.Update
+		body := http.MaxBytesReader(w, r.Body, constants.MaxWebhookBodySize)
+		if err := json.NewDecoder(body).Decode(&update); err != nil {
+			p.logger.Warnf("Failed to decode webhook update: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		// The bot stops reading updates once it's stopping; Telegram redelivers
+		// anything that isn't acknowledged
+		select {
+		case dest <- update:
+		case <-stop:
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		}
+	})
+}
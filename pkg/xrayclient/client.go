@@ -3,10 +3,12 @@ package xrayclient
 import (
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -35,6 +37,64 @@ type XrayAPIResponse struct {
 	Obj     interface{} `json:"obj"`
 }
 
+// PingResult holds the outcome of a connectivity check against the X-ray API
+type PingResult struct {
+	LoginLatency    time.Duration
+	InboundsLatency time.Duration
+	StatusCode      int
+	InboundCount    int
+}
+
+// ErrSubscriptionNotFound is returned when the panel has no subscription for the requested link
+var ErrSubscriptionNotFound = errors.New("subscription not found")
+
+// ErrClientNotFound is returned by RemoveClients when none of the users has a client left
+// on the panel, e.g. because they were deleted in X-UI directly
+var ErrClientNotFound = errors.New("client not found in any inbound")
+
+// APIError is returned when the panel answers a request with an error status code, or
+// with a response that reports the request as unsuccessful
+type APIError struct {
+	Operation string // e.g. "login" or "add client"
+	Status    int    // HTTP status code, http.StatusOK when the response reported the failure
+	Message   string // the panel's message or response body, if any
+}
+
+func (e *APIError) Error() string {
+	if e.Status == http.StatusOK {
+		return fmt.Sprintf("%s failed: %s", e.Operation, e.Message)
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("%s failed with status code: %d", e.Operation, e.Status)
+	}
+	return fmt.Sprintf("%s failed with status code: %d, response: %s", e.Operation, e.Status, e.Message)
+}
+
+// IsAuth reports whether the panel rejected the bot's credentials or session
+func (e *APIError) IsAuth() bool {
+	return e.Status == http.StatusUnauthorized || e.Status == http.StatusForbidden ||
+		(e.Operation == "login" && e.Status == http.StatusOK)
+}
+
+// IsNotFound reports whether the panel doesn't know the requested resource
+func (e *APIError) IsNotFound() bool {
+	return e.Status == http.StatusNotFound
+}
+
+// IsServer reports whether the panel failed with a server error
+func (e *APIError) IsServer() bool {
+	return e.Status >= http.StatusInternalServerError
+}
+
+// SubscriptionInfo describes the content the panel serves for a subscription link
+type SubscriptionInfo struct {
+	ConfigCount int
+	Upload      int64
+	Download    int64
+	Total       int64
+	Expire      int64
+}
+
 // NewClient creates a new X-ray API client
 func NewClient(serverConfig config.ServerConfig, logger *logrus.Logger) *Client {
 	httpClient := resty.New().
@@ -59,8 +119,10 @@ func (c *Client) Login(ctx context.Context) error {
 		return nil
 	}
 
-	c.logger.Infof("Logging in to X-ray API at %s", c.serverConfig.APIURL)
-	c.logger.Debugf("Using username: %s", c.serverConfig.User)
+	log := c.logger.WithFields(logrus.Fields{"operation": "login", "api_url": c.serverConfig.APIURL})
+
+	log.Info("Logging in to X-ray API")
+	log.Debugf("Using username: %s", c.serverConfig.User)
 
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
@@ -76,9 +138,8 @@ func (c *Client) Login(ctx context.Context) error {
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		c.logger.Errorf("Login failed - URL: %s/login, Status: %d, Response: %s",
-			c.serverConfig.APIURL, resp.StatusCode(), string(resp.Body()))
-		return fmt.Errorf("login failed with status code: %d, response: %s", resp.StatusCode(), string(resp.Body()))
+		log.WithField("status", resp.StatusCode()).Errorf("Login failed, response: %s", string(resp.Body()))
+		return &APIError{Operation: "login", Status: resp.StatusCode(), Message: string(resp.Body())}
 	}
 
 	var apiResp XrayAPIResponse
@@ -87,22 +148,73 @@ func (c *Client) Login(ctx context.Context) error {
 	}
 
 	if !apiResp.Success {
-		return fmt.Errorf("login failed: %s", apiResp.Msg)
+		return &APIError{Operation: "login", Status: http.StatusOK, Message: apiResp.Msg}
 	}
 
 	// Store cookies for future requests
 	cookies := resp.Cookies()
 	if len(cookies) > 0 {
 		c.cookieCache.Set("session", cookies, cache.DefaultExpiration)
-		c.logger.Info("Successfully logged in to X-ray API")
+		log.Info("Successfully logged in to X-ray API")
 		return nil
 	}
 
 	return errors.New("no session cookie received from server")
 }
 
+// Ping verifies credentials with a fresh login and performs a lightweight inbounds request
+func (c *Client) Ping(ctx context.Context) (*PingResult, error) {
+	result := &PingResult{}
+
+	// Drop the cached session so the credentials are actually verified
+	c.cookieCache.Delete("session")
+
+	start := time.Now()
+	err := c.Login(ctx)
+	result.LoginLatency = time.Since(start)
+	if err != nil {
+		return result, fmt.Errorf("login failed: %w", err)
+	}
+
+	cookies, _ := c.cookieCache.Get("session")
+
+	start = time.Now()
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetCookies(cookies.([]*http.Cookie)).
+		Get(fmt.Sprintf("%s/xui/API/inbounds", c.serverConfig.APIURL))
+	result.InboundsLatency = time.Since(start)
+
+	if err != nil {
+		return result, fmt.Errorf("get inbounds request failed: %w", err)
+	}
+
+	result.StatusCode = resp.StatusCode()
+	if resp.StatusCode() != http.StatusOK {
+		return result, &APIError{Operation: "get inbounds", Status: resp.StatusCode()}
+	}
+
+	var apiResp struct {
+		Success bool              `json:"success"`
+		Msg     string            `json:"msg"`
+		Obj     []json.RawMessage `json:"obj"`
+	}
+	if err := json.Unmarshal(resp.Body(), &apiResp); err != nil {
+		return result, fmt.Errorf("failed to parse inbounds response: %w", err)
+	}
+
+	if !apiResp.Success {
+		return result, &APIError{Operation: "get inbounds", Status: http.StatusOK, Message: apiResp.Msg}
+	}
+
+	result.InboundCount = len(apiResp.Obj)
+	return result, nil
+}
+
 // GetInbounds gets the inbounds from the X-ray API
 func (c *Client) GetInbounds(ctx context.Context) ([]models.Inbound, error) {
+	log := c.logger.WithFields(logrus.Fields{"operation": "get_inbounds"})
+
 	if err := c.Login(ctx); err != nil {
 		return nil, err
 	}
@@ -123,8 +235,8 @@ func (c *Client) GetInbounds(ctx context.Context) ([]models.Inbound, error) {
 			c.cookieCache.Delete("session")
 			return c.GetInbounds(ctx)
 		}
-		c.logger.Errorf("Get inbounds failed - Status: %d, Response: %s", resp.StatusCode(), string(resp.Body()))
-		return nil, fmt.Errorf("get inbounds failed with status code: %d, response: %s", resp.StatusCode(), string(resp.Body()))
+		log.WithField("status", resp.StatusCode()).Errorf("Get inbounds failed, response: %s", string(resp.Body()))
+		return nil, &APIError{Operation: "get inbounds", Status: resp.StatusCode(), Message: string(resp.Body())}
 	}
 
 	var apiResp XrayAPIResponse
@@ -133,7 +245,7 @@ func (c *Client) GetInbounds(ctx context.Context) ([]models.Inbound, error) {
 	}
 
 	if !apiResp.Success {
-		return nil, fmt.Errorf("get inbounds failed: %s", apiResp.Msg)
+		return nil, &APIError{Operation: "get inbounds", Status: http.StatusOK, Message: apiResp.Msg}
 	}
 
 	// Convert obj to JSON and then unmarshal to inbounds
@@ -152,33 +264,22 @@ func (c *Client) GetInbounds(ctx context.Context) ([]models.Inbound, error) {
 
 // AddClientToInbound adds a client to an inbound
 func (c *Client) AddClientToInbound(ctx context.Context, inboundID int, client models.Client) error {
+	log := c.logger.WithFields(logrus.Fields{"operation": "add_client", "inbound_id": inboundID, "email": client.Email})
+
 	if err := c.Login(ctx); err != nil {
 		return err
 	}
 
 	cookies, _ := c.cookieCache.Get("session")
 
-	// Create settings object with clients array
-	settings := map[string]interface{}{
-		"clients": []map[string]interface{}{client.ToDictionary()},
-	}
-
-	// Convert settings to JSON string
-	settingsJSON, err := json.Marshal(settings)
+	requestBody, err := c.buildClientRequestBody(inboundID, client)
 	if err != nil {
-		c.logger.Errorf("Failed to marshal settings: %v", err)
-		return fmt.Errorf("failed to marshal settings: %w", err)
-	}
-
-	// Create request body
-	requestBody := map[string]interface{}{
-		"id":       inboundID,
-		"settings": string(settingsJSON),
+		return err
 	}
 
 	// Log request details
-	c.logger.Infof("Adding client to inbound %d with email: %s", inboundID, client.Email)
-	c.logger.Debugf("Request body: %+v", requestBody)
+	log.Info("Adding client to inbound")
+	log.Debugf("Request body: %+v", requestBody)
 
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
@@ -187,13 +288,13 @@ func (c *Client) AddClientToInbound(ctx context.Context, inboundID int, client m
 		Post(fmt.Sprintf("%s/xui/API/inbounds/addClient", c.serverConfig.APIURL))
 
 	if err != nil {
-		c.logger.Errorf("Add client request failed: %v", err)
+		log.WithError(err).Error("Add client request failed")
 		return fmt.Errorf("add client request failed: %w", err)
 	}
 
 	// Log response details
-	c.logger.Debugf("Response status: %d", resp.StatusCode())
-	c.logger.Debugf("Response body: %s", string(resp.Body()))
+	log.Debugf("Response status: %d", resp.StatusCode())
+	log.Debugf("Response body: %s", string(resp.Body()))
 
 	if resp.StatusCode() != http.StatusOK {
 		// If unauthorized, try to login again
@@ -201,33 +302,116 @@ func (c *Client) AddClientToInbound(ctx context.Context, inboundID int, client m
 			c.cookieCache.Delete("session")
 			return c.AddClientToInbound(ctx, inboundID, client)
 		}
-		c.logger.Errorf("Add client failed with status code %d, response body: %s", resp.StatusCode(), string(resp.Body()))
-		return fmt.Errorf("add client failed with status code: %d", resp.StatusCode())
+		log.WithField("status", resp.StatusCode()).Errorf("Add client failed, response body: %s", string(resp.Body()))
+		return &APIError{Operation: "add client", Status: resp.StatusCode()}
 	}
 
 	// Check if response body is empty
 	if len(resp.Body()) == 0 {
-		c.logger.Errorf("Empty response body from server")
+		log.Error("Empty response body from server")
 		return fmt.Errorf("empty response from server")
 	}
 
 	var apiResp XrayAPIResponse
 	if err := json.Unmarshal(resp.Body(), &apiResp); err != nil {
-		c.logger.Errorf("Failed to parse add client response: %v, response body: %s", err, string(resp.Body()))
+		log.WithError(err).Errorf("Failed to parse add client response, response body: %s", string(resp.Body()))
 		return fmt.Errorf("failed to parse add client response: %w, body: %s", err, string(resp.Body()))
 	}
 
 	if !apiResp.Success {
-		c.logger.Errorf("Add client failed with message: %s", apiResp.Msg)
-		return fmt.Errorf("add client failed: %s", apiResp.Msg)
+		log.Errorf("Add client failed with message: %s", apiResp.Msg)
+		return &APIError{Operation: "add client", Status: http.StatusOK, Message: apiResp.Msg}
 	}
 
-	c.logger.Infof("Successfully added client %s to inbound %d", client.Email, inboundID)
+	log.Info("Successfully added client to inbound")
 	return nil
 }
 
+// UpdateClient updates an existing client in an inbound
+func (c *Client) UpdateClient(ctx context.Context, inboundID int, clientUUID string, client models.Client) error {
+	log := c.logger.WithFields(logrus.Fields{"operation": "update_client", "inbound_id": inboundID, "client_id": clientUUID, "email": client.Email})
+
+	if err := c.Login(ctx); err != nil {
+		return err
+	}
+
+	cookies, _ := c.cookieCache.Get("session")
+
+	requestBody, err := c.buildClientRequestBody(inboundID, client)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Updating client in inbound")
+	log.Debugf("Request body: %+v", requestBody)
+
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetCookies(cookies.([]*http.Cookie)).
+		SetBody(requestBody).
+		Post(fmt.Sprintf("%s/xui/API/inbounds/updateClient/%s", c.serverConfig.APIURL, clientUUID))
+
+	if err != nil {
+		log.WithError(err).Error("Update client request failed")
+		return fmt.Errorf("update client request failed: %w", err)
+	}
+
+	log.Debugf("Update client response status: %d, body: %s", resp.StatusCode(), string(resp.Body()))
+
+	if resp.StatusCode() != http.StatusOK {
+		// If unauthorized, try to login again
+		if resp.StatusCode() == http.StatusUnauthorized {
+			c.cookieCache.Delete("session")
+			return c.UpdateClient(ctx, inboundID, clientUUID, client)
+		}
+		log.WithField("status", resp.StatusCode()).Errorf("Update client failed, response body: %s", string(resp.Body()))
+		return &APIError{Operation: "update client", Status: resp.StatusCode()}
+	}
+
+	if len(resp.Body()) == 0 {
+		log.Error("Empty response body from server")
+		return fmt.Errorf("empty response from server")
+	}
+
+	var apiResp XrayAPIResponse
+	if err := json.Unmarshal(resp.Body(), &apiResp); err != nil {
+		log.WithError(err).Errorf("Failed to parse update client response, response body: %s", string(resp.Body()))
+		return fmt.Errorf("failed to parse update client response: %w, body: %s", err, string(resp.Body()))
+	}
+
+	if !apiResp.Success {
+		log.Errorf("Update client failed with message: %s", apiResp.Msg)
+		return &APIError{Operation: "update client", Status: http.StatusOK, Message: apiResp.Msg}
+	}
+
+	log.Info("Successfully updated client in inbound")
+	return nil
+}
+
+// buildClientRequestBody builds the request body shared by the add and update client endpoints
+func (c *Client) buildClientRequestBody(inboundID int, client models.Client) (map[string]interface{}, error) {
+	// Create settings object with clients array
+	settings := map[string]interface{}{
+		"clients": []map[string]interface{}{client.ToDictionary()},
+	}
+
+	// Convert settings to JSON string
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		c.logger.Errorf("Failed to marshal settings: %v", err)
+		return nil, fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	return map[string]interface{}{
+		"id":       inboundID,
+		"settings": string(settingsJSON),
+	}, nil
+}
+
 // RemoveClients removes clients from inbounds
 func (c *Client) RemoveClients(ctx context.Context, emails []string) error {
+	log := c.logger.WithFields(logrus.Fields{"operation": "remove_clients"})
+
 	if err := c.Login(ctx); err != nil {
 		return err
 	}
@@ -243,6 +427,7 @@ func (c *Client) RemoveClients(ctx context.Context, emails []string) error {
 	// Track deletion results
 	var deletionErrors []string
 	successfullyDeleted := false
+	anyFound := false
 
 	// For each email, find and delete from all inbounds
 	for _, email := range emails {
@@ -251,34 +436,33 @@ func (c *Client) RemoveClients(ctx context.Context, emails []string) error {
 		// Search through all inbounds
 		for _, inbound := range inbounds {
 			// Parse inbound settings to find client UUID
-			var settings models.InboundSettings
-			if err := json.Unmarshal([]byte(inbound.Settings), &settings); err != nil {
-				c.logger.Errorf("Failed to parse settings for inbound %d: %v", inbound.ID, err)
+			clients, err := helpers.ParseInboundClients(inbound)
+			if err != nil {
+				log.WithField("inbound_id", inbound.ID).WithError(err).Warn("Failed to parse inbound settings")
 				continue
 			}
 
 			// Find client by email
-			for _, client := range settings.Clients {
+			for _, client := range clients {
 				// Ищем по базовому имени используя helper функцию
 				if helpers.IsEmailMatchingBaseUsername(client.Email, email) {
-					c.logger.Infof("Found matching client: %s in inbound %d", client.Email, inbound.ID)
+					anyFound = true
+					clientLog := log.WithFields(logrus.Fields{"inbound_id": inbound.ID, "email": client.Email})
+					clientLog.Info("Found matching client")
 
-					// Extract client UUID from client object
-					// The client UUID is typically stored in the client object
-					// We need to find the actual UUID field
-					clientUUID := c.extractClientUUID(client, client.Email)
+					clientUUID := c.extractClientUUID(inbound, client)
 					if clientUUID == "" {
-						c.logger.Errorf("Failed to extract UUID for client %s in inbound %d", client.Email, inbound.ID)
+						clientLog.Error("Failed to extract client UUID")
 						continue
 					}
 
 					// Delete client using the correct API endpoint
 					err := c.deleteClientFromInbound(ctx, cookies.([]*http.Cookie), inbound.ID, clientUUID)
 					if err != nil {
-						c.logger.Errorf("Failed to delete client %s from inbound %d: %v", client.Email, inbound.ID, err)
+						clientLog.WithError(err).Error("Failed to delete client from inbound")
 						deletionErrors = append(deletionErrors, fmt.Sprintf("Failed to delete %s from inbound %d: %v", client.Email, inbound.ID, err))
 					} else {
-						c.logger.Infof("Successfully deleted client %s from inbound %d", client.Email, inbound.ID)
+						clientLog.Info("Successfully deleted client from inbound")
 						emailDeleted = true
 						successfullyDeleted = true
 					}
@@ -287,29 +471,35 @@ func (c *Client) RemoveClients(ctx context.Context, emails []string) error {
 		}
 
 		if !emailDeleted {
-			c.logger.Warnf("Client %s not found in any inbound", email)
+			log.WithField("email", email).Warn("Client not found in any inbound")
 			deletionErrors = append(deletionErrors, fmt.Sprintf("Client %s not found in any inbound", email))
 		}
 	}
 
+	if !anyFound {
+		return fmt.Errorf("%w: %s", ErrClientNotFound, strings.Join(emails, ", "))
+	}
+
 	// Return error if no clients were successfully deleted
 	if !successfullyDeleted {
-		c.logger.Errorf("No clients were successfully deleted. Errors: %s", strings.Join(deletionErrors, "; "))
+		log.Errorf("No clients were successfully deleted. Errors: %s", strings.Join(deletionErrors, "; "))
 		return fmt.Errorf("failed to delete any clients: %s", strings.Join(deletionErrors, "; "))
 	}
 
 	// Log warnings for any errors that occurred
 	if len(deletionErrors) > 0 {
-		c.logger.Warnf("Some deletion errors occurred: %s", strings.Join(deletionErrors, "; "))
+		log.Warnf("Some deletion errors occurred: %s", strings.Join(deletionErrors, "; "))
 	}
 
-	c.logger.Infof("RemoveClients operation completed successfully")
+	log.Info("RemoveClients operation completed successfully")
 	return nil
 }
 
 // deleteClientFromInbound deletes a client from a specific inbound using the correct API endpoint
 func (c *Client) deleteClientFromInbound(ctx context.Context, cookies []*http.Cookie, inboundID int, clientUUID string) error {
-	c.logger.Debugf("Deleting client with UUID %s from inbound %d", clientUUID, inboundID)
+	log := c.logger.WithFields(logrus.Fields{"operation": "delete_client", "inbound_id": inboundID, "client_id": clientUUID})
+
+	log.Debug("Deleting client from inbound")
 
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
@@ -320,7 +510,7 @@ func (c *Client) deleteClientFromInbound(ctx context.Context, cookies []*http.Co
 		return fmt.Errorf("delete client request failed: %w", err)
 	}
 
-	c.logger.Debugf("Delete client response status: %d, body: %s", resp.StatusCode(), string(resp.Body()))
+	log.Debugf("Delete client response status: %d, body: %s", resp.StatusCode(), string(resp.Body()))
 
 	if resp.StatusCode() != http.StatusOK {
 		// If unauthorized, try to login again
@@ -328,7 +518,7 @@ func (c *Client) deleteClientFromInbound(ctx context.Context, cookies []*http.Co
 			c.cookieCache.Delete("session")
 			return c.deleteClientFromInbound(ctx, cookies, inboundID, clientUUID)
 		}
-		return fmt.Errorf("delete client failed with status code: %d, response: %s", resp.StatusCode(), string(resp.Body()))
+		return &APIError{Operation: "delete client", Status: resp.StatusCode(), Message: string(resp.Body())}
 	}
 
 	var apiResp XrayAPIResponse
@@ -337,27 +527,26 @@ func (c *Client) deleteClientFromInbound(ctx context.Context, cookies []*http.Co
 	}
 
 	if !apiResp.Success {
-		return fmt.Errorf("delete client failed: %s", apiResp.Msg)
+		return &APIError{Operation: "delete client", Status: http.StatusOK, Message: apiResp.Msg}
 	}
 
 	return nil
 }
 
-// extractClientUUID extracts the UUID from a client object
-// This method needs to be implemented based on the actual structure of the client object
-func (c *Client) extractClientUUID(client models.InboundClient, email string) string {
-	// Use client.ID as UUID, as in the working C# implementation
-	if client.ID != "" {
-		return client.ID
+// extractClientUUID returns the key the panel's delClient endpoint expects for the client:
+// its UUID on VLESS/VMess, its password on Trojan and its email on Shadowsocks
+func (c *Client) extractClientUUID(inbound models.Inbound, client models.InboundClient) string {
+	if key := inbound.ClientKey(client); key != "" {
+		return key
 	}
 
-	// Fallback to SubID if ID is empty
+	// Fallback to SubID if the key is empty
 	if client.SubID != "" {
 		return client.SubID
 	}
 
 	// If both are empty, use email as fallback
-	return email
+	return client.Email
 }
 
 // GetOnlineUsers gets the online users
@@ -383,7 +572,7 @@ func (c *Client) GetOnlineUsers(ctx context.Context) ([]string, error) {
 			c.cookieCache.Delete("session")
 			return c.GetOnlineUsers(ctx)
 		}
-		return nil, fmt.Errorf("get online users failed with status code: %d", resp.StatusCode())
+		return nil, &APIError{Operation: "get online users", Status: resp.StatusCode()}
 	}
 
 	var apiResp XrayAPIResponse
@@ -392,7 +581,7 @@ func (c *Client) GetOnlineUsers(ctx context.Context) ([]string, error) {
 	}
 
 	if !apiResp.Success {
-		return nil, fmt.Errorf("get online users failed: %s", apiResp.Msg)
+		return nil, &APIError{Operation: "get online users", Status: http.StatusOK, Message: apiResp.Msg}
 	}
 
 	// Convert obj to JSON and then unmarshal to string array
@@ -409,15 +598,101 @@ func (c *Client) GetOnlineUsers(ctx context.Context) ([]string, error) {
 	return onlineUsers, nil
 }
 
+// SetInboundEnabled turns a whole inbound on or off. The panel overwrites every field of
+// the inbound on update, so it is fetched as-is and sent back with only enable changed.
+func (c *Client) SetInboundEnabled(ctx context.Context, inboundID int, enable bool) error {
+	log := c.logger.WithFields(logrus.Fields{"operation": "set_inbound_enabled", "inbound_id": inboundID, "enable": enable})
+
+	if err := c.Login(ctx); err != nil {
+		return err
+	}
+
+	cookies, _ := c.cookieCache.Get("session")
+
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		SetCookies(cookies.([]*http.Cookie)).
+		Get(fmt.Sprintf("%s/xui/API/inbounds/get/%d", c.serverConfig.APIURL, inboundID))
+
+	if err != nil {
+		return fmt.Errorf("get inbound request failed: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		// If unauthorized, try to login again
+		if resp.StatusCode() == http.StatusUnauthorized {
+			c.cookieCache.Delete("session")
+			return c.SetInboundEnabled(ctx, inboundID, enable)
+		}
+		return &APIError{Operation: "get inbound", Status: resp.StatusCode(), Message: string(resp.Body())}
+	}
+
+	var getResp struct {
+		Success bool                   `json:"success"`
+		Msg     string                 `json:"msg"`
+		Obj     map[string]interface{} `json:"obj"`
+	}
+	if err := json.Unmarshal(resp.Body(), &getResp); err != nil {
+		return fmt.Errorf("failed to parse get inbound response: %w", err)
+	}
+	if !getResp.Success || getResp.Obj == nil {
+		return &APIError{Operation: "get inbound", Status: http.StatusOK, Message: getResp.Msg}
+	}
+
+	// Traffic stats are kept by the panel separately and aren't part of the update
+	inbound := getResp.Obj
+	delete(inbound, "clientStats")
+	inbound["enable"] = enable
+
+	log.Info("Updating inbound")
+
+	resp, err = c.httpClient.R().
+		SetContext(ctx).
+		SetCookies(cookies.([]*http.Cookie)).
+		SetBody(inbound).
+		Post(fmt.Sprintf("%s/xui/API/inbounds/update/%d", c.serverConfig.APIURL, inboundID))
+
+	if err != nil {
+		log.WithError(err).Error("Update inbound request failed")
+		return fmt.Errorf("update inbound request failed: %w", err)
+	}
+
+	log.Debugf("Update inbound response status: %d, body: %s", resp.StatusCode(), string(resp.Body()))
+
+	if resp.StatusCode() != http.StatusOK {
+		if resp.StatusCode() == http.StatusUnauthorized {
+			c.cookieCache.Delete("session")
+			return c.SetInboundEnabled(ctx, inboundID, enable)
+		}
+		log.WithField("status", resp.StatusCode()).Errorf("Update inbound failed, response body: %s", string(resp.Body()))
+		return &APIError{Operation: "update inbound", Status: resp.StatusCode()}
+	}
+
+	var apiResp XrayAPIResponse
+	if err := json.Unmarshal(resp.Body(), &apiResp); err != nil {
+		return fmt.Errorf("failed to parse update inbound response: %w", err)
+	}
+
+	if !apiResp.Success {
+		log.Errorf("Update inbound failed with message: %s", apiResp.Msg)
+		return &APIError{Operation: "update inbound", Status: http.StatusOK, Message: apiResp.Msg}
+	}
+
+	log.Info("Successfully updated inbound")
+	return nil
+}
+
 // ResetUserTraffic resets a user's traffic
 func (c *Client) ResetUserTraffic(ctx context.Context, inboundID int, email string) error {
+	log := c.logger.WithFields(logrus.Fields{"operation": "reset_traffic", "inbound_id": inboundID, "email": email})
+
 	if err := c.Login(ctx); err != nil {
 		return err
 	}
 
 	cookies, _ := c.cookieCache.Get("session")
 
-	c.logger.Debugf("Resetting traffic for client %s in inbound %d", email, inboundID)
+	log.Debug("Resetting client traffic")
 
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
@@ -428,7 +703,7 @@ func (c *Client) ResetUserTraffic(ctx context.Context, inboundID int, email stri
 		return fmt.Errorf("reset user traffic request failed: %w", err)
 	}
 
-	c.logger.Debugf("Reset traffic response status: %d, body: %s", resp.StatusCode(), string(resp.Body()))
+	log.Debugf("Reset traffic response status: %d, body: %s", resp.StatusCode(), string(resp.Body()))
 
 	if resp.StatusCode() != http.StatusOK {
 		// If unauthorized, try to login again
@@ -436,7 +711,7 @@ func (c *Client) ResetUserTraffic(ctx context.Context, inboundID int, email stri
 			c.cookieCache.Delete("session")
 			return c.ResetUserTraffic(ctx, inboundID, email)
 		}
-		return fmt.Errorf("reset user traffic failed with status code: %d, response: %s", resp.StatusCode(), string(resp.Body()))
+		return &APIError{Operation: "reset user traffic", Status: resp.StatusCode(), Message: string(resp.Body())}
 	}
 
 	var apiResp XrayAPIResponse
@@ -445,7 +720,7 @@ func (c *Client) ResetUserTraffic(ctx context.Context, inboundID int, email stri
 	}
 
 	if !apiResp.Success {
-		return fmt.Errorf("reset user traffic failed: %s", apiResp.Msg)
+		return &APIError{Operation: "reset user traffic", Status: http.StatusOK, Message: apiResp.Msg}
 	}
 
 	return nil
@@ -459,3 +734,77 @@ func (c *Client) GetSubscriptionURL(ctx context.Context, email string) (string,
 
 	return fmt.Sprintf("%s/sub/%s", c.serverConfig.SubURLPrefix, email), nil
 }
+
+// FetchSubscription downloads a subscription link to check that the panel serves it
+// and counts the configs it contains
+func (c *Client) FetchSubscription(ctx context.Context, subURL string) (*SubscriptionInfo, error) {
+	log := c.logger.WithFields(logrus.Fields{"operation": "fetch_subscription", "url": subURL})
+
+	resp, err := c.httpClient.R().
+		SetContext(ctx).
+		Get(subURL)
+
+	if err != nil {
+		return nil, fmt.Errorf("fetch subscription request failed: %w", err)
+	}
+
+	log.Debugf("Fetch subscription response status: %d", resp.StatusCode())
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, ErrSubscriptionNotFound
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, &APIError{Operation: "fetch subscription", Status: resp.StatusCode()}
+	}
+
+	info := parseSubscriptionUserinfo(resp.Header().Get("Subscription-Userinfo"))
+	info.ConfigCount = countSubscriptionConfigs(resp.Body())
+	return info, nil
+}
+
+// countSubscriptionConfigs counts the config links in a subscription body,
+// which the panel serves either base64-encoded or as plain text
+func countSubscriptionConfigs(body []byte) int {
+	content := strings.TrimSpace(string(body))
+	if decoded, err := base64.StdEncoding.DecodeString(content); err == nil {
+		content = string(decoded)
+	}
+
+	count := 0
+	for _, line := range strings.Split(content, "\n") {
+		if strings.Contains(line, "://") {
+			count++
+		}
+	}
+	return count
+}
+
+// parseSubscriptionUserinfo parses the "upload=..; download=..; total=..; expire=.." header
+func parseSubscriptionUserinfo(header string) *SubscriptionInfo {
+	info := &SubscriptionInfo{}
+
+	for _, part := range strings.Split(header, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch key {
+		case "upload":
+			info.Upload = n
+		case "download":
+			info.Download = n
+		case "total":
+			info.Total = n
+		case "expire":
+			info.Expire = n
+		}
+	}
+
+	return info
+}
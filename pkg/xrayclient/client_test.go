@@ -0,0 +1,117 @@
+package xrayclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"xui-tg-admin/internal/config"
+)
+
+// mockPanel is an X-UI panel serving fixed inbounds and recording delete requests
+type mockPanel struct {
+	inbounds []map[string]interface{}
+
+	mu      sync.Mutex
+	deleted []string
+}
+
+func (p *mockPanel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/login":
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "test"})
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	case r.URL.Path == "/xui/API/inbounds":
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "obj": p.inbounds})
+	case strings.Contains(r.URL.Path, "/delClient/"):
+		p.mu.Lock()
+		p.deleted = append(p.deleted, strings.TrimPrefix(r.URL.Path, "/xui/API/inbounds/"))
+		p.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// testInbound builds an inbound as the panel lists it, with its clients in the settings JSON
+func testInbound(t *testing.T, id int, protocol string, clients ...map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	settings, err := json.Marshal(map[string]interface{}{"clients": clients})
+	if err != nil {
+		t.Fatalf("failed to marshal settings: %v", err)
+	}
+	return map[string]interface{}{
+		"id":       id,
+		"enable":   true,
+		"protocol": protocol,
+		"settings": string(settings),
+	}
+}
+
+func newTestClient(t *testing.T, panel http.Handler) *Client {
+	t.Helper()
+	server := httptest.NewServer(panel)
+	t.Cleanup(server.Close)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewClient(config.ServerConfig{APIURL: server.URL, User: "admin", Password: "admin"}, logger)
+}
+
+func TestRemoveClientsUsesProtocolKey(t *testing.T) {
+	panel := &mockPanel{inbounds: []map[string]interface{}{
+		testInbound(t, 1, "vless",
+			map[string]interface{}{"id": "11111111-1111-4111-8111-111111111111", "email": "alice-1"},
+			map[string]interface{}{"id": "99999999-9999-4999-8999-999999999999", "email": "bob-1"},
+		),
+		testInbound(t, 2, "trojan",
+			map[string]interface{}{"id": "22222222-2222-4222-8222-222222222222", "email": "alice-2", "password": "trojan-secret"},
+		),
+		testInbound(t, 3, "trojan",
+			map[string]interface{}{"id": "33333333-3333-4333-8333-333333333333", "email": "alice-3"},
+		),
+		testInbound(t, 4, "shadowsocks",
+			map[string]interface{}{"email": "alice-4", "password": "ss-secret"},
+		),
+	}}
+	client := newTestClient(t, panel)
+
+	if err := client.RemoveClients(context.Background(), []string{"alice"}); err != nil {
+		t.Fatalf("RemoveClients returned error: %v", err)
+	}
+
+	want := []string{
+		"1/delClient/11111111-1111-4111-8111-111111111111",
+		"2/delClient/trojan-secret",
+		"3/delClient/33333333-3333-4333-8333-333333333333",
+		"4/delClient/alice-4",
+	}
+	sort.Strings(panel.deleted)
+	if strings.Join(panel.deleted, "\n") != strings.Join(want, "\n") {
+		t.Errorf("deleted %v, want %v", panel.deleted, want)
+	}
+}
+
+func TestRemoveClientsNotFound(t *testing.T) {
+	panel := &mockPanel{inbounds: []map[string]interface{}{
+		testInbound(t, 1, "vless", map[string]interface{}{"id": "11111111-1111-4111-8111-111111111111", "email": "bob-1"}),
+	}}
+	client := newTestClient(t, panel)
+
+	err := client.RemoveClients(context.Background(), []string{"alice"})
+	if !errors.Is(err, ErrClientNotFound) {
+		t.Fatalf("RemoveClients of a missing member = %v, want ErrClientNotFound", err)
+	}
+	if len(panel.deleted) != 0 {
+		t.Errorf("deleted %v, want nothing", panel.deleted)
+	}
+}